@@ -499,7 +499,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Estado de salud con recomendaciones. Requiere JWT.",
+                "description": "Estado de salud con recomendaciones y estado de los circuit breakers de dependencias (Firestore, DynamoDB, Postgres, GCS). Requiere JWT.",
                 "produces": [
                     "application/json"
                 ],
@@ -3,16 +3,24 @@ package main
 import (
 	"backend-yonathan/src/api/handlers"
 	"backend-yonathan/src/api/services"
-	"backend-yonathan/src/config"
 	"backend-yonathan/src/pkg/apiresponse"
 	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/resilience"
 	"backend-yonathan/src/pkg/telemetry"
 	"backend-yonathan/src/repository"
-	dynamoRepo "backend-yonathan/src/repository/dynamodb"
-	firestoreRepo "backend-yonathan/src/repository/firestore"
-	jsonRepo "backend-yonathan/src/repository/json"
+	cachedrepo "backend-yonathan/src/repository/cached"
+	_ "backend-yonathan/src/repository/dynamodb"
+	encryptedrepo "backend-yonathan/src/repository/encrypted"
+	_ "backend-yonathan/src/repository/firestore"
+	_ "backend-yonathan/src/repository/json"
+	_ "backend-yonathan/src/repository/memory"
+	resilientrepo "backend-yonathan/src/repository/resilient"
+	_ "backend-yonathan/src/repository/sql"
+	tenantrepo "backend-yonathan/src/repository/tenant"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -44,29 +52,96 @@ func init() {
 	_ = godotenv.Load()
 }
 
-func buildRepositories() (repository.UserRepository, repository.ExperienceRepository) {
-	switch strings.ToLower(os.Getenv("DB_PROVIDER")) {
-	case "dynamodb":
-		client, err := config.ConfigAWS()
-		if err != nil {
-			log.Fatalf("AWS config error: %v", err)
-		}
-		return dynamoRepo.NewUserRepository(client), jsonRepo.NewExperienceRepository()
-	case "firestore":
-		client, err := config.ConfigFirestore()
-		if err != nil {
-			log.Fatalf("Firestore config error: %v", err)
+// buildRepositories opens the stores configured by the *_STORE variables,
+// falling back to DB_PROVIDER for stores left unset, calls cloud stores
+// through retries and a circuit breaker, encrypts sensitive user fields when
+// USER_ENCRYPTION_KEY_FILE is set, and wraps experiences in a
+// read-through cache unless EXPERIENCES_CACHE_TTL_SECONDS is 0. With
+// CHANGE_FEED_SOURCE=firestore it also starts the snapshot listener that
+// feeds the change stream and drops the cache on remote writes.
+func buildRepositories() (repository.Repositories, error) {
+	cfg, err := repository.ConfigFromEnv()
+	if err != nil {
+		return repository.Repositories{}, err
+	}
+	log.Printf("[repository] users=%s experiences=%s skills=%s tag_aliases=%s", cfg.Users, cfg.Experiences, cfg.Skills, cfg.TagAliases)
+	repos, err := repository.Open(context.Background(), cfg)
+	if err != nil {
+		return repository.Repositories{}, err
+	}
+	var watcher repository.ExperienceWatcher
+	if constants.ChangeFeedSource() == constants.ChangeFeedSourceFirestore {
+		w, ok := repos.Experiences.(repository.ExperienceWatcher)
+		if !ok {
+			return repository.Repositories{}, fmt.Errorf("CHANGE_FEED_SOURCE=firestore requires a firestore experiences store, got %q", cfg.Experiences)
 		}
-		return firestoreRepo.NewUserRepository(client), firestoreRepo.NewExperienceRepository(client)
-	case "json":
-		return jsonRepo.NewUserRepository(), jsonRepo.NewExperienceRepository()
-	default:
-		log.Fatalf("DB_PROVIDER no configurado o no reconocido. Valores validos: dynamodb, firestore, json")
-		return nil, nil
+		watcher = w
+	}
+	if dependency := cloudDependency(cfg.Experiences); dependency != "" {
+		repos.Experiences = resilientrepo.NewExperienceRepository(repos.Experiences, resilientrepo.Options{
+			Breaker: resilience.BreakerFromEnv(dependency),
+			Policy:  resilience.PolicyFromEnv(),
+		})
+	}
+	if dependency := cloudDependency(cfg.Users); dependency != "" {
+		repos.Users = resilientrepo.NewUserRepository(repos.Users, resilientrepo.Options{
+			Breaker: resilience.BreakerFromEnv(dependency),
+			Policy:  resilience.PolicyFromEnv(),
+		})
+	}
+	if cipher, fields, err := userEncryptionFromEnv(); err != nil {
+		return repository.Repositories{}, err
+	} else if cipher != nil {
+		log.Printf("[repository] user encryption key=%s fields=%s", cipher.CurrentKeyID(), strings.Join(fields, ","))
+		repos.Users = encryptedrepo.NewUserRepository(repos.Users, cipher, fields)
+	}
+	var invalidate func()
+	if ttl := constants.ExperienceCacheTTL(); ttl > 0 {
+		cached := cachedrepo.NewExperienceRepository(repos.Experiences, cachedrepo.Options{
+			TTL:         ttl,
+			MaxItems:    constants.ExperienceCacheMaxItems(),
+			LoadTimeout: constants.LongRequestTimeout(),
+		})
+		invalidate = cached.(interface{ Invalidate() }).Invalidate
+		repos.Experiences = cached
+	}
+	if constants.MultiTenant() {
+		log.Printf("[repository] multi-tenant default_tenant=%q", constants.DefaultTenantID())
+		repos.Experiences = tenantrepo.NewExperienceRepository(repos.Experiences, constants.DefaultTenantID())
+		repos.Skills = tenantrepo.NewSkillRepository(repos.Skills, constants.DefaultTenantID())
+		repos.TagAliases = tenantrepo.NewTagAliasRepository(repos.TagAliases, constants.DefaultTenantID())
 	}
+	if watcher != nil {
+		log.Printf("[change-feed] source=firestore")
+		services.StartChangeFeedWatcher(context.Background(), watcher, invalidate)
+	}
+	return repos, nil
+}
+
+// cloudDependency returns the breaker name of a store reached over the
+// network ("firestore", "dynamodb", "postgres"), or "" for local stores.
+func cloudDependency(dsn string) string {
+	parsed, err := repository.ParseDSN(dsn)
+	if err != nil {
+		return ""
+	}
+	switch parsed.Scheme {
+	case "firestore", "dynamodb":
+		return parsed.Scheme
+	case "postgres", "postgresql":
+		return "postgres"
+	}
+	return ""
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(context.Background(), os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rotate-user-keys" {
+		os.Exit(runRotateUserKeys(context.Background(), os.Args[2:], os.Stdout))
+	}
+
 	app := fiber.New(fiber.Config{
 		TrustProxy:  true,
 		ProxyHeader: fiber.HeaderXForwardedFor,
@@ -90,9 +165,9 @@ func main() {
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     strings.Split(allowedOrigins, ","),
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
-		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "If-Match", "If-None-Match", "Last-Event-ID"},
+		ExposeHeaders:    []string{"X-Request-ID", "ETag"},
 		AllowCredentials: true,
 	}))
 
@@ -143,9 +218,15 @@ func main() {
 		return err
 	})
 
-	userRepo, expRepo := buildRepositories()
-	services.SeedAdminUser(userRepo)
-	handlers.SetupRoutes(app, userRepo, expRepo)
+	repos, err := buildRepositories()
+	if err != nil {
+		log.Fatalf("Configuracion de almacenamiento invalida: %v", err)
+	}
+	if err := services.LoadContentRules(); err != nil {
+		log.Fatalf("Reglas de validacion invalidas: %v", err)
+	}
+	services.SeedAdminUser(repos.Users)
+	handlers.SetupRoutes(app, repos)
 	app.Get("/swagger/*", swaggo.HandlerDefault)
 
 	port := os.Getenv("PORT")
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/migrate"
+)
+
+const migrateUsage = `Uso: main migrate --to DSN [--from DSN] [opciones]
+
+Copia usuarios y experiencias de un almacenamiento a otro (upsert por ID) y
+verifica el resultado comparando cantidades y hashes de contenido.
+
+DSN: file:./data, firestore://proyecto, dynamodb://tabla, sqlite:///ruta.db,
+postgres:, memory:./seed.json. Sin --from se usan USERS_STORE y
+EXPERIENCES_STORE (o DB_PROVIDER).
+
+Opciones:
+`
+
+// runMigrate implements the migrate subcommand and returns its exit code:
+// 0 on success, 1 when the migration or its verification failed and 2 on
+// invalid arguments.
+func runMigrate(ctx context.Context, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", "", "DSN de origen para usuarios y experiencias")
+	to := flags.String("to", "", "DSN de destino para usuarios y experiencias")
+	fromUsers := flags.String("from-users", "", "DSN de origen de usuarios (reemplaza --from)")
+	fromExperiences := flags.String("from-experiences", "", "DSN de origen de experiencias (reemplaza --from)")
+	toUsers := flags.String("to-users", "", "DSN de destino de usuarios (reemplaza --to)")
+	toExperiences := flags.String("to-experiences", "", "DSN de destino de experiencias (reemplaza --to)")
+	dryRun := flags.Bool("dry-run", false, "muestra lo que se haria sin escribir en el destino")
+	skipVerify := flags.Bool("skip-verify", false, "omite la verificacion final")
+	every := flags.Int("progress", 50, "imprime el progreso cada N items")
+	flags.Usage = func() {
+		fmt.Fprint(out, migrateUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	source := repository.Config{Users: firstNonEmpty(*fromUsers, *from), Experiences: firstNonEmpty(*fromExperiences, *from)}
+	for _, entry := range []struct {
+		store  repository.Store
+		target *string
+	}{{repository.StoreUsers, &source.Users}, {repository.StoreExperiences, &source.Experiences}} {
+		if *entry.target != "" {
+			continue
+		}
+		dsn, err := repository.StoreFromEnv(entry.store)
+		if err != nil {
+			fmt.Fprintf(out, "Origen no configurado: %v\n", err)
+			return 2
+		}
+		*entry.target = dsn
+	}
+	target := repository.Config{Users: firstNonEmpty(*toUsers, *to), Experiences: firstNonEmpty(*toExperiences, *to)}
+	if target.Users == "" || target.Experiences == "" {
+		fmt.Fprintln(out, "Falta el destino: usa --to o --to-users y --to-experiences")
+		flags.Usage()
+		return 2
+	}
+	if source.Users == target.Users && source.Experiences == target.Experiences {
+		fmt.Fprintln(out, "El origen y el destino son el mismo almacenamiento")
+		return 2
+	}
+
+	sourceStores, err := openMigrationStores(ctx, source)
+	if err != nil {
+		fmt.Fprintf(out, "No se pudo abrir el origen: %v\n", err)
+		return 1
+	}
+	targetStores, err := openMigrationStores(ctx, target)
+	if err != nil {
+		fmt.Fprintf(out, "No se pudo abrir el destino: %v\n", err)
+		return 1
+	}
+
+	mode := ""
+	if *dryRun {
+		mode = " (dry-run, sin escribir)"
+	}
+	fmt.Fprintf(out, "Migrando usuarios %s -> %s y experiencias %s -> %s%s\n",
+		source.Users, target.Users, source.Experiences, target.Experiences, mode)
+
+	report, err := migrate.Run(ctx, sourceStores, targetStores, migrate.Options{DryRun: *dryRun, Progress: out, ProgressEvery: *every})
+	exitCode := 0
+	if err != nil {
+		fmt.Fprintf(out, "Migracion con errores: %v\n", err)
+		if !errors.Is(err, migrate.ErrFailedItems) {
+			return 1
+		}
+		exitCode = 1
+	}
+	for _, entry := range []struct {
+		store  repository.Store
+		counts migrate.Counts
+	}{{repository.StoreUsers, report.Users}, {repository.StoreExperiences, report.Experiences}} {
+		fmt.Fprintf(out, "%s: %d en origen, %d creados, %d actualizados, %d sin cambios, %d fallidos\n",
+			entry.store, entry.counts.Source, entry.counts.Created, entry.counts.Updated, entry.counts.Unchanged, entry.counts.Failed)
+	}
+
+	if *dryRun || *skipVerify {
+		return exitCode
+	}
+	verifications, err := migrate.Verify(ctx, sourceStores, targetStores)
+	if err != nil {
+		fmt.Fprintf(out, "No se pudo verificar: %v\n", err)
+		return 1
+	}
+	for _, v := range verifications {
+		status := "OK"
+		if !v.OK() {
+			status = "ERROR"
+			exitCode = 1
+		}
+		fmt.Fprintf(out, "verificacion %s: %s (origen %d, destino %d)\n", v.Store, status, v.SourceCount, v.TargetCount)
+		printIDs(out, "  faltan", v.Missing)
+		printIDs(out, "  distintos", v.Different)
+		printIDs(out, "  solo en destino", v.Extra)
+	}
+	return exitCode
+}
+
+// openMigrationStores opens the users and experiences stores of cfg without
+// the caches the server adds.
+func openMigrationStores(ctx context.Context, cfg repository.Config) (migrate.Stores, error) {
+	users, err := repository.OpenUsers(ctx, cfg.Users)
+	if err != nil {
+		return migrate.Stores{}, err
+	}
+	experiences, err := repository.OpenExperiences(ctx, cfg.Experiences)
+	if err != nil {
+		return migrate.Stores{}, err
+	}
+	return migrate.Stores{Users: users, Experiences: experiences}, nil
+}
+
+func printIDs(out io.Writer, label string, ids []string) {
+	if len(ids) > 0 {
+		fmt.Fprintf(out, "%s (%d): %s\n", label, len(ids), strings.Join(ids, ", "))
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/fieldcrypto"
+	"backend-yonathan/src/repository"
+	encryptedrepo "backend-yonathan/src/repository/encrypted"
+)
+
+const rotateUserKeysUsage = `Uso: main rotate-user-keys [--users DSN] [--dry-run]
+
+Cifra los usuarios guardados en texto plano y vuelve a cifrar con la clave
+actual (o con los campos de USER_ENCRYPTED_FIELDS) los cifrados con otra
+clave de USER_ENCRYPTION_KEY_FILE. Cuando termina sin fallos, las claves
+anteriores pueden quitarse del archivo. Sin --users se usa USERS_STORE (o
+DB_PROVIDER).
+
+Opciones:
+`
+
+// userEncryptionFromEnv returns the cipher and fields configured by
+// USER_ENCRYPTION_KEY_FILE and USER_ENCRYPTED_FIELDS, or a nil cipher when
+// user encryption is disabled.
+func userEncryptionFromEnv() (*fieldcrypto.Cipher, []string, error) {
+	path := constants.UserEncryptionKeyFile()
+	if path == "" {
+		return nil, nil, nil
+	}
+	keys, err := fieldcrypto.LoadKeyFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields, err := encryptedrepo.ParseFields(constants.UserEncryptedFields())
+	if err != nil {
+		return nil, nil, err
+	}
+	return fieldcrypto.NewCipher(keys, keys.IndexKey()), fields, nil
+}
+
+// runRotateUserKeys implements the rotate-user-keys subcommand and returns
+// its exit code: 0 on success, 1 when some users could not be re-encrypted
+// and 2 on invalid arguments or configuration.
+func runRotateUserKeys(ctx context.Context, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("rotate-user-keys", flag.ContinueOnError)
+	flags.SetOutput(out)
+	users := flags.String("users", "", "DSN del almacenamiento de usuarios")
+	dryRun := flags.Bool("dry-run", false, "muestra lo que se haria sin escribir")
+	flags.Usage = func() {
+		fmt.Fprint(out, rotateUserKeysUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cipher, fields, err := userEncryptionFromEnv()
+	if err != nil {
+		fmt.Fprintf(out, "Claves de cifrado invalidas: %v\n", err)
+		return 2
+	}
+	if cipher == nil {
+		fmt.Fprintln(out, "Falta USER_ENCRYPTION_KEY_FILE")
+		return 2
+	}
+	dsn := *users
+	if dsn == "" {
+		if dsn, err = repository.StoreFromEnv(repository.StoreUsers); err != nil {
+			fmt.Fprintf(out, "Usuarios no configurados: %v\n", err)
+			return 2
+		}
+	}
+	raw, err := repository.OpenUsers(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(out, "No se pudo abrir %s: %v\n", dsn, err)
+		return 1
+	}
+	store, ok := raw.(encryptedrepo.Store)
+	if !ok {
+		fmt.Fprintf(out, "El almacenamiento %s no permite listar usuarios\n", dsn)
+		return 1
+	}
+
+	mode := ""
+	if *dryRun {
+		mode = " (dry-run, sin escribir)"
+	}
+	fmt.Fprintf(out, "Cifrando usuarios de %s con la clave %s%s\n", dsn, cipher.CurrentKeyID(), mode)
+	report, err := encryptedrepo.Rotate(ctx, store, cipher, fields, *dryRun, out)
+	fmt.Fprintf(out, "usuarios: %d en total, %d cifrados, %d recifrados, %d sin cambios, %d fallidos\n",
+		report.Total, report.Encrypted, report.Resealed, report.Unchanged, report.Failed)
+	if err != nil {
+		if !errors.Is(err, encryptedrepo.ErrFailedUsers) {
+			fmt.Fprintf(out, "No se pudo completar: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
@@ -6,16 +6,25 @@ import (
 	"backend-yonathan/src/pkg/apiresponse"
 	"backend-yonathan/src/pkg/constants"
 	"backend-yonathan/src/repository"
+	"context"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/limiter"
 )
 
-func SetupRoutes(app *fiber.App, userRepo repository.UserRepository, expRepo repository.ExperienceRepository) {
-	auth := services.NewAuthService(userRepo)
-	exp := services.NewExperienceService(expRepo)
-	skill := services.NewSkillService(expRepo)
+func SetupRoutes(app *fiber.App, repos repository.Repositories) {
+	auth := services.NewAuthService(repos.Users)
+	exp := services.NewExperienceService(repos.Experiences)
+	skill := services.NewSkillService(repos.Experiences)
+	catalog := services.NewSkillCatalogService(repos.Skills, repos.Experiences)
+	archive := services.NewArchiveService(repos.Experiences, repos.Skills)
+	tags := services.NewTagService(repos.Experiences, repos.TagAliases)
+	tags.LoadAliases(context.Background())
+	feeds := services.NewFeedService(repos.Experiences)
+	seo := services.NewSEOService(repos.Experiences)
+	stats := services.NewStatsService(repos.Experiences)
 
 	rateLimitReached := func(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusTooManyRequests,
@@ -38,16 +47,56 @@ func SetupRoutes(app *fiber.App, userRepo repository.UserRepository, expRepo rep
 		LimitReached: rateLimitReached,
 	})
 
+	// Request deadlines; change feed streams stay open on their own schedule.
+	app.Use(jwtMiddleware.RequestDeadline(jwtMiddleware.DeadlineConfig{
+		Timeout: constants.RequestTimeout(),
+		Routes: map[string]time.Duration{
+			"/api/private/upload-image":           constants.LongRequestTimeout(),
+			"/api/private/export":                 constants.LongRequestTimeout(),
+			"/api/private/import":                 constants.LongRequestTimeout(),
+			"/api/private/skills/catalog/migrate": constants.LongRequestTimeout(),
+			"/api/tools/dns/propagation":          constants.LongRequestTimeout(),
+			"/api/tools/dns/blacklist":            constants.LongRequestTimeout(),
+		},
+		Next: func(c fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
+	}))
+	// Requests that hit an open circuit breaker respond 503.
+	app.Use(jwtMiddleware.FailFast())
+
 	// --- Public routes ---
 
+	app.Get("/sitemap.xml", seo.Sitemap)
+
 	public := app.Group("/api")
 	public.Get("/health", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
 	public.Post("/login", authLimiter, auth.Login)
 	public.Post("/register", authLimiter, auth.Register)
 	public.Post("/logout", auth.Logout)
 	public.Post("/contact", authLimiter, services.SubmitContact)
-	public.Get("/experiences", exp.ListPublicExperiences)
-	public.Get("/skills", skill.ListPublicSkills)
+	portfolio := func(router fiber.Router) {
+		router.Get("/experiences", exp.ListPublicExperiences)
+		router.Get("/experiences/stream", services.StreamPublicChanges)
+		router.Get("/experiences/:id/jsonld", seo.ExperienceJSONLD)
+		router.Get("/experiences/:id/related", exp.ListRelatedExperiences)
+		router.Get("/skills", skill.ListPublicSkills)
+		router.Get("/skills/catalog", catalog.ListPublicCatalog)
+		router.Get("/stats", stats.GetPublicStats)
+		router.Get("/tags", tags.ListPublicTags)
+		router.Get("/feed.rss", feeds.RSSFeed)
+		router.Get("/feed.atom", feeds.AtomFeed)
+		router.Get("/feed.json", feeds.JSONFeed)
+	}
+	portfolio(public)
+
+	// With MULTI_TENANT the public portfolio of a tenant can also be read by
+	// path; the routes above resolve it from the Host header instead.
+	if constants.MultiTenant() {
+		tenant := public.Group("/tenants/:tenant")
+		portfolio(tenant)
+		tenant.Get("/sitemap.xml", seo.Sitemap)
+	}
 
 	// --- Tools (public, no auth) ---
 
@@ -69,15 +118,40 @@ func SetupRoutes(app *fiber.App, userRepo repository.UserRepository, expRepo rep
 
 	private.Get("/experiences", exp.ListAllExperiences)
 	private.Post("/experiences", exp.CreateExperience)
+	private.Get("/experiences/stream", services.StreamAllChanges)
+	private.Put("/experiences/order", exp.ReorderExperiences)
+	private.Post("/experiences/batch", exp.BatchExperiences)
+	private.Post("/experiences/validate", exp.ValidateExperience)
+	private.Get("/experiences/:id", exp.GetExperience)
 	private.Put("/experiences/:id", exp.UpdateExperience)
+	private.Patch("/experiences/:id", exp.PatchExperience)
+	private.Put("/experiences/:id/related", exp.SetRelatedExperiences)
 	private.Delete("/experiences/:id", exp.DeleteExperience)
 	private.Post("/upload-image", services.UploadImage)
 
 	private.Get("/skills", skill.ListAllSkills)
 	private.Post("/skills", skill.CreateSkill)
+	private.Post("/skills/validate", skill.ValidateSkill)
 	private.Put("/skills/:id", skill.UpdateSkill)
+	private.Patch("/skills/:id", skill.PatchSkill)
 	private.Delete("/skills/:id", skill.DeleteSkill)
 
+	private.Get("/skills/catalog", catalog.ListCatalog)
+	private.Post("/skills/catalog", catalog.CreateCatalogSkill)
+	private.Post("/skills/catalog/migrate", catalog.MigrateCatalog)
+	private.Put("/skills/catalog/:id", catalog.UpdateCatalogSkill)
+	private.Delete("/skills/catalog/:id", catalog.DeleteCatalogSkill)
+
+	private.Get("/tags", tags.ListTags)
+	private.Post("/tags/rename", tags.RenameTag)
+	private.Post("/tags/merge", tags.MergeTags)
+	private.Get("/tags/aliases", tags.ListTagAliases)
+	private.Put("/tags/aliases/:alias", tags.SaveTagAlias)
+	private.Delete("/tags/aliases/:alias", tags.DeleteTagAlias)
+
+	private.Get("/export", archive.ExportContent)
+	private.Post("/import", archive.ImportContent)
+
 	private.Get("/ops/metrics", services.GetOpsMetrics)
 	private.Get("/ops/alerts", services.GetOpsAlerts)
 	private.Get("/ops/health", services.GetOpsHealth)
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
 	"backend-yonathan/src/repository/memory"
 	"net/http"
 	"net/http/httptest"
@@ -14,11 +15,15 @@ func TestAuthRateLimiter(t *testing.T) {
 	app := fiber.New()
 	
 	// Create mock repositories
-	userRepo := memory.NewUserRepository()
-	expRepo := memory.NewExperienceRepository()
+	repos := repository.Repositories{
+		Users:       memory.NewUserRepository(),
+		Experiences: memory.NewExperienceRepository(),
+		Skills:      memory.NewSkillRepository(),
+		TagAliases:  memory.NewTagAliasRepository(),
+	}
 	
 	// Setup routes exactly as in production
-	SetupRoutes(app, userRepo, expRepo)
+	SetupRoutes(app, repos)
 
 	// Max limit for auth is constants.RateLimitAuthMax
 	limit := constants.RateLimitAuthMax
@@ -0,0 +1,50 @@
+package jwtMiddleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend-yonathan/src/pkg/apiresponse"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DeadlineConfig configures RequestDeadline.
+type DeadlineConfig struct {
+	// Timeout applies to every request; zero disables the deadline.
+	Timeout time.Duration
+	// Routes overrides Timeout for exact request paths.
+	Routes map[string]time.Duration
+	// Next skips the deadline for requests it returns true for.
+	Next func(c fiber.Ctx) bool
+}
+
+// RequestDeadline gives the request context (c.Context) a deadline, so that
+// the repository and network calls made with it are cancelled when it
+// passes. A request that fails after its deadline responds 504 instead of
+// the error the handler produced.
+func RequestDeadline(cfg DeadlineConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+		timeout := cfg.Timeout
+		if override, ok := cfg.Routes[c.Path()]; ok {
+			timeout = override
+		}
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context(), timeout)
+		defer cancel()
+		c.SetContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError) {
+			return apiresponse.Error(c, fiber.StatusGatewayTimeout, "request_timeout", "La solicitud excedio el tiempo limite", nil)
+		}
+		return err
+	}
+}
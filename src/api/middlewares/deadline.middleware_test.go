@@ -0,0 +1,56 @@
+package jwtMiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-yonathan/src/pkg/apiresponse"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// slowHandler waits for the request context like a repository call would and
+// fails with the error it returns.
+func slowHandler(c fiber.Ctx) error {
+	select {
+	case <-c.Context().Done():
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_failed", "No se pudo cargar", c.Context().Err().Error())
+	case <-time.After(200 * time.Millisecond):
+		return c.SendStatus(fiber.StatusOK)
+	}
+}
+
+func TestRequestDeadlineRespondsGatewayTimeout(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestDeadline(DeadlineConfig{
+		Timeout: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"/slow/long": time.Second},
+		Next:    func(c fiber.Ctx) bool { return strings.HasSuffix(c.Path(), "/stream") },
+	}))
+	app.Get("/slow", slowHandler)
+	app.Get("/slow/long", slowHandler)
+	app.Get("/slow/stream", slowHandler)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if err != nil {
+		t.Fatalf("unexpected app test error: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != fiber.StatusGatewayTimeout || !strings.Contains(string(body), "request_timeout") {
+		t.Fatalf("expected 504 request_timeout, got %d %s", res.StatusCode, body)
+	}
+
+	for _, path := range []string{"/slow/long", "/slow/stream"} {
+		res, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("unexpected app test error: %v", err)
+		}
+		if res.StatusCode != fiber.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, res.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package jwtMiddleware
+
+import (
+	"math"
+	"strconv"
+
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/resilience"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// FailFast records the calls of a request that an open circuit breaker
+// rejects. A request that fails after such a rejection responds 503 with
+// Retry-After instead of the error the handler produced, so clients back off
+// until the dependency recovers.
+func FailFast() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx := resilience.WithRejections(c.Context())
+		c.SetContext(ctx)
+
+		err := c.Next()
+		rejection, rejected := resilience.Rejected(ctx)
+		if rejected && (err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError) {
+			seconds := int(math.Ceil(rejection.RetryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			return apiresponse.Error(c, fiber.StatusServiceUnavailable, "dependency_unavailable",
+				"Servicio temporalmente no disponible, reintente mas tarde", fiber.Map{"dependency": rejection.Dependency})
+		}
+		return err
+	}
+}
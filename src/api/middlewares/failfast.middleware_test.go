@@ -0,0 +1,56 @@
+package jwtMiddleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/resilience"
+
+	"github.com/gofiber/fiber/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFailFastRespondsServiceUnavailable(t *testing.T) {
+	breaker := resilience.BreakerFor(t.Name(), resilience.BreakerConfig{FailureThreshold: 1, OpenTimeout: 90 * time.Second})
+	_ = resilience.Do(context.Background(), breaker, resilience.Policy{Attempts: 1}, func(context.Context) error {
+		return status.Error(codes.Unavailable, "down")
+	})
+
+	app := fiber.New()
+	app.Use(FailFast())
+	app.Get("/load", func(c fiber.Ctx) error {
+		err := resilience.Do(c.Context(), breaker, resilience.Policy{Attempts: 1}, func(context.Context) error { return nil })
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_failed", "No se pudo cargar", err.Error())
+	})
+	app.Get("/other", func(c fiber.Ctx) error {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_failed", "No se pudo cargar", errors.New("boom").Error())
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/load", nil))
+	if err != nil {
+		t.Fatalf("unexpected app test error: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != fiber.StatusServiceUnavailable || !strings.Contains(string(body), "dependency_unavailable") {
+		t.Fatalf("expected 503 dependency_unavailable, got %d %s", res.StatusCode, body)
+	}
+	if retryAfter := res.Header.Get(fiber.HeaderRetryAfter); retryAfter != "90" {
+		t.Fatalf("expected Retry-After 90, got %q", retryAfter)
+	}
+
+	res, err = app.Test(httptest.NewRequest(http.MethodGet, "/other", nil))
+	if err != nil {
+		t.Fatalf("unexpected app test error: %v", err)
+	}
+	if res.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("expected errors unrelated to breakers to keep 500, got %d", res.StatusCode)
+	}
+}
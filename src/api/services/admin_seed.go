@@ -47,7 +47,10 @@ func SeedAdminUser(userRepo repository.UserRepository) {
 		UserName: username,
 	}
 
-	if err := userRepo.SaveUser(ctx, admin); err != nil {
+	if _, err := userRepo.CreateUser(ctx, admin); errors.Is(err, repository.ErrConflict) {
+		// Another instance seeded the admin concurrently.
+		return
+	} else if err != nil {
 		log.Printf("[admin-seed] error saving admin user: %v", err)
 		return
 	}
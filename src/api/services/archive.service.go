@@ -0,0 +1,672 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/validation"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// downloadFromBucketFunc is injectable for tests. Default: downloadFromGCS.
+var downloadFromBucketFunc = downloadFromGCS
+
+// ArchiveService exports and imports the portfolio content (experiences,
+// catalog skills and referenced bucket images) as a versioned zip archive.
+type ArchiveService struct {
+	experiences repository.ExperienceRepository
+	skills      repository.SkillRepository
+}
+
+// NewArchiveService creates an ArchiveService backed by the given repositories.
+func NewArchiveService(experiences repository.ExperienceRepository, skills repository.SkillRepository) *ArchiveService {
+	return &ArchiveService{experiences: experiences, skills: skills}
+}
+
+// archiveManifest describes the content of an export archive.
+type archiveManifest struct {
+	Format            string         `json:"format"`
+	Version           int            `json:"version"`
+	ExportedAt        string         `json:"exportedAt"`
+	SourceImagePrefix string         `json:"sourceImagePrefix"`
+	Counts            map[string]int `json:"counts"`
+	Images            []archiveImage `json:"images"`
+	MissingImages     []string       `json:"missingImages"`
+}
+
+// archiveImage maps an archive entry to the bucket object it was read from.
+type archiveImage struct {
+	Object      string `json:"object"`
+	Entry       string `json:"entry"`
+	ContentType string `json:"contentType"`
+}
+
+// importDiff lists the IDs affected by an import, per outcome.
+type importDiff struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+	Deleted   []string `json:"deleted"`
+}
+
+// importItemError reports why an archive item was rejected.
+type importItemError struct {
+	Collection string                  `json:"collection"`
+	Index      int                     `json:"index"`
+	ID         string                  `json:"id"`
+	Reason     string                  `json:"reason"`
+	Errors     []validation.FieldError `json:"errors,omitempty"`
+}
+
+// importReport is the response of POST /api/private/import.
+type importReport struct {
+	DryRun      bool           `json:"dryRun"`
+	Mode        string         `json:"mode"`
+	Experiences importDiff     `json:"experiences"`
+	Skills      importDiff     `json:"skills"`
+	Images      map[string]int `json:"images"`
+}
+
+func newImportDiff() importDiff {
+	return importDiff{Created: []string{}, Updated: []string{}, Unchanged: []string{}, Deleted: []string{}}
+}
+
+// referencedObjects returns the sorted, de-duplicated bucket object paths
+// referenced by image URLs, bodies and skill icons.
+func referencedObjects(prefix string, experiences []models.Experience, skills []models.Skill) []string {
+	if prefix == "" {
+		return []string{}
+	}
+	seen := map[string]bool{}
+	add := func(rawURL string) {
+		if strings.HasPrefix(rawURL, prefix) {
+			seen[strings.TrimPrefix(stripQueryParams(rawURL), prefix)] = true
+		}
+	}
+	for _, exp := range experiences {
+		for _, u := range exp.ImageURLs {
+			add(u)
+		}
+		for _, u := range FindBodyImageURLs(exp.Body, prefix) {
+			add(u)
+		}
+	}
+	for _, skill := range skills {
+		add(skill.Icon)
+	}
+
+	objects := make([]string, 0, len(seen))
+	for object := range seen {
+		objects = append(objects, object)
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+func writeZipJSON(zw *zip.Writer, name string, value interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readZipFile(f *zip.File, maxBytes int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", f.Name, maxBytes)
+	}
+	return data, nil
+}
+
+// rewriteImagePrefix points URLs of the source bucket to the current bucket,
+// so an archive exported from another environment keeps working.
+func rewriteImagePrefix(value, from, to string) string {
+	if from == "" || to == "" || from == to {
+		return value
+	}
+	return strings.ReplaceAll(value, from, to)
+}
+
+// ExportContent godoc
+// @Summary      Exportar contenido
+// @Description  Genera un archivo zip versionado con manifest.json, experiences.json, skills.json y las imagenes del bucket referenciadas (images/...). Requiere JWT.
+// @Tags         Archive
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Success      200  {file}    file
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/export [get]
+func (s *ArchiveService) ExportContent(c fiber.Ctx) error {
+	ctx := requestContext(c)
+	experiences, err := s.experiences.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+	skills, err := s.skills.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
+	prefix := constants.GCSURLPrefix()
+	manifest := archiveManifest{
+		Format:            constants.ArchiveFormat,
+		Version:           constants.ArchiveFormatVersion,
+		ExportedAt:        time.Now().UTC().Format(time.RFC3339),
+		SourceImagePrefix: prefix,
+		Images:            []archiveImage{},
+		MissingImages:     []string{},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	bucket := constants.GCSBucketName()
+	for _, object := range referencedObjects(prefix, experiences, skills) {
+		data, contentType, err := downloadFromBucketFunc(ctx, bucket, object, constants.MaxImageUploadBytes)
+		if err != nil {
+			log.Printf("[archive] could not export image %s: %v", object, err)
+			manifest.MissingImages = append(manifest.MissingImages, object)
+			continue
+		}
+		entry := constants.ArchiveImagesDir + object
+		w, err := zw.Create(entry)
+		if err != nil {
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "export_failed", "No se pudo generar el archivo", err.Error())
+		}
+		if _, err := w.Write(data); err != nil {
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "export_failed", "No se pudo generar el archivo", err.Error())
+		}
+		manifest.Images = append(manifest.Images, archiveImage{Object: object, Entry: entry, ContentType: contentType})
+	}
+
+	manifest.Counts = map[string]int{
+		"experiences": len(experiences),
+		"skills":      len(skills),
+		"images":      len(manifest.Images),
+	}
+
+	for name, value := range map[string]interface{}{
+		constants.ArchiveManifestFile:    manifest,
+		constants.ArchiveExperiencesFile: experiences,
+		constants.ArchiveSkillsFile:      skills,
+	} {
+		if err := writeZipJSON(zw, name, value); err != nil {
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "export_failed", "No se pudo generar el archivo", err.Error())
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "export_failed", "No se pudo generar el archivo", err.Error())
+	}
+
+	c.Attachment("portfolio-export-" + time.Now().UTC().Format("20060102-150405") + ".zip")
+	c.Set(fiber.HeaderContentType, "application/zip")
+	return c.Send(buf.Bytes())
+}
+
+// ImportContent godoc
+// @Summary      Importar contenido
+// @Description  Importa un archivo generado por GET /api/private/export (multipart "file"). Cada item se valida con las mismas reglas que create/update; si alguno es invalido no se aplica nada. mode=merge (default) crea/actualiza por ID; mode=replace ademas elimina lo que no esta en el archivo. Las experiencias se escriben en un lote atomico si el almacenamiento lo soporta; si no, y para las skills, un fallo deja aplicado lo listado en details.context.applied. dryRun=true solo devuelve el diff. Requiere JWT.
+// @Tags         Archive
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file    formData  file    true   "Archivo zip exportado"
+// @Param        mode    query     string  false  "merge | replace"
+// @Param        dryRun  query     bool    false  "Solo calcular diff"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/import [post]
+func (s *ArchiveService) ImportContent(c fiber.Ctx) error {
+	mode := strings.ToLower(strings.TrimSpace(c.Query("mode", "merge")))
+	if mode != "merge" && mode != "replace" {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_mode", "mode debe ser merge o replace", nil)
+	}
+	dryRun := fiber.Query[bool](c, "dryRun")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "missing_file", "Falta el archivo en el campo 'file'", err.Error())
+	}
+	opened, err := file.Open()
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "read_file_failed", "No se pudo leer el archivo", err.Error())
+	}
+	defer opened.Close()
+	raw, err := io.ReadAll(opened)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "read_file_failed", "No se pudo leer el archivo", err.Error())
+	}
+
+	archive, err := readContentArchive(raw)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_archive", "Archivo de importacion invalido", err.Error())
+	}
+
+	ctx := requestContext(c)
+	experiences, skills, itemErrors := archive.validatedItems(ctx)
+	if len(itemErrors) > 0 {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_items", "El archivo contiene items invalidos", itemErrors)
+	}
+	currentExperiences, err := s.experiences.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+	currentSkills, err := s.skills.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
+	stored := make(map[string]models.Experience, len(currentExperiences))
+	for _, item := range currentExperiences {
+		stored[item.ID] = item
+	}
+	// Imported experiences belong to the importing tenant; existing ones keep
+	// their owner so that unchanged items compare equal.
+	tenant := tenantID(c)
+	for i := range experiences {
+		if current, ok := stored[experiences[i].ID]; ok {
+			experiences[i].TenantID = current.TenantID
+		} else {
+			experiences[i].TenantID = tenant
+		}
+	}
+
+	report := importReport{
+		DryRun:      dryRun,
+		Mode:        mode,
+		Experiences: diffByID(experienceIndex(currentExperiences), experienceIndex(experiences), mode),
+		Skills:      diffByID(skillIndex(currentSkills), skillIndex(skills), mode),
+		Images:      map[string]int{"uploaded": 0, "skipped": 0},
+	}
+
+	if dryRun {
+		report.Images["skipped"] = len(archive.images)
+		return apiresponse.Success(c, report)
+	}
+
+	applied, err := s.applyImport(ctx, report, stored, experiences, skills)
+	invalidatePublicStats()
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "import_failed", "No se pudo completar la importacion", fiber.Map{"error": err.Error(), "applied": applied})
+	}
+	report.Images = archive.uploadImages(ctx)
+
+	return apiresponse.Success(c, report)
+}
+
+// importApplied lists the IDs an import wrote before it failed.
+type importApplied struct {
+	Experiences []string `json:"experiences"`
+	Skills      []string `json:"skills"`
+}
+
+// applyImport writes the diff in report and reports each experience write to
+// the change feed. stored holds each current experience; its version is the
+// expected version of updates and deletes. Experiences are written in one
+// atomic batch when the store supports it; otherwise, and for skills, writes
+// are applied one by one and the returned importApplied lists those that
+// were applied before a failure.
+func (s *ArchiveService) applyImport(ctx context.Context, report importReport, stored map[string]models.Experience, experiences []models.Experience, skills []models.Skill) (importApplied, error) {
+	applied := importApplied{Experiences: []string{}, Skills: []string{}}
+	expByID := map[string]models.Experience{}
+	for _, exp := range experiences {
+		expByID[exp.ID] = exp
+	}
+	writes := make([]repository.ExperienceWrite, 0, len(report.Experiences.Created)+len(report.Experiences.Updated)+len(report.Experiences.Deleted))
+	for _, id := range report.Experiences.Created {
+		writes = append(writes, repository.ExperienceWrite{Kind: repository.WriteCreate, Experience: expByID[id]})
+	}
+	for _, id := range report.Experiences.Updated {
+		writes = append(writes, repository.ExperienceWrite{Kind: repository.WriteUpdate, Experience: expByID[id], ExpectedVersion: stored[id].Version})
+	}
+	for _, id := range report.Experiences.Deleted {
+		writes = append(writes, repository.ExperienceWrite{Kind: repository.WriteDelete, Experience: stored[id], ExpectedVersion: stored[id].Version})
+	}
+
+	if batcher, ok := s.experiences.(repository.BatchExperienceRepository); ok && len(writes) > 0 {
+		if err := batcher.ApplyBatch(ctx, writes); err != nil {
+			return applied, err
+		}
+		for _, w := range writes {
+			publishImportWrite(w, stored)
+			applied.Experiences = append(applied.Experiences, w.Experience.ID)
+		}
+	} else {
+		for _, w := range writes {
+			var err error
+			switch w.Kind {
+			case repository.WriteCreate:
+				err = s.experiences.Create(ctx, w.Experience)
+			case repository.WriteUpdate:
+				err = s.experiences.Update(ctx, w.Experience, w.ExpectedVersion)
+			case repository.WriteDelete:
+				err = s.experiences.Delete(ctx, w.Experience.ID, w.ExpectedVersion)
+				if errors.Is(err, repository.ErrNotFound) {
+					continue
+				}
+			}
+			if err != nil {
+				return applied, err
+			}
+			publishImportWrite(w, stored)
+			applied.Experiences = append(applied.Experiences, w.Experience.ID)
+		}
+	}
+
+	skillByID := map[string]models.Skill{}
+	for _, skill := range skills {
+		skillByID[skill.ID] = skill
+	}
+	for _, id := range report.Skills.Created {
+		if err := s.skills.Create(ctx, skillByID[id]); err != nil {
+			return applied, err
+		}
+		applied.Skills = append(applied.Skills, id)
+	}
+	for _, id := range report.Skills.Updated {
+		if err := s.skills.Update(ctx, skillByID[id]); err != nil {
+			return applied, err
+		}
+		applied.Skills = append(applied.Skills, id)
+	}
+	for _, id := range report.Skills.Deleted {
+		if err := s.skills.Delete(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return applied, err
+		}
+		applied.Skills = append(applied.Skills, id)
+	}
+	return applied, nil
+}
+
+// publishImportWrite reports an applied import write to the change feed.
+func publishImportWrite(w repository.ExperienceWrite, stored map[string]models.Experience) {
+	id := w.Experience.ID
+	switch w.Kind {
+	case repository.WriteCreate:
+		publishExperienceChange(repository.ChangeCreated, w.Experience, "")
+	case repository.WriteUpdate:
+		updated := w.Experience
+		updated.Version = w.ExpectedVersion + 1
+		publishExperienceChange(repository.ChangeUpdated, updated, stored[id].Visibility)
+	case repository.WriteDelete:
+		publishExperienceChange(repository.ChangeDeleted, stored[id], stored[id].Visibility)
+	}
+}
+
+// contentArchive is a parsed export archive.
+type contentArchive struct {
+	manifest    archiveManifest
+	experiences []models.Experience
+	skills      []models.Skill
+	images      map[string]*zip.File
+}
+
+func readContentArchive(raw []byte) (*contentArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	archive := &contentArchive{images: map[string]*zip.File{}}
+	readJSON := func(name string, target interface{}, required bool) error {
+		f, ok := files[name]
+		if !ok {
+			if required {
+				return fmt.Errorf("missing %s", name)
+			}
+			return nil
+		}
+		data, err := readZipFile(f, constants.BodyLimitDefault)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := readJSON(constants.ArchiveManifestFile, &archive.manifest, true); err != nil {
+		return nil, err
+	}
+	if archive.manifest.Format != constants.ArchiveFormat {
+		return nil, fmt.Errorf("unknown archive format %q", archive.manifest.Format)
+	}
+	if archive.manifest.Version < 1 || archive.manifest.Version > constants.ArchiveFormatVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", archive.manifest.Version)
+	}
+	if err := readJSON(constants.ArchiveExperiencesFile, &archive.experiences, true); err != nil {
+		return nil, err
+	}
+	if err := readJSON(constants.ArchiveSkillsFile, &archive.skills, false); err != nil {
+		return nil, err
+	}
+	if len(archive.experiences)+len(archive.skills) > constants.MaxImportItems {
+		return nil, fmt.Errorf("archive exceeds %d items", constants.MaxImportItems)
+	}
+
+	for _, img := range archive.manifest.Images {
+		f, ok := files[img.Entry]
+		if !ok || !strings.HasPrefix(img.Entry, constants.ArchiveImagesDir) {
+			continue
+		}
+		object := path.Clean(img.Object)
+		if !strings.HasPrefix(object, constants.StorageImagePrefix+"/") {
+			continue
+		}
+		archive.images[object] = f
+	}
+	return archive, nil
+}
+
+// validatedItems sanitizes every archive item and checks it against the
+// content rules, as the create/update endpoints do, and returns the rejected
+// ones.
+func (a *contentArchive) validatedItems(ctx context.Context) ([]models.Experience, []models.Skill, []importItemError) {
+	from := a.manifest.SourceImagePrefix
+	to := constants.GCSURLPrefix()
+	itemErrors := []importItemError{}
+	seen := map[string]bool{}
+
+	experiences := make([]models.Experience, 0, len(a.experiences))
+	for i, exp := range a.experiences {
+		if !validatePayloadID(exp.ID) || seen[exp.ID] {
+			itemErrors = append(itemErrors, importItemError{Collection: "experiences", Index: i, ID: exp.ID, Reason: "invalid_id"})
+			continue
+		}
+		seen[exp.ID] = true
+
+		imageURLs := make([]string, len(exp.ImageURLs))
+		for j, u := range exp.ImageURLs {
+			imageURLs[j] = rewriteImagePrefix(u, from, to)
+		}
+		payload := experiencePayload{
+			Title:      exp.Title,
+			Summary:    exp.Summary,
+			Body:       rewriteImagePrefix(exp.Body, from, to),
+			ImageURLs:  imageURLs,
+			Tags:       exp.Tags,
+			Visibility: exp.Visibility,
+		}
+		sanitizePayload(ctx, &payload)
+		if payload.Title == "" {
+			itemErrors = append(itemErrors, importItemError{Collection: "experiences", Index: i, ID: exp.ID, Reason: "missing_title"})
+			continue
+		}
+
+		exp.Title = payload.Title
+		exp.Summary = payload.Summary
+		exp.Body = payload.Body
+		exp.ImageURLs = payload.ImageURLs
+		exp.Tags = payload.Tags
+		exp.Visibility = payload.Visibility
+		collection := constants.ValidationCollectionExperiences
+		if isSkillExperience(exp) {
+			collection = constants.ValidationCollectionSkills
+		}
+		if errs := validateContent(collection, exp); len(errs) > 0 {
+			itemErrors = append(itemErrors, importItemError{Collection: "experiences", Index: i, ID: exp.ID, Reason: "validation_failed", Errors: errs})
+			continue
+		}
+		experiences = append(experiences, exp)
+	}
+
+	seen = map[string]bool{}
+	skills := make([]models.Skill, 0, len(a.skills))
+	for i, skill := range a.skills {
+		if !validatePayloadID(skill.ID) || seen[skill.ID] {
+			itemErrors = append(itemErrors, importItemError{Collection: "skills", Index: i, ID: skill.ID, Reason: "invalid_id"})
+			continue
+		}
+		seen[skill.ID] = true
+
+		payload := skillPayload{
+			Name:          skill.Name,
+			Category:      skill.Category,
+			Proficiency:   skill.Proficiency,
+			Years:         skill.Years,
+			Icon:          rewriteImagePrefix(skill.Icon, from, to),
+			ExperienceIDs: skill.ExperienceIDs,
+			Visibility:    skill.Visibility,
+		}
+		sanitizeSkillPayload(&payload)
+		if code, _ := validateSkillPayload(payload); code != "" {
+			itemErrors = append(itemErrors, importItemError{Collection: "skills", Index: i, ID: skill.ID, Reason: code})
+			continue
+		}
+
+		skill.Name = payload.Name
+		skill.Category = payload.Category
+		skill.Proficiency = payload.Proficiency
+		skill.Years = payload.Years
+		skill.Icon = payload.Icon
+		skill.ExperienceIDs = payload.ExperienceIDs
+		skill.Visibility = payload.Visibility
+		skills = append(skills, skill)
+	}
+
+	return experiences, skills, itemErrors
+}
+
+// uploadImages copies the archived images to the configured bucket, keeping
+// their object paths. Without a bucket every image is skipped.
+func (a *contentArchive) uploadImages(ctx context.Context) map[string]int {
+	result := map[string]int{"uploaded": 0, "skipped": 0}
+	bucket := constants.GCSBucketName()
+	contentTypes := map[string]string{}
+	for _, img := range a.manifest.Images {
+		contentTypes[path.Clean(img.Object)] = img.ContentType
+	}
+
+	for object, f := range a.images {
+		if bucket == "" {
+			result["skipped"]++
+			continue
+		}
+		data, err := readZipFile(f, constants.MaxImageUploadBytes)
+		if err != nil {
+			log.Printf("[archive] could not read image %s: %v", object, err)
+			result["skipped"]++
+			continue
+		}
+		contentType := contentTypes[object]
+		if !isAllowedImageContentType(contentType) {
+			contentType = mime.TypeByExtension(path.Ext(object))
+		}
+		if !isAllowedImageContentType(contentType) {
+			result["skipped"]++
+			continue
+		}
+		if _, err := uploadToBucketFunc(ctx, bucket, object, contentType, bytes.NewReader(data)); err != nil {
+			log.Printf("[archive] could not upload image %s: %v", object, err)
+			result["skipped"]++
+			continue
+		}
+		result["uploaded"]++
+	}
+	return result
+}
+
+func experienceIndex(items []models.Experience) map[string]interface{} {
+	index := make(map[string]interface{}, len(items))
+	for _, item := range items {
+		index[item.ID] = item
+	}
+	return index
+}
+
+func skillIndex(items []models.Skill) map[string]interface{} {
+	index := make(map[string]interface{}, len(items))
+	for _, item := range items {
+		index[item.ID] = item
+	}
+	return index
+}
+
+// diffByID classifies incoming items against the current ones by ID. Items
+// are compared by their JSON encoding. In replace mode, current items absent
+// from the archive are marked for deletion.
+func diffByID(current, incoming map[string]interface{}, mode string) importDiff {
+	diff := newImportDiff()
+	for id, item := range incoming {
+		existing, ok := current[id]
+		if !ok {
+			diff.Created = append(diff.Created, id)
+			continue
+		}
+		if buildPayloadETag(existing) == buildPayloadETag(item) {
+			diff.Unchanged = append(diff.Unchanged, id)
+		} else {
+			diff.Updated = append(diff.Updated, id)
+		}
+	}
+	if mode == "replace" {
+		for id := range current {
+			if _, ok := incoming[id]; !ok {
+				diff.Deleted = append(diff.Deleted, id)
+			}
+		}
+	}
+	sort.Strings(diff.Created)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Unchanged)
+	sort.Strings(diff.Deleted)
+	return diff
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	archiveExpID   = "00000000-0000-0000-0000-000000000001"
+	archiveSkillID = "00000000-0000-0000-0000-000000000002"
+)
+
+func newArchiveTestApp(svc *ArchiveService) *fiber.App {
+	app := fiber.New()
+	app.Get("/private/export", svc.ExportContent)
+	app.Post("/private/import", svc.ImportContent)
+	return app
+}
+
+func exportArchive(t *testing.T, app *fiber.App) []byte {
+	t.Helper()
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/private/export", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("export failed: err=%v status=%d", err, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "application/zip" {
+		t.Fatalf("expected application/zip, got %s", ct)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	return raw
+}
+
+func importArchive(t *testing.T, app *fiber.App, archive []byte, query string) (*http.Response, map[string]any) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	mp := multipart.NewWriter(body)
+	part, _ := mp.CreateFormFile("file", "export.zip")
+	_, _ = part.Write(archive)
+	_ = mp.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/private/import"+query, body)
+	req.Header.Set("Content-Type", mp.FormDataContentType())
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var decoded map[string]any
+	_ = json.Unmarshal(raw, &decoded)
+	return res, decoded
+}
+
+func seedArchiveRepos(t *testing.T, prefix string) (*memory.ExperienceRepository, *memory.SkillRepository) {
+	t.Helper()
+	ctx := context.Background()
+	expRepo := memory.NewExperienceRepository()
+	skillRepo := memory.NewSkillRepository()
+	_ = expRepo.Create(ctx, models.Experience{
+		ID:         archiveExpID,
+		Title:      "Proyecto",
+		Body:       `<p><img src="` + prefix + `portfolio-images/body.png"></p>`,
+		ImageURLs:  []string{prefix + "portfolio-images/cover.jpg", "https://example.com/external.jpg"},
+		Tags:       []string{"go"},
+		Visibility: constants.VisibilityPublic,
+	})
+	_ = skillRepo.Create(ctx, models.Skill{
+		ID:          archiveSkillID,
+		Name:        "Go",
+		Category:    "backend",
+		Proficiency: 4,
+		Visibility:  constants.VisibilityPublic,
+	})
+	return expRepo, skillRepo
+}
+
+func TestExportContentIncludesManifestAndImages(t *testing.T) {
+	t.Setenv("GCS_BUCKET_NAME", "src-bucket")
+	prefix := constants.GCSURLPrefix()
+
+	orig := downloadFromBucketFunc
+	downloadFromBucketFunc = func(_ context.Context, bucket, object string, _ int64) ([]byte, string, error) {
+		return []byte("img:" + object), "image/png", nil
+	}
+	t.Cleanup(func() { downloadFromBucketFunc = orig })
+
+	expRepo, skillRepo := seedArchiveRepos(t, prefix)
+	app := newArchiveTestApp(NewArchiveService(expRepo, skillRepo))
+
+	raw := exportArchive(t, app)
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+	for _, name := range []string{"manifest.json", "experiences.json", "skills.json", "images/portfolio-images/body.png", "images/portfolio-images/cover.jpg"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("expected archive entry %s", name)
+		}
+	}
+
+	data, _ := readZipFile(entries["manifest.json"], 1<<20)
+	var manifest archiveManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.Format != constants.ArchiveFormat || manifest.Version != constants.ArchiveFormatVersion {
+		t.Errorf("unexpected manifest header: %+v", manifest)
+	}
+	if manifest.Counts["experiences"] != 1 || manifest.Counts["skills"] != 1 || manifest.Counts["images"] != 2 {
+		t.Errorf("unexpected counts: %v", manifest.Counts)
+	}
+	if manifest.SourceImagePrefix != prefix {
+		t.Errorf("expected source prefix %s, got %s", prefix, manifest.SourceImagePrefix)
+	}
+}
+
+func TestImportContentDryRunReportsDiff(t *testing.T) {
+	t.Setenv("GCS_BUCKET_NAME", "")
+	srcExp, srcSkills := seedArchiveRepos(t, "")
+	archive := exportArchive(t, newArchiveTestApp(NewArchiveService(srcExp, srcSkills)))
+
+	dstExp := memory.NewExperienceRepository()
+	dstSkills := memory.NewSkillRepository()
+	stale := "00000000-0000-0000-0000-000000000003"
+	_ = dstExp.Create(context.Background(), models.Experience{ID: stale, Title: "Antigua"})
+	app := newArchiveTestApp(NewArchiveService(dstExp, dstSkills))
+
+	res, report := importArchive(t, app, archive, "?mode=replace&dryRun=true")
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", res.StatusCode, report)
+	}
+	exps := report["experiences"].(map[string]any)
+	if created := exps["created"].([]any); len(created) != 1 || created[0] != archiveExpID {
+		t.Errorf("expected experience to be created, got %v", exps["created"])
+	}
+	if deleted := exps["deleted"].([]any); len(deleted) != 1 || deleted[0] != stale {
+		t.Errorf("expected stale experience to be deleted in replace mode, got %v", exps["deleted"])
+	}
+
+	all, _ := dstExp.List(context.Background())
+	if len(all) != 1 || all[0].ID != stale {
+		t.Fatalf("dry run must not modify the repository, got %+v", all)
+	}
+}
+
+func TestImportContentAppliesMergeAndIsIdempotent(t *testing.T) {
+	srcPrefix := "https://storage.googleapis.com/src-bucket/"
+
+	origDownload := downloadFromBucketFunc
+	downloadFromBucketFunc = func(_ context.Context, _, object string, _ int64) ([]byte, string, error) {
+		return []byte("img"), "image/png", nil
+	}
+	uploaded := map[string]string{}
+	origUpload := uploadToBucketFunc
+	uploadToBucketFunc = func(_ context.Context, bucket, object, _ string, _ io.Reader) (string, error) {
+		uploaded[object] = bucket
+		return "", nil
+	}
+	t.Cleanup(func() {
+		downloadFromBucketFunc = origDownload
+		uploadToBucketFunc = origUpload
+	})
+
+	t.Setenv("GCS_BUCKET_NAME", "src-bucket")
+	srcExp, srcSkills := seedArchiveRepos(t, srcPrefix)
+	archive := exportArchive(t, newArchiveTestApp(NewArchiveService(srcExp, srcSkills)))
+	t.Setenv("GCS_BUCKET_NAME", "dst-bucket")
+
+	dstExp := memory.NewExperienceRepository()
+	dstSkills := memory.NewSkillRepository()
+	app := newArchiveTestApp(NewArchiveService(dstExp, dstSkills))
+
+	res, report := importArchive(t, app, archive, "")
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", res.StatusCode, report)
+	}
+	if uploaded["portfolio-images/cover.jpg"] != "dst-bucket" {
+		t.Errorf("expected images re-uploaded to target bucket, got %v", uploaded)
+	}
+
+	imported, err := dstExp.GetByID(context.Background(), archiveExpID)
+	if err != nil {
+		t.Fatalf("expected imported experience: %v", err)
+	}
+	if imported.ImageURLs[0] != "https://storage.googleapis.com/dst-bucket/portfolio-images/cover.jpg" {
+		t.Errorf("expected image URL rewritten to target bucket, got %s", imported.ImageURLs[0])
+	}
+	if _, err := dstSkills.GetByID(context.Background(), archiveSkillID); err != nil {
+		t.Errorf("expected imported skill: %v", err)
+	}
+
+	_, report = importArchive(t, app, archive, "")
+	exps := report["experiences"].(map[string]any)
+	if unchanged := exps["unchanged"].([]any); len(unchanged) != 1 {
+		t.Errorf("expected second import to be unchanged, got %v", exps)
+	}
+}
+
+func TestImportContentRejectsInvalidItems(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = writeZipJSON(zw, "manifest.json", archiveManifest{Format: constants.ArchiveFormat, Version: 1, ExportedAt: time.Now().UTC().Format(time.RFC3339)})
+	_ = writeZipJSON(zw, "experiences.json", []models.Experience{
+		{ID: archiveExpID, Title: "<b></b>"},
+		{ID: "bad-id", Title: "Valido"},
+	})
+	_ = zw.Close()
+
+	expRepo := memory.NewExperienceRepository()
+	app := newArchiveTestApp(NewArchiveService(expRepo, memory.NewSkillRepository()))
+
+	res, body := importArchive(t, app, buf.Bytes(), "")
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", res.StatusCode)
+	}
+	details := body["details"].(map[string]any)
+	if items := details["context"].([]any); len(items) != 2 {
+		t.Fatalf("expected 2 item errors, got %v", details["context"])
+	}
+	if all, _ := expRepo.List(context.Background()); len(all) != 0 {
+		t.Fatalf("expected nothing imported, got %d items", len(all))
+	}
+
+	res, _ = importArchive(t, app, []byte("not a zip"), "")
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid archive, got %d", res.StatusCode)
+	}
+}
+
+func TestImportContentAppliesContentRules(t *testing.T) {
+	useTestContentRules(t)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = writeZipJSON(zw, "manifest.json", archiveManifest{Format: constants.ArchiveFormat, Version: 1, ExportedAt: time.Now().UTC().Format(time.RFC3339)})
+	_ = writeZipJSON(zw, "experiences.json", []models.Experience{
+		{ID: archiveExpID, Title: "Proyecto", Summary: "Corto", Tags: []string{"go"}},
+	})
+	_ = zw.Close()
+
+	expRepo := memory.NewExperienceRepository()
+	app := newArchiveTestApp(NewArchiveService(expRepo, memory.NewSkillRepository()))
+
+	res, body := importArchive(t, app, buf.Bytes(), "")
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", res.StatusCode)
+	}
+	items := body["details"].(map[string]any)["context"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["reason"] != "validation_failed" {
+		t.Fatalf("expected the rule violation to be reported, got %v", items)
+	}
+	if all, _ := expRepo.List(context.Background()); len(all) != 0 {
+		t.Fatalf("expected nothing imported, got %d items", len(all))
+	}
+}
+
+// failingCreateRepository is an experience store without atomic batches
+// whose Create fails for one ID.
+type failingCreateRepository struct {
+	repository.ExperienceRepository
+	failID string
+}
+
+func (r *failingCreateRepository) Create(ctx context.Context, exp models.Experience) error {
+	if exp.ID == r.failID {
+		return errors.New("disk full")
+	}
+	return r.ExperienceRepository.Create(ctx, exp)
+}
+
+func TestImportContentReportsAppliedItemsOnPartialFailure(t *testing.T) {
+	const secondID = "00000000-0000-0000-0000-000000000003"
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = writeZipJSON(zw, "manifest.json", archiveManifest{Format: constants.ArchiveFormat, Version: 1, ExportedAt: time.Now().UTC().Format(time.RFC3339)})
+	_ = writeZipJSON(zw, "experiences.json", []models.Experience{
+		{ID: archiveExpID, Title: "Primera"},
+		{ID: secondID, Title: "Segunda"},
+	})
+	_ = zw.Close()
+
+	inner := memory.NewExperienceRepository()
+	app := newArchiveTestApp(NewArchiveService(&failingCreateRepository{ExperienceRepository: inner, failID: secondID}, memory.NewSkillRepository()))
+
+	res, body := importArchive(t, app, buf.Bytes(), "")
+	if res.StatusCode != fiber.StatusInternalServerError || body["code"] != "import_failed" {
+		t.Fatalf("expected 500 import_failed, got %d %v", res.StatusCode, body)
+	}
+	applied := body["details"].(map[string]any)["context"].(map[string]any)["applied"].(map[string]any)
+	if ids := applied["experiences"].([]any); len(ids) != 1 || ids[0] != archiveExpID {
+		t.Fatalf("expected the first experience reported as applied, got %v", applied)
+	}
+	if all, _ := inner.List(context.Background()); len(all) != 1 {
+		t.Fatalf("expected 1 stored experience, got %d", len(all))
+	}
+}
@@ -1,7 +1,7 @@
 package services
 
 import (
-	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -79,17 +79,18 @@ func (s *AuthService) Register(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_username", "El nombre de usuario es requerido", nil)
 	}
 
-	_, err := s.users.GetUserByEmail(context.Background(), user.Email)
-	if err == nil {
-		return apiresponse.Error(c, fiber.StatusBadRequest, "user_already_exists", "El usuario ya existe", nil)
-	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "password_hash_failed", "No se pudo procesar la contrasena", err.Error())
 	}
 	user.Password = string(hashedPassword)
-	if err := s.users.SaveUser(context.Background(), user); err != nil {
+	// CreateUser checks the email and stores the user atomically, so two
+	// concurrent registrations cannot both succeed.
+	user, err = s.users.CreateUser(requestContext(c), user)
+	if errors.Is(err, repository.ErrConflict) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "user_already_exists", "El usuario ya existe", nil)
+	}
+	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_user_failed", "No se pudo registrar el usuario", err.Error())
 	}
 	return respondWithToken(c, user.UserId, user.UserName)
@@ -124,7 +125,7 @@ func (s *AuthService) Login(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_password", "Contrasena invalida", nil)
 	}
 
-	user, err := s.users.GetUserByEmail(context.Background(), loginRequest.Email)
+	user, err := s.users.GetUserByEmail(requestContext(c), loginRequest.Email)
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusUnauthorized, "invalid_credentials", "Unauthorized", nil)
 	}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	models "backend-yonathan/src/models"
@@ -18,11 +19,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// errSaveUserRepo wraps a UserRepository and always fails on SaveUser.
+// errSaveUserRepo wraps a UserRepository and always fails on CreateUser and SaveUser.
 type errSaveUserRepo struct {
 	delegate repository.UserRepository
 }
 
+func (e errSaveUserRepo) CreateUser(_ context.Context, _ models.User) (models.User, error) {
+	return models.User{}, errors.New("save failed")
+}
 func (e errSaveUserRepo) SaveUser(_ context.Context, _ models.User) error {
 	return errors.New("save failed")
 }
@@ -191,6 +195,49 @@ func TestRegisterUserAlreadyExists(t *testing.T) {
 	}
 }
 
+func TestRegisterConcurrentDuplicatesCreateOneUser(t *testing.T) {
+	t.Setenv("JWT_SECRET", "unit-test-secret")
+	t.Setenv("REGISTRATION_ENABLED", "true")
+
+	svc := NewAuthService(memory.NewUserRepository())
+	app := fiber.New()
+	app.Post("/register", svc.Register)
+
+	const attempts = 5
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"email":"race@test.com","password":"Test1234","username":"tester"}`))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			statuses <- res.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	created := 0
+	for status := range statuses {
+		switch status {
+		case fiber.StatusOK:
+			created++
+		case fiber.StatusBadRequest:
+		default:
+			t.Errorf("unexpected status %d", status)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly one successful registration, got %d", created)
+	}
+}
+
 func TestRegisterSaveUserFails(t *testing.T) {
 	t.Setenv("JWT_SECRET", "unit-test-secret")
 	t.Setenv("REGISTRATION_ENABLED", "true")
@@ -6,6 +6,7 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
@@ -14,7 +15,12 @@ import (
 // --- HTTP / caching helpers ---
 
 func buildCollectionETag(items []userModel.Experience) string {
-	payload, err := json.Marshal(items)
+	return buildPayloadETag(items)
+}
+
+// buildPayloadETag computes a weak ETag from the JSON encoding of any response payload.
+func buildPayloadETag(value interface{}) string {
+	payload, err := json.Marshal(value)
 	if err != nil {
 		return ""
 	}
@@ -44,3 +50,35 @@ func setPublicCollectionCacheHeaders(c fiber.Ctx, etag string) {
 		c.Set("ETag", etag)
 	}
 }
+
+// buildVersionETag returns the strong ETag of a single versioned item.
+func buildVersionETag(version int64) string {
+	return "\"" + strconv.FormatInt(version, 10) + "\""
+}
+
+// matchesIfMatchHeader reports whether an If-Match header accepts the given
+// strong ETag. "*" matches any existing item; weak validators never match.
+func matchesIfMatchHeader(ifMatch, etag string) bool {
+	if strings.TrimSpace(ifMatch) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch validates a write precondition against the stored version.
+// It returns the HTTP status, error code and message to respond with, or a
+// zero status when the write may proceed.
+func checkIfMatch(ifMatch string, version int64) (int, string, string) {
+	if strings.TrimSpace(ifMatch) == "" {
+		return fiber.StatusPreconditionRequired, "if_match_required", "Se requiere el encabezado If-Match con la version actual"
+	}
+	if !matchesIfMatchHeader(ifMatch, buildVersionETag(version)) {
+		return fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo"
+	}
+	return 0, "", ""
+}
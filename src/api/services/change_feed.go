@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Stream timing (swap in tests).
+var (
+	changeFeedHeartbeat = constants.ChangeFeedHeartbeat
+	changeFeedMaxStream = constants.ChangeFeedMaxStream
+)
+
+// Change feed collections, derived from the tags of the changed experience.
+const (
+	changeCollectionExperiences = "experiences"
+	changeCollectionSkills      = "skills"
+)
+
+// changeEvent is one created/updated/deleted change of an experience or
+// skill. Previous is the visibility before the change, empty when unknown.
+type changeEvent struct {
+	Seq      uint64
+	Kind     repository.ChangeKind
+	Item     models.Experience
+	Previous string
+	At       time.Time
+}
+
+// changeBus fans change events out to stream subscribers and keeps the last
+// events in a bounded buffer for Last-Event-ID replay. Event IDs are
+// "<epoch>-<seq>": the epoch identifies this bus, so IDs issued by another
+// instance or before a restart are recognised as unknown instead of being
+// matched against unrelated sequence numbers.
+type changeBus struct {
+	mu          sync.Mutex
+	epoch       string
+	size        int
+	seq         uint64
+	buffer      []changeEvent
+	subscribers map[*changeSubscription]struct{}
+	// external is set when an ExperienceWatcher feeds the bus; local
+	// publishes are then ignored so each change is delivered once.
+	external bool
+}
+
+// changeSubscription receives live events on events. The channel is closed
+// when the subscriber falls too far behind or unsubscribes.
+type changeSubscription struct {
+	events chan changeEvent
+}
+
+func newChangeBus(size int) *changeBus {
+	return &changeBus{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        size,
+		subscribers: map[*changeSubscription]struct{}{},
+	}
+}
+
+// changeFeed is the process-wide bus the services publish to.
+var changeFeed = newChangeBus(constants.ChangeFeedBufferSize)
+
+// eventID formats the SSE id of the event with sequence seq.
+func (b *changeBus) eventID(seq uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// publish assigns the next sequence number to event, buffers it and
+// delivers it to every subscriber. Subscribers whose queue is full are
+// dropped; they resume from the buffer with Last-Event-ID.
+func (b *changeBus) publish(event changeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	event.Seq = b.seq
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	b.buffer = append(b.buffer, event)
+	if len(b.buffer) > b.size {
+		b.buffer = append(b.buffer[:0:0], b.buffer[len(b.buffer)-b.size:]...)
+	}
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe registers a subscriber. With a lastEventID it also returns the
+// buffered events after it; ok is false when that ID is not from this bus
+// or is older than the buffer, in which case the client must reload.
+func (b *changeBus) subscribe(lastEventID string) (sub *changeSubscription, replay []changeEvent, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub = &changeSubscription{events: make(chan changeEvent, constants.ChangeFeedSubscriberBuffer)}
+	b.subscribers[sub] = struct{}{}
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+
+	epoch, rawSeq, found := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if !found || err != nil || epoch != b.epoch || seq > b.seq {
+		return sub, nil, false
+	}
+	if len(b.buffer) > 0 && seq+1 < b.buffer[0].Seq {
+		return sub, nil, false
+	}
+	for _, event := range b.buffer {
+		if event.Seq > seq {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, true
+}
+
+// unsubscribe removes sub, closing its channel if still open.
+func (b *changeBus) unsubscribe(sub *changeSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publishLocal publishes a change made by this instance unless an external
+// source feeds the bus.
+func (b *changeBus) publishLocal(event changeEvent) {
+	b.mu.Lock()
+	external := b.external
+	b.mu.Unlock()
+	if !external {
+		b.publish(event)
+	}
+}
+
+// publishExperienceChange reports a write made through the services to the
+// change feed. previous is the visibility before the write ("" for creates).
+func publishExperienceChange(kind repository.ChangeKind, item models.Experience, previous string) {
+	changeFeed.publishLocal(changeEvent{Kind: kind, Item: item, Previous: previous})
+}
+
+// changeCollection returns the collection a changed item belongs to.
+func changeCollection(item models.Experience) string {
+	if isSkillExperience(item) {
+		return changeCollectionSkills
+	}
+	return changeCollectionExperiences
+}
+
+// publicChange returns the event a public subscriber sees, or false when
+// the change does not concern public content. Items that stop being public
+// are reported as deleted and items that become public as created.
+func publicChange(event changeEvent) (changeEvent, bool) {
+	isPublic := event.Kind != repository.ChangeDeleted && event.Item.Visibility == constants.VisibilityPublic
+	wasPublic := event.Previous == constants.VisibilityPublic
+	unknown := event.Previous == "" && event.Kind != repository.ChangeCreated
+
+	switch {
+	case isPublic && event.Kind == repository.ChangeUpdated && !wasPublic && !unknown:
+		event.Kind = repository.ChangeCreated
+	case isPublic:
+	case wasPublic || unknown:
+		event.Kind = repository.ChangeDeleted
+	default:
+		return changeEvent{}, false
+	}
+	return event, true
+}
+
+// StartChangeFeedWatcher feeds the change feed from watcher instead of from
+// local writes, so that changes made by other instances reach this one's
+// subscribers. onChange runs for every change, before it is published, to
+// drop caches the change made stale. The watch is restarted after failures
+// until ctx is cancelled.
+func StartChangeFeedWatcher(ctx context.Context, watcher repository.ExperienceWatcher, onChange func()) {
+	changeFeed.mu.Lock()
+	changeFeed.external = true
+	changeFeed.mu.Unlock()
+
+	go func() {
+		for {
+			err := watcher.WatchExperiences(ctx, func(change repository.ExperienceChange) {
+				if onChange != nil {
+					onChange()
+				}
+				invalidatePublicStats()
+				previous := ""
+				if change.Previous != nil {
+					previous = change.Previous.Visibility
+				}
+				if change.Kind == repository.ChangeDeleted {
+					previous = change.Experience.Visibility
+				}
+				changeFeed.publish(changeEvent{Kind: change.Kind, Item: change.Experience, Previous: previous})
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[change-feed] watcher stopped: %v; retrying in %s", err, constants.ChangeFeedRetryDelay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(constants.ChangeFeedRetryDelay):
+			}
+		}
+	}()
+}
+
+// changeEventData is the JSON payload of a change feed event.
+type changeEventData struct {
+	Collection string             `json:"collection"`
+	ID         string             `json:"id"`
+	Item       *models.Experience `json:"item,omitempty"`
+	At         string             `json:"at"`
+}
+
+// changePayload builds the payload of event, signing image URLs of the item.
+func changePayload(ctx context.Context, event changeEvent) changeEventData {
+	data := changeEventData{
+		Collection: changeCollection(event.Item),
+		ID:         event.Item.ID,
+		At:         event.At.Format(time.RFC3339),
+	}
+	if event.Kind != repository.ChangeDeleted {
+		item := event.Item
+		SignExperienceImageURLs(ctx, &item)
+		data.Item = &item
+	}
+	return data
+}
+
+// writeChangeEvent writes event as an SSE message.
+func writeChangeEvent(w *bufio.Writer, id string, event changeEvent) error {
+	data, err := json.Marshal(changePayload(context.Background(), event))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event.Kind, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// streamChanges serves the change feed as server-sent events. Streams only
+// carry content of the request's tenant, and public streams only public
+// content (see publicChange). A Last-Event-ID that cannot be replayed
+// produces a "reset" event telling the client to reload.
+func streamChanges(c fiber.Ctx, public bool) error {
+	scoped, tenant := constants.MultiTenant(), tenantID(c)
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	sub, replay, ok := changeFeed.subscribe(lastEventID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer changeFeed.unsubscribe(sub)
+
+		send := func(event changeEvent) error {
+			id := changeFeed.eventID(event.Seq)
+			visible := !scoped || experienceTenant(event.Item) == tenant
+			if visible && public {
+				event, visible = publicChange(event)
+			}
+			if !visible {
+				// Advance the client's position without revealing anything.
+				if _, err := fmt.Fprintf(w, "id: %s\n\n", id); err != nil {
+					return err
+				}
+				return w.Flush()
+			}
+			return writeChangeEvent(w, id, event)
+		}
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", constants.ChangeFeedRetryDelay.Milliseconds()); err != nil {
+			return
+		}
+		if !ok {
+			if _, err := w.WriteString("event: reset\ndata: {}\n\n"); err != nil {
+				return
+			}
+		}
+		for _, event := range replay {
+			if send(event) != nil {
+				return
+			}
+		}
+		if w.Flush() != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(changeFeedHeartbeat)
+		defer heartbeat.Stop()
+		deadline := time.NewTimer(changeFeedMaxStream)
+		defer deadline.Stop()
+		for {
+			select {
+			case event, open := <-sub.events:
+				if !open {
+					return
+				}
+				if send(event) != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := w.WriteString(": ping\n\n"); err != nil || w.Flush() != nil {
+					return
+				}
+			case <-deadline.C:
+				return
+			}
+		}
+	})
+}
+
+// StreamPublicChanges godoc
+// @Summary      Stream de cambios publicos
+// @Description  Server-sent events con los cambios (created, updated, deleted) de experiencias y skills publicas. Cada evento lleva id; al reconectar, el header Last-Event-ID (o ?lastEventId=) reenvia los eventos perdidos que sigan en el buffer. Si no es posible se envia un evento "reset" y el cliente debe recargar el listado. Un contenido que deja de ser publico llega como deleted.
+// @Tags         Experiences
+// @Produce      text/event-stream
+// @Param        Last-Event-ID  header  string  false  "Ultimo id recibido"
+// @Success      200  {string}  string  "text/event-stream"
+// @Router       /api/experiences/stream [get]
+func StreamPublicChanges(c fiber.Ctx) error {
+	return streamChanges(c, true)
+}
+
+// StreamAllChanges godoc
+// @Summary      Stream de todos los cambios
+// @Description  Igual que /api/experiences/stream pero incluye contenido privado. Requiere JWT (la cookie de sesion sirve para EventSource).
+// @Tags         Experiences
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        Last-Event-ID  header  string  false  "Ultimo id recibido"
+// @Success      200  {string}  string  "text/event-stream"
+// @Failure      401  {object}  map[string]interface{}
+// @Router       /api/private/experiences/stream [get]
+func StreamAllChanges(c fiber.Ctx) error {
+	return streamChanges(c, false)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// useChangeFeed replaces the process-wide bus with a fresh one for a test.
+func useChangeFeed(t *testing.T, size int) *changeBus {
+	t.Helper()
+	original, maxStream := changeFeed, changeFeedMaxStream
+	changeFeed = newChangeBus(size)
+	changeFeedMaxStream = 50 * time.Millisecond
+	t.Cleanup(func() { changeFeed, changeFeedMaxStream = original, maxStream })
+	return changeFeed
+}
+
+func TestChangeBusReplaysFromLastEventID(t *testing.T) {
+	bus := useChangeFeed(t, 3)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		bus.publish(changeEvent{Kind: repository.ChangeCreated, Item: models.Experience{ID: id}})
+	}
+
+	_, replay, ok := bus.subscribe(bus.eventID(3))
+	if !ok || len(replay) != 2 || replay[0].Item.ID != "d" || replay[1].Item.ID != "e" {
+		t.Fatalf("replay after 3 = %v, %v", replay, ok)
+	}
+	// Event 2 fell out of the buffer, so resuming after event 1 would miss it.
+	if _, _, ok := bus.subscribe(bus.eventID(1)); ok {
+		t.Fatal("expected a reset for an ID older than the buffer")
+	}
+	for _, id := range []string{"other-4", bus.eventID(9), "garbage"} {
+		if _, _, ok := bus.subscribe(id); ok {
+			t.Fatalf("expected a reset for unknown ID %q", id)
+		}
+	}
+}
+
+func TestChangeBusDropsSlowSubscribers(t *testing.T) {
+	bus := useChangeFeed(t, 10)
+	sub, _, _ := bus.subscribe("")
+	for i := 0; i <= constants.ChangeFeedSubscriberBuffer; i++ {
+		bus.publish(changeEvent{Kind: repository.ChangeUpdated, Item: models.Experience{ID: "a"}})
+	}
+	received := 0
+	for range sub.events {
+		received++
+	}
+	if received != constants.ChangeFeedSubscriberBuffer {
+		t.Fatalf("received %d events before the channel closed", received)
+	}
+	bus.unsubscribe(sub) // already removed; must not panic
+}
+
+func TestPublicChangeFiltersByVisibility(t *testing.T) {
+	public, private := constants.VisibilityPublic, constants.VisibilityPrivate
+	cases := []struct {
+		name     string
+		kind     repository.ChangeKind
+		now      string
+		previous string
+		want     repository.ChangeKind
+	}{
+		{"public create", repository.ChangeCreated, public, "", repository.ChangeCreated},
+		{"private create", repository.ChangeCreated, private, "", ""},
+		{"public update", repository.ChangeUpdated, public, public, repository.ChangeUpdated},
+		{"published", repository.ChangeUpdated, public, private, repository.ChangeCreated},
+		{"unpublished", repository.ChangeUpdated, private, public, repository.ChangeDeleted},
+		{"private update", repository.ChangeUpdated, private, private, ""},
+		{"public delete", repository.ChangeDeleted, public, public, repository.ChangeDeleted},
+		{"private delete", repository.ChangeDeleted, private, private, ""},
+	}
+	for _, tc := range cases {
+		event, ok := publicChange(changeEvent{Kind: tc.kind, Item: models.Experience{ID: "a", Visibility: tc.now}, Previous: tc.previous})
+		if got := event.Kind; (tc.want == "") == ok || (ok && got != tc.want) {
+			t.Errorf("%s: got %q, %v; want %q", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestServicesPublishChanges(t *testing.T) {
+	bus := useChangeFeed(t, 10)
+	repo := memory.NewExperienceRepository()
+	exp := NewExperienceService(repo)
+	skill := NewSkillService(repo)
+
+	app := fiber.New()
+	app.Post("/experiences", exp.CreateExperience)
+	app.Post("/skills", skill.CreateSkill)
+
+	for _, path := range []string{"/experiences", "/skills"} {
+		body, _ := json.Marshal(map[string]any{"title": "Titulo", "visibility": constants.VisibilityPublic})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if res, err := app.Test(req); err != nil || res.StatusCode != fiber.StatusOK {
+			t.Fatalf("POST %s: %v %v", path, res, err)
+		}
+	}
+
+	if len(bus.buffer) != 2 {
+		t.Fatalf("buffered %d events, want 2", len(bus.buffer))
+	}
+	if got := changeCollection(bus.buffer[0].Item); got != changeCollectionExperiences {
+		t.Fatalf("first event collection = %q", got)
+	}
+	if got := changeCollection(bus.buffer[1].Item); got != changeCollectionSkills {
+		t.Fatalf("second event collection = %q", got)
+	}
+}
+
+func TestReorderAndRelatedPublishChanges(t *testing.T) {
+	bus := useChangeFeed(t, 10)
+	repo := memory.NewExperienceRepository()
+	ids := []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"}
+	for _, id := range ids {
+		_ = repo.Create(context.Background(), models.Experience{ID: id, Title: "Titulo", Visibility: constants.VisibilityPublic, Version: 1})
+	}
+	exp := NewExperienceService(repo)
+	app := fiber.New()
+	app.Put("/experiences/order", exp.ReorderExperiences)
+	app.Put("/experiences/:id/related", exp.SetRelatedExperiences)
+
+	send := func(path, ifMatch string, payload any) {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", ifMatch)
+		if res, err := app.Test(req); err != nil || res.StatusCode != fiber.StatusOK {
+			t.Fatalf("PUT %s: %v %v", path, res, err)
+		}
+	}
+	send("/experiences/order", "", map[string]any{"items": []map[string]any{{"id": ids[0], "position": 1}, {"id": ids[1], "position": 0}}})
+	send("/experiences/"+ids[0]+"/related", buildVersionETag(2), map[string]any{"ids": []string{ids[1]}})
+
+	if len(bus.buffer) != 3 {
+		t.Fatalf("buffered %d events, want 3", len(bus.buffer))
+	}
+	for i, event := range bus.buffer[:2] {
+		if event.Kind != repository.ChangeUpdated || event.Item.Version != 2 {
+			t.Fatalf("reorder event %d = %+v, want an update to version 2", i, event)
+		}
+	}
+	if last := bus.buffer[2]; last.Item.ID != ids[0] || last.Item.Version != 3 || len(last.Item.RelatedIDs) != 1 {
+		t.Fatalf("related event = %+v, want %s at version 3", last, ids[0])
+	}
+}
+
+func TestStreamChangesReplaysPublicEvents(t *testing.T) {
+	bus := useChangeFeed(t, 10)
+	bus.publish(changeEvent{Kind: repository.ChangeCreated, Item: models.Experience{ID: "first", Visibility: constants.VisibilityPublic}})
+	bus.publish(changeEvent{Kind: repository.ChangeCreated, Item: models.Experience{ID: "secret", Visibility: constants.VisibilityPrivate}})
+	bus.publish(changeEvent{Kind: repository.ChangeUpdated, Item: models.Experience{ID: "first", Visibility: constants.VisibilityPrivate}, Previous: constants.VisibilityPublic})
+
+	app := fiber.New()
+	app.Get("/stream", StreamPublicChanges)
+	app.Get("/private/stream", StreamAllChanges)
+
+	read := func(path, lastEventID string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if ct := res.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(ct, "text/event-stream") {
+			t.Fatalf("content type = %q", ct)
+		}
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	public := read("/stream", bus.eventID(1))
+	if strings.Contains(public, "secret") {
+		t.Fatalf("public stream leaked a private item:\n%s", public)
+	}
+	if !strings.Contains(public, "id: "+bus.eventID(2)+"\n\n") {
+		t.Fatalf("public stream should advance past hidden events:\n%s", public)
+	}
+	if !strings.Contains(public, "id: "+bus.eventID(3)+"\nevent: deleted\n") {
+		t.Fatalf("unpublished item should be reported as deleted:\n%s", public)
+	}
+
+	private := read("/private/stream", bus.eventID(1))
+	if !strings.Contains(private, `"id":"secret"`) || !strings.Contains(private, "event: updated") {
+		t.Fatalf("private stream missing events:\n%s", private)
+	}
+
+	if reset := read("/stream", "stale-1"); !strings.Contains(reset, "event: reset") {
+		t.Fatalf("expected a reset event:\n%s", reset)
+	}
+}
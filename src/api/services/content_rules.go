@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/sanitizer"
+	"backend-yonathan/src/pkg/validation"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// --- Content validation rules ---
+
+// activeContentRules is the rule set evaluated on experience and skill writes.
+// A nil rule set accepts everything.
+var activeContentRules = struct {
+	mu    sync.RWMutex
+	rules *validation.RuleSet
+}{}
+
+func setContentRules(rules *validation.RuleSet) {
+	activeContentRules.mu.Lock()
+	activeContentRules.rules = rules
+	activeContentRules.mu.Unlock()
+}
+
+// LoadContentRules loads the rules file named by VALIDATION_RULES_FILE. When
+// the variable is unset writes are only checked by the built-in
+// sanitization. An invalid file returns an error, on which main fails
+// startup; the active rules are left unchanged.
+func LoadContentRules() error {
+	path := constants.ValidationRulesFile()
+	if path == "" {
+		setContentRules(nil)
+		return nil
+	}
+	rules, err := validation.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("validation rules %s: %w", path, err)
+	}
+	setContentRules(rules)
+	return nil
+}
+
+// validateContent evaluates the active rules of collection against the
+// editable fields of item. Body length and pattern rules apply to its plain
+// text; link rules inspect the HTML.
+func validateContent(collection string, item models.Experience) []validation.FieldError {
+	activeContentRules.mu.RLock()
+	rules := activeContentRules.rules
+	activeContentRules.mu.RUnlock()
+
+	return rules.Validate(collection, validation.Input{
+		Fields: map[string]string{
+			"title":      item.Title,
+			"summary":    item.Summary,
+			"body":       sanitizer.StripHTML(item.Body),
+			"visibility": item.Visibility,
+		},
+		Tags:      item.Tags,
+		ImageURLs: item.ImageURLs,
+		BodyHTML:  item.Body,
+	})
+}
+
+func contentRulesError(c fiber.Ctx, errs []validation.FieldError) error {
+	return apiresponse.Error(c, fiber.StatusBadRequest, "validation_failed", "El contenido no cumple las reglas de validacion", errs)
+}
+
+// dryRunValidation sanitizes a payload and reports every built-in and
+// configured rule it violates, without saving anything.
+func dryRunValidation(c fiber.Ctx, collection string, prepare func(models.Experience) models.Experience) error {
+	var payload experiencePayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	sanitizePayload(requestContext(c), &payload)
+
+	item := prepare(models.Experience{
+		Title:      payload.Title,
+		Summary:    payload.Summary,
+		Body:       payload.Body,
+		ImageURLs:  payload.ImageURLs,
+		Tags:       payload.Tags,
+		Visibility: payload.Visibility,
+	})
+
+	errs := []validation.FieldError{}
+	if item.Title == "" {
+		errs = append(errs, validation.FieldError{Field: "title", Rule: "required", Message: "El titulo es requerido"})
+	}
+	for _, fieldErr := range validateContent(collection, item) {
+		if item.Title == "" && fieldErr.Field == "title" && fieldErr.Rule == "required" {
+			continue
+		}
+		errs = append(errs, fieldErr)
+	}
+
+	payload.Tags = item.Tags
+	return apiresponse.Success(c, fiber.Map{
+		"valid":   len(errs) == 0,
+		"errors":  errs,
+		"payload": payload,
+	})
+}
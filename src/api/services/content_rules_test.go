@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/validation"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const testContentRules = `{
+  "collections": {
+    "experiences": {
+      "fields": {"summary": {"required": true, "minLength": 10}},
+      "minTags": 1,
+      "bodyLinks": {"requireHttps": true}
+    },
+    "skills": {"allowedTags": ["skill", "go"]}
+  }
+}`
+
+func useTestContentRules(t *testing.T) {
+	t.Helper()
+	rules, err := validation.Parse([]byte(testContentRules), "json")
+	if err != nil {
+		t.Fatalf("invalid test rules: %v", err)
+	}
+	setContentRules(rules)
+	t.Cleanup(func() { setContentRules(nil) })
+}
+
+func postJSON(t *testing.T, app *fiber.App, path string, payload any) (*http.Response, []byte) {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	return res, raw
+}
+
+func TestCreateExperienceAppliesContentRules(t *testing.T) {
+	useTestContentRules(t)
+	svc := NewExperienceService(memory.NewExperienceRepository())
+	app := fiber.New()
+	app.Post("/private/experiences", svc.CreateExperience)
+
+	res, raw := postJSON(t, app, "/private/experiences", map[string]any{
+		"title":   "Proyecto",
+		"summary": "Corto",
+		"body":    `<a href="http://example.com">demo</a>`,
+	})
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", res.StatusCode, raw)
+	}
+	var envelope struct {
+		Code    string `json:"code"`
+		Details struct {
+			Context []validation.FieldError `json:"context"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if envelope.Code != "validation_failed" || len(envelope.Details.Context) != 3 {
+		t.Fatalf("expected three field errors, got %s", raw)
+	}
+
+	res, raw = postJSON(t, app, "/private/experiences", map[string]any{
+		"title":   "Proyecto",
+		"summary": "Resumen suficientemente largo",
+		"body":    `<a href="https://example.com">demo</a>`,
+		"tags":    []string{"go"},
+	})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.StatusCode, raw)
+	}
+}
+
+func TestValidateEndpointsAreDryRun(t *testing.T) {
+	useTestContentRules(t)
+	repo := memory.NewExperienceRepository()
+	exp := NewExperienceService(repo)
+	skill := NewSkillService(repo)
+	app := fiber.New()
+	app.Post("/private/experiences/validate", exp.ValidateExperience)
+	app.Post("/private/skills/validate", skill.ValidateSkill)
+
+	var result struct {
+		Valid  bool                    `json:"valid"`
+		Errors []validation.FieldError `json:"errors"`
+	}
+	res, raw := postJSON(t, app, "/private/experiences/validate", map[string]any{"summary": "Resumen suficientemente largo"})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	_ = json.Unmarshal(raw, &result)
+	if result.Valid || len(result.Errors) != 2 || result.Errors[0].Field != "title" {
+		t.Fatalf("expected title and tags errors, got %s", raw)
+	}
+
+	res, raw = postJSON(t, app, "/private/skills/validate", map[string]any{"title": "Go", "tags": []string{"go"}})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	result.Errors = nil
+	_ = json.Unmarshal(raw, &result)
+	if !result.Valid {
+		t.Fatalf("expected skill to be valid, got %s", raw)
+	}
+
+	if items, _ := repo.List(t.Context()); len(items) != 0 {
+		t.Fatalf("dry run must not save, got %d items", len(items))
+	}
+}
+
+func TestBatchReportsContentRuleErrors(t *testing.T) {
+	useTestContentRules(t)
+	svc := NewExperienceService(memory.NewExperienceRepository())
+	app := fiber.New()
+	app.Post("/private/experiences/batch", svc.BatchExperiences)
+
+	res, raw := postJSON(t, app, "/private/experiences/batch", map[string]any{
+		"operations": []map[string]any{
+			{"op": "create", "data": map[string]any{"title": "Sin tags", "summary": "Resumen suficientemente largo"}},
+			{"op": "create", "data": map[string]any{"title": "Con tags", "summary": "Resumen suficientemente largo", "tags": []string{"go"}}},
+		},
+	})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.StatusCode, raw)
+	}
+	var result struct {
+		Applied int           `json:"applied"`
+		Results []batchResult `json:"results"`
+	}
+	_ = json.Unmarshal(raw, &result)
+	if result.Applied != 1 || result.Results[0].Code != "validation_failed" || len(result.Results[0].Errors) != 1 {
+		t.Fatalf("expected first create to fail validation, got %s", raw)
+	}
+}
+
+func TestLoadContentRulesKeepsRulesOnInvalidFile(t *testing.T) {
+	useTestContentRules(t)
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VALIDATION_RULES_FILE", path)
+
+	if err := LoadContentRules(); err == nil {
+		t.Fatal("expected an error for an invalid rules file")
+	}
+	if errs := validateContent("experiences", models.Experience{Title: "Proyecto"}); len(errs) == 0 {
+		t.Fatal("expected the previous rules to stay active")
+	}
+}
@@ -47,7 +47,7 @@ func ResolveDomain(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_domain", "Formato de dominio invalido", nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultDNSTimeout)
+	ctx, cancel := context.WithTimeout(c.Context(), constants.DefaultDNSTimeout)
 	defer cancel()
 
 	resolver := newDNSResolver()
@@ -94,7 +94,7 @@ func CheckPropagation(c fiber.Ctx) error {
 	}
 
 	recordType := strings.ToUpper(strings.TrimSpace(c.Query("type", "A")))
-	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultDNSTimeout)
+	ctx, cancel := context.WithTimeout(c.Context(), constants.DefaultDNSTimeout)
 	defer cancel()
 
 	resolver := newDNSResolver()
@@ -165,7 +165,7 @@ func GetMailRecords(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_domain", "Formato de dominio invalido", nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultDNSTimeout)
+	ctx, cancel := context.WithTimeout(c.Context(), constants.DefaultDNSTimeout)
 	defer cancel()
 
 	resolver := newDNSResolver()
@@ -250,7 +250,7 @@ func CheckBlacklist(c fiber.Ctx) error {
 		Listed   bool   `json:"listed"`
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultDNSTimeout)
+	ctx, cancel := context.WithTimeout(c.Context(), constants.DefaultDNSTimeout)
 	defer cancel()
 
 	resolver := newDNSResolver()
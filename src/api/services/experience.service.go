@@ -1,8 +1,8 @@
 package services
 
 import (
-	"context"
 	"errors"
+	"sort"
 	"time"
 
 	models "backend-yonathan/src/models"
@@ -16,17 +16,49 @@ import (
 
 // ExperienceService handles experience CRUD business logic.
 type ExperienceService struct {
-	repo repository.ExperienceRepository
+	repo    repository.ExperienceRepository
+	related *relatedIndexes
 }
 
 // NewExperienceService creates an ExperienceService backed by the given ExperienceRepository.
 func NewExperienceService(repo repository.ExperienceRepository) *ExperienceService {
-	return &ExperienceService{repo: repo}
+	return &ExperienceService{repo: repo, related: newRelatedIndexes()}
+}
+
+// reorderPayload is the request body of PUT /api/private/experiences/order.
+type reorderPayload struct {
+	Items []struct {
+		ID       string `json:"id"`
+		Position int    `json:"position"`
+		Pinned   *bool  `json:"pinned"`
+	} `json:"items"`
+}
+
+// sortExperiences applies the default display order: pinned items first,
+// then ascending position. Ties keep the repository order.
+func sortExperiences(items []models.Experience) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Pinned != items[j].Pinned {
+			return items[i].Pinned
+		}
+		return items[i].Position < items[j].Position
+	})
+}
+
+// nextPosition returns the position that places a new item after all existing ones.
+func nextPosition(items []models.Experience) int {
+	next := 0
+	for _, item := range items {
+		if item.Position >= next {
+			next = item.Position + 1
+		}
+	}
+	return next
 }
 
 // ListPublicExperiences godoc
 // @Summary      Listar experiencias publicas
-// @Description  Devuelve experiencias con visibility=public. Soporta ETag/If-None-Match.
+// @Description  Devuelve experiencias con visibility=public, primero las fijadas (pinned) y luego por position. Soporta ETag/If-None-Match.
 // @Tags         Experiences
 // @Produce      json
 // @Success      200  {object}  map[string]interface{}  "items"
@@ -34,7 +66,7 @@ func NewExperienceService(repo repository.ExperienceRepository) *ExperienceServi
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/experiences [get]
 func (s *ExperienceService) ListPublicExperiences(c fiber.Ctx) error {
-	all, err := s.repo.List(context.Background())
+	all, err := s.repo.List(requestContext(c))
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
 	}
@@ -46,9 +78,10 @@ func (s *ExperienceService) ListPublicExperiences(c fiber.Ctx) error {
 		}
 	}
 
-	SignExperienceList(context.Background(), public)
+	sortExperiences(public)
+	SignExperienceList(requestContext(c), public)
 
-	etag := buildCollectionETag(public)
+	etag := tenantETag(c, buildCollectionETag(public))
 	setPublicCollectionCacheHeaders(c, etag)
 	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
 		return c.SendStatus(fiber.StatusNotModified)
@@ -68,11 +101,12 @@ func (s *ExperienceService) ListPublicExperiences(c fiber.Ctx) error {
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/experiences [get]
 func (s *ExperienceService) ListAllExperiences(c fiber.Ctx) error {
-	all, err := s.repo.List(context.Background())
+	all, err := s.repo.List(requestContext(c))
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
 	}
-	SignExperienceList(context.Background(), all)
+	sortExperiences(all)
+	SignExperienceList(requestContext(c), all)
 	return apiresponse.Success(c, fiber.Map{"items": all})
 }
 
@@ -94,44 +128,112 @@ func (s *ExperienceService) CreateExperience(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
 	}
 
-	sanitizePayload(&payload)
+	sanitizePayload(requestContext(c), &payload)
 
 	if payload.Title == "" {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "missing_title", "El titulo es requerido", nil)
 	}
 
+	all, err := s.repo.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 	item := models.Experience{
 		ID:         uuid.NewString(),
+		TenantID:   tenantID(c),
 		Title:      payload.Title,
 		Summary:    payload.Summary,
 		Body:       payload.Body,
 		ImageURLs:  payload.ImageURLs,
 		Tags:       payload.Tags,
 		Visibility: payload.Visibility,
+		Position:   nextPosition(all),
+		Version:    1,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 
-	if err := s.repo.Create(context.Background(), item); err != nil {
+	if errs := validateContent(constants.ValidationCollectionExperiences, item); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	if err := s.repo.Create(requestContext(c), item); err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo guardar la experiencia", err.Error())
 	}
 
+	s.related.forTenant(tenantID(c)).upsert(item)
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeCreated, item, "")
+	c.Set("ETag", buildVersionETag(item.Version))
 	return apiresponse.Success(c, item)
 }
 
+// ValidateExperience godoc
+// @Summary      Validar experiencia (dry-run)
+// @Description  Sanitiza el payload y lo evalua contra las reglas de contenido configuradas (VALIDATION_RULES_FILE) sin guardar nada. Devuelve los errores por campo. Requiere JWT.
+// @Tags         Experiences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        experience  body  object{title=string,summary=string,body=string,imageUrls=[]string,tags=[]string,visibility=string}  true  "Datos"
+// @Success      200  {object}  map[string]interface{}  "valid, errors, payload"
+// @Failure      400  {object}  map[string]interface{}
+// @Router       /api/private/experiences/validate [post]
+func (s *ExperienceService) ValidateExperience(c fiber.Ctx) error {
+	return dryRunValidation(c, constants.ValidationCollectionExperiences, func(item models.Experience) models.Experience {
+		return item
+	})
+}
+
+// GetExperience godoc
+// @Summary      Obtener experiencia
+// @Description  Devuelve una experiencia por ID con su ETag de version, necesario en If-Match para actualizarla o eliminarla. Requiere JWT.
+// @Tags         Experiences
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "ID de la experiencia"
+// @Success      200  {object}  userModel.Experience
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/experiences/{id} [get]
+func (s *ExperienceService) GetExperience(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	item, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	c.Set("ETag", buildVersionETag(item.Version))
+	signed := []models.Experience{item}
+	SignExperienceList(requestContext(c), signed)
+	return apiresponse.Success(c, signed[0])
+}
+
 // UpdateExperience godoc
 // @Summary      Actualizar experiencia
-// @Description  Actualiza una experiencia por ID. Requiere JWT. imageUrls solo acepta URLs http/https (máx. 10, cada una ≤ 2048 chars); las data: URLs se descartan.
+// @Description  Actualiza una experiencia por ID. Requiere JWT e If-Match con el ETag de la version actual. imageUrls solo acepta URLs http/https (máx. 10, cada una ≤ 2048 chars); las data: URLs se descartan.
 // @Tags         Experiences
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id          path  string  true  "ID de la experiencia"
+// @Param        id          path    string  true  "ID de la experiencia"
+// @Param        If-Match    header  string  true  "ETag de la version actual"
 // @Param        experience  body  object{title=string,summary=string,body=string,imageUrls=[]string,tags=[]string,visibility=string}  true  "Datos"
 // @Success      200  {object}  userModel.Experience
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/experiences/{id} [put]
 func (s *ExperienceService) UpdateExperience(c fiber.Ctx) error {
@@ -145,9 +247,9 @@ func (s *ExperienceService) UpdateExperience(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
 	}
 
-	sanitizePayload(&payload)
+	sanitizePayload(requestContext(c), &payload)
 
-	existing, err := s.repo.GetByID(context.Background(), id)
+	existing, err := s.repo.GetByID(requestContext(c), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
@@ -155,6 +257,12 @@ func (s *ExperienceService) UpdateExperience(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
 	}
 
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+	expected := existing.Version
+	previousVisibility := existing.Visibility
+
 	if payload.Title != "" {
 		existing.Title = payload.Title
 	}
@@ -165,26 +273,116 @@ func (s *ExperienceService) UpdateExperience(c fiber.Ctx) error {
 	existing.Visibility = payload.Visibility
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 
-	if err := s.repo.Update(context.Background(), existing); err != nil {
+	if errs := validateContent(constants.ValidationCollectionExperiences, existing); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	if err := s.repo.Update(requestContext(c), existing, expected); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo actualizar la experiencia", err.Error())
 	}
+	existing.Version = expected + 1
 
+	s.related.forTenant(tenantID(c)).upsert(existing)
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeUpdated, existing, previousVisibility)
+	c.Set("ETag", buildVersionETag(existing.Version))
+	return apiresponse.Success(c, existing)
+}
+
+// PatchExperience godoc
+// @Summary      Actualizar parcialmente experiencia
+// @Description  Aplica un JSON Merge Patch (application/merge-patch+json, RFC 7396) o un JSON Patch (application/json-patch+json, RFC 6902) sobre title, summary, body, imageUrls, tags y visibility. El resultado se valida igual que en PUT. Requiere JWT e If-Match.
+// @Tags         Experiences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string  true  "ID de la experiencia"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
+// @Param        patch     body    object  true  "Merge patch u operaciones JSON Patch"
+// @Success      200  {object}  userModel.Experience
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      415  {object}  map[string]interface{}
+// @Failure      422  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/experiences/{id} [patch]
+func (s *ExperienceService) PatchExperience(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	existing, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+
+	payload, failure := patchExperiencePayload(c, existing)
+	if failure != nil {
+		return apiresponse.Error(c, failure.status, failure.code, failure.message, failure.details)
+	}
+
+	expected := existing.Version
+	previousVisibility := existing.Visibility
+	existing.Title = payload.Title
+	existing.Summary = payload.Summary
+	existing.Body = payload.Body
+	existing.ImageURLs = payload.ImageURLs
+	existing.Tags = payload.Tags
+	existing.Visibility = payload.Visibility
+	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if errs := validateContent(constants.ValidationCollectionExperiences, existing); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	if err := s.repo.Update(requestContext(c), existing, expected); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo actualizar la experiencia", err.Error())
+	}
+	existing.Version = expected + 1
+
+	s.related.forTenant(tenantID(c)).upsert(existing)
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeUpdated, existing, previousVisibility)
+	c.Set("ETag", buildVersionETag(existing.Version))
 	return apiresponse.Success(c, existing)
 }
 
 // DeleteExperience godoc
 // @Summary      Eliminar experiencia
-// @Description  Elimina una experiencia por ID. Requiere JWT.
+// @Description  Elimina una experiencia por ID. Requiere JWT e If-Match con el ETag de la version actual.
 // @Tags         Experiences
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id  path  string  true  "ID de la experiencia"
+// @Param        id        path    string  true  "ID de la experiencia"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
 // @Success      200  {object}  map[string]interface{}  "deleted, id"
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/experiences/{id} [delete]
 func (s *ExperienceService) DeleteExperience(c fiber.Ctx) error {
@@ -193,12 +391,90 @@ func (s *ExperienceService) DeleteExperience(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
 	}
 
-	if err := s.repo.Delete(context.Background(), id); err != nil {
+	existing, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+
+	if err := s.repo.Delete(requestContext(c), id, existing.Version); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo eliminar la experiencia", err.Error())
 	}
 
+	s.related.forTenant(tenantID(c)).remove(id)
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeDeleted, existing, existing.Visibility)
 	return apiresponse.Success(c, fiber.Map{"deleted": true, "id": id})
 }
+
+// ReorderExperiences godoc
+// @Summary      Reordenar experiencias
+// @Description  Actualiza position (y opcionalmente pinned) de varias experiencias en una sola operacion atomica. Si algun ID no existe no se aplica ningun cambio. Requiere JWT.
+// @Tags         Experiences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        order  body  object{items=[]object{id=string,position=int,pinned=bool}}  true  "Nuevo orden"
+// @Success      200  {object}  map[string]interface{}  "updated, items"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/experiences/order [put]
+func (s *ExperienceService) ReorderExperiences(c fiber.Ctx) error {
+	var payload reorderPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	if len(payload.Items) == 0 || len(payload.Items) > constants.MaxReorderItems {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_order", "La lista de posiciones esta vacia o es demasiado grande", nil)
+	}
+
+	updates := make([]repository.PositionUpdate, 0, len(payload.Items))
+	seen := map[string]bool{}
+	for _, item := range payload.Items {
+		if !validatePayloadID(item.ID) {
+			return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", item.ID)
+		}
+		if seen[item.ID] {
+			return apiresponse.Error(c, fiber.StatusBadRequest, "duplicate_id", "ID repetido en la lista de posiciones", item.ID)
+		}
+		seen[item.ID] = true
+		updates = append(updates, repository.PositionUpdate{ID: item.ID, Position: item.Position, Pinned: item.Pinned})
+	}
+
+	if err := s.repo.UpdatePositions(requestContext(c), updates); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", err.Error())
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo actualizar el orden", err.Error())
+	}
+
+	invalidatePublicStats()
+	all, err := s.repo.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+	related := s.related.forTenant(tenantID(c))
+	for _, item := range all {
+		if seen[item.ID] {
+			related.upsert(item)
+			publishExperienceChange(repository.ChangeUpdated, item, item.Visibility)
+		}
+	}
+	sortExperiences(all)
+	SignExperienceList(requestContext(c), all)
+
+	return apiresponse.Success(c, fiber.Map{"updated": len(updates), "items": all})
+}
@@ -2,13 +2,17 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	models "backend-yonathan/src/models"
 	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
 	"backend-yonathan/src/repository/memory"
 
 	"github.com/gofiber/fiber/v3"
@@ -114,6 +118,7 @@ func TestUpdateDeleteAndListAllExperiences(t *testing.T) {
 	})
 	updateReq := httptest.NewRequest(http.MethodPut, "/private/experiences/"+id, bytes.NewReader(updateBody))
 	updateReq.Header.Set("Content-Type", "application/json")
+	updateReq.Header.Set("If-Match", createRes.Header.Get("ETag"))
 	updateRes, err := app.Test(updateReq)
 	if err != nil || updateRes.StatusCode != fiber.StatusOK {
 		t.Fatalf("update failed: %v status=%d", err, updateRes.StatusCode)
@@ -126,6 +131,7 @@ func TestUpdateDeleteAndListAllExperiences(t *testing.T) {
 	}
 
 	deleteReq := httptest.NewRequest(http.MethodDelete, "/private/experiences/"+id, nil)
+	deleteReq.Header.Set("If-Match", updateRes.Header.Get("ETag"))
 	deleteRes, err := app.Test(deleteReq)
 	if err != nil || deleteRes.StatusCode != fiber.StatusOK {
 		t.Fatalf("delete failed: %v status=%d", err, deleteRes.StatusCode)
@@ -203,3 +209,242 @@ func TestListPublicExperiencesFiltersPrivate(t *testing.T) {
 		t.Fatalf("expected 0 public experiences, got %v", result["items"])
 	}
 }
+
+func TestReorderExperiencesPinnedFirstThenPosition(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	svc := NewExperienceService(repo)
+
+	app := fiber.New()
+	app.Post("/private/experiences", svc.CreateExperience)
+	app.Put("/private/experiences/order", svc.ReorderExperiences)
+	app.Get("/experiences", svc.ListPublicExperiences)
+
+	ids := make([]string, 0, 3)
+	for _, title := range []string{"A", "B", "C"} {
+		body, _ := json.Marshal(map[string]any{"title": title, "visibility": constants.VisibilityPublic})
+		req := httptest.NewRequest(http.MethodPost, "/private/experiences", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := app.Test(req)
+		if err != nil || res.StatusCode != fiber.StatusOK {
+			t.Fatalf("create failed: err=%v status=%d", err, res.StatusCode)
+		}
+		raw, _ := io.ReadAll(res.Body)
+		var created map[string]any
+		_ = json.Unmarshal(raw, &created)
+		if created["position"] != float64(len(ids)) {
+			t.Fatalf("expected new item appended at position %d, got %v", len(ids), created["position"])
+		}
+		ids = append(ids, created["id"].(string))
+	}
+
+	orderBody, _ := json.Marshal(map[string]any{"items": []map[string]any{
+		{"id": ids[0], "position": 2},
+		{"id": ids[1], "position": 0},
+		{"id": ids[2], "position": 1, "pinned": true},
+	}})
+	req := httptest.NewRequest(http.MethodPut, "/private/experiences/order", bytes.NewReader(orderBody))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("reorder failed: err=%v status=%d", err, res.StatusCode)
+	}
+
+	res, err = app.Test(httptest.NewRequest(http.MethodGet, "/experiences", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("list failed: err=%v status=%d", err, res.StatusCode)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var result struct {
+		Items []struct {
+			Title string `json:"title"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	got := ""
+	for _, item := range result.Items {
+		got += item.Title
+	}
+	if got != "CBA" {
+		t.Fatalf("expected order CBA (pinned first, then position), got %s", got)
+	}
+}
+
+func TestReorderExperiencesIsAllOrNothing(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	svc := NewExperienceService(repo)
+
+	app := fiber.New()
+	app.Put("/private/experiences/order", svc.ReorderExperiences)
+
+	existing := models.Experience{ID: "00000000-0000-0000-0000-000000000001", Title: "A", Position: 3}
+	_ = repo.Create(context.Background(), existing)
+
+	body, _ := json.Marshal(map[string]any{"items": []map[string]any{
+		{"id": existing.ID, "position": 0},
+		{"id": "00000000-0000-0000-0000-000000000099", "position": 1},
+	}})
+	req := httptest.NewRequest(http.MethodPut, "/private/experiences/order", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for unknown ID, got %d", res.StatusCode)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), existing.ID)
+	if stored.Position != 3 {
+		t.Fatalf("expected position unchanged after failed reorder, got %d", stored.Position)
+	}
+
+	dup, _ := json.Marshal(map[string]any{"items": []map[string]any{
+		{"id": existing.ID, "position": 0},
+		{"id": existing.ID, "position": 1},
+	}})
+	req = httptest.NewRequest(http.MethodPut, "/private/experiences/order", bytes.NewReader(dup))
+	req.Header.Set("Content-Type", "application/json")
+	res, err = app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for duplicate IDs, got err=%v status=%d", err, res.StatusCode)
+	}
+}
+
+func TestUpdateExperienceRequiresMatchingVersion(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	svc := NewExperienceService(repo)
+	id := "00000000-0000-0000-0000-000000000001"
+	_ = repo.Create(context.Background(), models.Experience{ID: id, Title: "Original", Version: 3})
+
+	app := fiber.New()
+	app.Get("/private/experiences/:id", svc.GetExperience)
+	app.Put("/private/experiences/:id", svc.UpdateExperience)
+	app.Delete("/private/experiences/:id", svc.DeleteExperience)
+
+	getRes, err := app.Test(httptest.NewRequest(http.MethodGet, "/private/experiences/"+id, nil))
+	if err != nil || getRes.StatusCode != fiber.StatusOK {
+		t.Fatalf("get failed: %v status=%d", err, getRes.StatusCode)
+	}
+	if etag := getRes.Header.Get("ETag"); etag != `"3"` {
+		t.Fatalf("expected version ETag, got %q", etag)
+	}
+
+	put := func(ifMatch string) *http.Response {
+		body, _ := json.Marshal(map[string]any{"title": "Editado"})
+		req := httptest.NewRequest(http.MethodPut, "/private/experiences/"+id, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("unexpected update error: %v", err)
+		}
+		return res
+	}
+
+	if res := put(""); res.StatusCode != fiber.StatusPreconditionRequired {
+		t.Fatalf("expected 428 without If-Match, got %d", res.StatusCode)
+	}
+	if res := put(`"2"`); res.StatusCode != fiber.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for stale version, got %d", res.StatusCode)
+	}
+	res := put(`"3"`)
+	if res.StatusCode != fiber.StatusOK || res.Header.Get("ETag") != `"4"` {
+		t.Fatalf("expected 200 with new ETag, got %d %q", res.StatusCode, res.Header.Get("ETag"))
+	}
+	if res := put(`"3"`); res.StatusCode != fiber.StatusPreconditionFailed {
+		t.Fatalf("expected 412 when reusing the old ETag, got %d", res.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/private/experiences/"+id, nil)
+	req.Header.Set("If-Match", `"3"`)
+	if res, _ := app.Test(req); res.StatusCode != fiber.StatusPreconditionFailed {
+		t.Fatalf("expected 412 on stale delete, got %d", res.StatusCode)
+	}
+	stored, _ := repo.GetByID(context.Background(), id)
+	if stored.Title != "Editado" || stored.Version != 4 {
+		t.Fatalf("unexpected stored experience: %+v", stored)
+	}
+}
+
+func TestExperienceRepositoryCompareAndSwap(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, models.Experience{ID: "a", Title: "v1", Version: 1})
+
+	if err := repo.Update(ctx, models.Experience{ID: "a", Title: "first"}, 1); err != nil {
+		t.Fatalf("unexpected update error: %v", err)
+	}
+	if err := repo.Update(ctx, models.Experience{ID: "a", Title: "second"}, 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if err := repo.Delete(ctx, "a", 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected version conflict on delete, got %v", err)
+	}
+	stored, _ := repo.GetByID(ctx, "a")
+	if stored.Title != "first" || stored.Version != 2 {
+		t.Fatalf("unexpected stored experience: %+v", stored)
+	}
+}
+
+func TestPatchExperience(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	svc := NewExperienceService(repo)
+	id := "00000000-0000-0000-0000-000000000001"
+	_ = repo.Create(context.Background(), models.Experience{
+		ID: id, Title: "Original", Summary: "Resumen", Body: "<p>Body</p>",
+		Tags: []string{"go"}, Visibility: constants.VisibilityPublic, Version: 1,
+	})
+
+	app := fiber.New()
+	app.Patch("/private/experiences/:id", svc.PatchExperience)
+
+	patch := func(contentType, ifMatch, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPatch, "/private/experiences/"+id, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("If-Match", ifMatch)
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("unexpected patch error: %v", err)
+		}
+		return res
+	}
+
+	res := patch("application/merge-patch+json", `"1"`, `{"title":"Solo titulo"}`)
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("merge patch failed: %d", res.StatusCode)
+	}
+	stored, _ := repo.GetByID(context.Background(), id)
+	if stored.Title != "Solo titulo" || stored.Summary != "Resumen" || stored.Body != "<p>Body</p>" || len(stored.Tags) != 1 {
+		t.Fatalf("merge patch should keep untouched fields, got %+v", stored)
+	}
+
+	res = patch("application/json-patch+json", `"2"`, `[{"op":"add","path":"/tags/-","value":"API"},{"op":"replace","path":"/visibility","value":"private"}]`)
+	if res.StatusCode != fiber.StatusOK || res.Header.Get("ETag") != `"3"` {
+		t.Fatalf("json patch failed: %d %q", res.StatusCode, res.Header.Get("ETag"))
+	}
+	stored, _ = repo.GetByID(context.Background(), id)
+	if len(stored.Tags) != 2 || stored.Tags[1] != "api" || stored.Visibility != constants.VisibilityPrivate {
+		t.Fatalf("expected sanitized tags and new visibility, got %+v", stored)
+	}
+
+	cases := []struct {
+		name, contentType, body string
+		want                    int
+	}{
+		{"unsupported type", "application/json", `{"title":"x"}`, fiber.StatusUnsupportedMediaType},
+		{"failed test op", "application/json-patch+json", `[{"op":"test","path":"/title","value":"otro"}]`, fiber.StatusConflict},
+		{"bad path", "application/json-patch+json", `[{"op":"remove","path":"/missing"}]`, fiber.StatusUnprocessableEntity},
+		{"empty title", "application/merge-patch+json", `{"title":null}`, fiber.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := patch(tc.contentType, `"3"`, tc.body); res.StatusCode != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, res.StatusCode)
+			}
+		})
+	}
+}
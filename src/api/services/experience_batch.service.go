@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/validation"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// Batch operation names accepted by POST /api/private/experiences/batch.
+const (
+	batchOpCreate        = "create"
+	batchOpUpdate        = "update"
+	batchOpDelete        = "delete"
+	batchOpSetVisibility = "setVisibility"
+	batchOpAddTag        = "addTag"
+)
+
+// batchPayload is the request body of POST /api/private/experiences/batch.
+type batchPayload struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []batchOperation `json:"operations"`
+}
+
+// batchOperation is one operation of a batch. Version is required by every
+// operation but create and must match the stored version of the target
+// experience.
+type batchOperation struct {
+	Op         string             `json:"op"`
+	ID         string             `json:"id"`
+	Version    *int64             `json:"version"`
+	Data       *experiencePayload `json:"data"`
+	Visibility string             `json:"visibility"`
+	Tag        string             `json:"tag"`
+}
+
+// batchResult reports the outcome of one operation.
+type batchResult struct {
+	Index   int                     `json:"index"`
+	Op      string                  `json:"op"`
+	ID      string                  `json:"id,omitempty"`
+	Status  int                     `json:"status"`
+	Code    string                  `json:"code,omitempty"`
+	Message string                  `json:"message,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
+	Item    *models.Experience      `json:"item,omitempty"`
+}
+
+// plannedWrite links a repository write to the operation that produced it.
+type plannedWrite struct {
+	index int
+	write repository.ExperienceWrite
+}
+
+func (r *batchResult) fail(status int, code, message string) {
+	r.Status = status
+	r.Code = code
+	r.Message = message
+	r.Item = nil
+}
+
+// planBatch validates every operation against a working copy of the stored
+// experiences, so later operations see the effect of earlier ones. It returns
+// one result per operation and the writes of the operations that succeeded.
+func planBatch(ctx context.Context, ops []batchOperation, current []models.Experience) ([]batchResult, []plannedWrite) {
+	working := make(map[string]models.Experience, len(current))
+	for _, item := range current {
+		working[item.ID] = item
+	}
+	position := nextPosition(current)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	results := make([]batchResult, len(ops))
+	writes := make([]plannedWrite, 0, len(ops))
+	for i, op := range ops {
+		result := &results[i]
+		*result = batchResult{Index: i, Op: op.Op, ID: op.ID, Status: fiber.StatusOK}
+
+		if op.Op == batchOpCreate {
+			if op.Data == nil {
+				result.fail(fiber.StatusBadRequest, "invalid_payload", "La operacion create requiere data")
+				continue
+			}
+			payload := *op.Data
+			sanitizePayload(ctx, &payload)
+			if payload.Title == "" {
+				result.fail(fiber.StatusBadRequest, "missing_title", "El titulo es requerido")
+				continue
+			}
+			item := models.Experience{
+				ID:         uuid.NewString(),
+				Title:      payload.Title,
+				Summary:    payload.Summary,
+				Body:       payload.Body,
+				ImageURLs:  payload.ImageURLs,
+				Tags:       payload.Tags,
+				Visibility: payload.Visibility,
+				Position:   position,
+				Version:    1,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if errs := validateContent(constants.ValidationCollectionExperiences, item); len(errs) > 0 {
+				result.fail(fiber.StatusBadRequest, "validation_failed", "El contenido no cumple las reglas de validacion")
+				result.Errors = errs
+				continue
+			}
+			position++
+			working[item.ID] = item
+			result.ID = item.ID
+			result.Item = &item
+			writes = append(writes, plannedWrite{i, repository.ExperienceWrite{Kind: repository.WriteCreate, Experience: item}})
+			continue
+		}
+
+		if !validatePayloadID(op.ID) {
+			result.fail(fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido")
+			continue
+		}
+		existing, ok := working[op.ID]
+		if !ok {
+			result.fail(fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada")
+			continue
+		}
+		if op.Version == nil {
+			result.fail(fiber.StatusPreconditionRequired, "precondition_required", "Se requiere el campo version con la version actual")
+			continue
+		}
+		if *op.Version != existing.Version {
+			result.fail(fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo")
+			continue
+		}
+		expected := existing.Version
+
+		switch op.Op {
+		case batchOpDelete:
+			delete(working, op.ID)
+			writes = append(writes, plannedWrite{i, repository.ExperienceWrite{Kind: repository.WriteDelete, Experience: existing, ExpectedVersion: expected}})
+			continue
+		case batchOpUpdate:
+			if op.Data == nil {
+				result.fail(fiber.StatusBadRequest, "invalid_payload", "La operacion update requiere data")
+				continue
+			}
+			payload := *op.Data
+			sanitizePayload(ctx, &payload)
+			if payload.Title != "" {
+				existing.Title = payload.Title
+			}
+			existing.Summary = payload.Summary
+			existing.Body = payload.Body
+			existing.ImageURLs = payload.ImageURLs
+			existing.Tags = payload.Tags
+			existing.Visibility = payload.Visibility
+		case batchOpSetVisibility:
+			visibility := strings.ToLower(strings.TrimSpace(op.Visibility))
+			if visibility != constants.VisibilityPublic && visibility != constants.VisibilityPrivate {
+				result.fail(fiber.StatusBadRequest, "invalid_visibility", "La visibilidad debe ser public o private")
+				continue
+			}
+			existing.Visibility = visibility
+		case batchOpAddTag:
+			tag := normalizeTag(op.Tag)
+			if tag == "" {
+				result.fail(fiber.StatusBadRequest, "invalid_tag", "El tag es requerido")
+				continue
+			}
+			existing.Tags = normalizeTags(ctx, append(append([]string{}, existing.Tags...), tag))
+		default:
+			result.fail(fiber.StatusBadRequest, "invalid_operation", "Operacion no soportada")
+			continue
+		}
+
+		if errs := validateContent(constants.ValidationCollectionExperiences, existing); len(errs) > 0 {
+			result.fail(fiber.StatusBadRequest, "validation_failed", "El contenido no cumple las reglas de validacion")
+			result.Errors = errs
+			continue
+		}
+
+		existing.UpdatedAt = now
+		existing.Version = expected + 1
+		working[op.ID] = existing
+		item := existing
+		result.Item = &item
+		writes = append(writes, plannedWrite{i, repository.ExperienceWrite{Kind: repository.WriteUpdate, Experience: existing, ExpectedVersion: expected}})
+	}
+	return results, writes
+}
+
+// applyWrite persists a single planned write through the regular repository methods.
+func (s *ExperienceService) applyWrite(ctx context.Context, w repository.ExperienceWrite) error {
+	switch w.Kind {
+	case repository.WriteCreate:
+		return s.repo.Create(ctx, w.Experience)
+	case repository.WriteUpdate:
+		return s.repo.Update(ctx, w.Experience, w.ExpectedVersion)
+	default:
+		return s.repo.Delete(ctx, w.Experience.ID, w.ExpectedVersion)
+	}
+}
+
+// reindex keeps the related-content index of tenant and the stats cache in
+// sync with an applied write and reports it to the change feed. visibility
+// holds the stored visibility of each experience and is updated with the
+// write.
+func (s *ExperienceService) reindex(tenant string, w repository.ExperienceWrite, visibility map[string]string) {
+	related := s.related.forTenant(tenant)
+	invalidatePublicStats()
+	previous := visibility[w.Experience.ID]
+	switch w.Kind {
+	case repository.WriteDelete:
+		related.remove(w.Experience.ID)
+		delete(visibility, w.Experience.ID)
+		publishExperienceChange(repository.ChangeDeleted, w.Experience, w.Experience.Visibility)
+	case repository.WriteCreate:
+		related.upsert(w.Experience)
+		visibility[w.Experience.ID] = w.Experience.Visibility
+		publishExperienceChange(repository.ChangeCreated, w.Experience, "")
+	default:
+		related.upsert(w.Experience)
+		visibility[w.Experience.ID] = w.Experience.Visibility
+		publishExperienceChange(repository.ChangeUpdated, w.Experience, previous)
+	}
+}
+
+// writeFailure maps a repository write error to a batch result.
+func writeFailure(result *batchResult, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		result.fail(fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada")
+	case errors.Is(err, repository.ErrVersionConflict):
+		result.fail(fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo")
+	default:
+		result.fail(fiber.StatusInternalServerError, "save_experience_failed", "No se pudo guardar la experiencia")
+	}
+}
+
+// BatchExperiences godoc
+// @Summary      Operaciones en lote sobre experiencias
+// @Description  Ejecuta una lista de operaciones (create, update, delete, setVisibility, addTag) sobre experiencias y skills. Con atomic=true se aplican todas o ninguna (transaccion en Firestore, una sola escritura en JSON); si no, cada operacion se aplica por separado. Devuelve un resultado por operacion. Requiere JWT.
+// @Tags         Experiences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        batch  body  object{atomic=bool,operations=[]object{op=string,id=string,version=int,data=object,visibility=string,tag=string}}  true  "Operaciones"
+// @Success      200  {object}  map[string]interface{}  "atomic, applied, failed, results"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Failure      501  {object}  map[string]interface{}
+// @Router       /api/private/experiences/batch [post]
+func (s *ExperienceService) BatchExperiences(c fiber.Ctx) error {
+	var payload batchPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	if len(payload.Operations) == 0 || len(payload.Operations) > constants.MaxBatchOperations {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_batch", "La lista de operaciones esta vacia o es demasiado grande", nil)
+	}
+
+	ctx := requestContext(c)
+	current, err := s.repo.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	results, planned := planBatch(ctx, payload.Operations, current)
+	tenant := tenantID(c)
+	for i := range planned {
+		if planned[i].write.Kind == repository.WriteCreate {
+			planned[i].write.Experience.TenantID = tenant
+		}
+	}
+	failed := len(results) - len(planned)
+	visibility := make(map[string]string, len(current))
+	for _, item := range current {
+		visibility[item.ID] = item.Visibility
+	}
+
+	if payload.Atomic {
+		if failed > 0 {
+			return apiresponse.Error(c, fiber.StatusConflict, "batch_rejected", "Alguna operacion no es valida; no se aplico ningun cambio", results)
+		}
+		batcher, ok := s.repo.(repository.BatchExperienceRepository)
+		if !ok {
+			return apiresponse.Error(c, fiber.StatusNotImplemented, "batch_not_supported", "El almacenamiento actual no soporta lotes atomicos", nil)
+		}
+		writes := make([]repository.ExperienceWrite, len(planned))
+		for i, p := range planned {
+			writes[i] = p.write
+		}
+		if err := batcher.ApplyBatch(ctx, writes); err != nil {
+			if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrVersionConflict) {
+				return apiresponse.Error(c, fiber.StatusConflict, "batch_rejected", "Otro cambio se aplico mientras tanto; no se aplico ningun cambio", err.Error())
+			}
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo aplicar el lote", err.Error())
+		}
+		for _, w := range writes {
+			s.reindex(tenant, w, visibility)
+		}
+	} else {
+		for _, p := range planned {
+			if err := s.applyWrite(ctx, p.write); err != nil {
+				writeFailure(&results[p.index], err)
+				failed++
+				continue
+			}
+			s.reindex(tenant, p.write, visibility)
+		}
+	}
+
+	for i := range results {
+		if results[i].Item != nil {
+			signed := []models.Experience{*results[i].Item}
+			SignExperienceList(ctx, signed)
+			results[i].Item = &signed[0]
+		}
+	}
+
+	return apiresponse.Success(c, fiber.Map{
+		"atomic":  payload.Atomic,
+		"applied": len(results) - failed,
+		"failed":  failed,
+		"results": results,
+	})
+}
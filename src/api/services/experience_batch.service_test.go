@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	batchIDOne = "00000000-0000-0000-0000-000000000001"
+	batchIDTwo = "00000000-0000-0000-0000-000000000002"
+)
+
+func newBatchTestRepo() *memory.ExperienceRepository {
+	repo := memory.NewExperienceRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, models.Experience{ID: batchIDOne, Title: "Uno", Tags: []string{"go"}, Visibility: constants.VisibilityPublic, Version: 1})
+	_ = repo.Create(ctx, models.Experience{ID: batchIDTwo, Title: "Dos", Visibility: constants.VisibilityPublic, Version: 1})
+	return repo
+}
+
+func postBatch(t *testing.T, repo repository.ExperienceRepository, payload map[string]any) (int, map[string]any) {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/private/experiences/batch", NewExperienceService(repo).BatchExperiences)
+
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/private/experiences/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected batch error: %v", err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var decoded map[string]any
+	_ = json.Unmarshal(raw, &decoded)
+	return res.StatusCode, decoded
+}
+
+func TestBatchExperiencesAtomic(t *testing.T) {
+	repo := newBatchTestRepo()
+
+	status, result := postBatch(t, repo, map[string]any{
+		"atomic": true,
+		"operations": []map[string]any{
+			{"op": "setVisibility", "id": batchIDOne, "visibility": "private", "version": 1},
+			{"op": "addTag", "id": batchIDOne, "tag": "API", "version": 2},
+			{"op": "delete", "id": batchIDTwo, "version": 1},
+			{"op": "create", "data": map[string]any{"title": "Nueva", "tags": []string{"cloud"}}},
+		},
+	})
+	if status != fiber.StatusOK || result["applied"] != float64(4) {
+		t.Fatalf("expected 4 applied operations, got %d %v", status, result)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), batchIDOne)
+	if stored.Visibility != constants.VisibilityPrivate || len(stored.Tags) != 2 || stored.Version != 3 {
+		t.Fatalf("expected chained updates on the same item, got %+v", stored)
+	}
+	all, _ := repo.List(context.Background())
+	if len(all) != 2 || all[1].Title != "Nueva" {
+		t.Fatalf("expected delete and create applied, got %+v", all)
+	}
+}
+
+func TestBatchExperiencesAtomicRejectsWholeBatch(t *testing.T) {
+	repo := newBatchTestRepo()
+
+	status, result := postBatch(t, repo, map[string]any{
+		"atomic": true,
+		"operations": []map[string]any{
+			{"op": "setVisibility", "id": batchIDOne, "visibility": "private", "version": 1},
+			{"op": "delete", "id": batchIDTwo, "version": 7},
+		},
+	})
+	if status != fiber.StatusConflict || result["code"] != "batch_rejected" {
+		t.Fatalf("expected 409 batch_rejected, got %d %v", status, result)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), batchIDOne)
+	if stored.Visibility != constants.VisibilityPublic || stored.Version != 1 {
+		t.Fatalf("expected nothing applied, got %+v", stored)
+	}
+}
+
+func TestBatchExperiencesPartial(t *testing.T) {
+	repo := newBatchTestRepo()
+
+	status, result := postBatch(t, repo, map[string]any{
+		"operations": []map[string]any{
+			{"op": "setVisibility", "id": batchIDOne, "visibility": "hidden", "version": 1},
+			{"op": "update", "id": batchIDTwo, "version": 1, "data": map[string]any{"title": "Dos editado", "visibility": "private"}},
+			{"op": "delete", "id": "00000000-0000-0000-0000-000000000099", "version": 1},
+		},
+	})
+	if status != fiber.StatusOK || result["applied"] != float64(1) || result["failed"] != float64(2) {
+		t.Fatalf("expected 1 applied and 2 failed, got %d %v", status, result)
+	}
+	results, _ := result["results"].([]any)
+	codes := []string{}
+	for _, r := range results {
+		entry, _ := r.(map[string]any)
+		code, _ := entry["code"].(string)
+		codes = append(codes, code)
+	}
+	if codes[0] != "invalid_visibility" || codes[1] != "" || codes[2] != "experience_not_found" {
+		t.Fatalf("unexpected per-item codes: %v", codes)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), batchIDTwo)
+	if stored.Title != "Dos editado" || stored.Visibility != constants.VisibilityPrivate {
+		t.Fatalf("expected valid operation applied, got %+v", stored)
+	}
+}
+
+func TestBatchExperiencesRequireVersion(t *testing.T) {
+	repo := newBatchTestRepo()
+
+	status, result := postBatch(t, repo, map[string]any{
+		"operations": []map[string]any{
+			{"op": "setVisibility", "id": batchIDOne, "visibility": "private"},
+			{"op": "delete", "id": batchIDTwo},
+			{"op": "create", "data": map[string]any{"title": "Nueva"}},
+		},
+	})
+	if status != fiber.StatusOK || result["applied"] != float64(1) || result["failed"] != float64(2) {
+		t.Fatalf("expected only the create applied, got %d %v", status, result)
+	}
+	results, _ := result["results"].([]any)
+	for _, r := range results[:2] {
+		entry, _ := r.(map[string]any)
+		if entry["status"] != float64(fiber.StatusPreconditionRequired) || entry["code"] != "precondition_required" {
+			t.Fatalf("expected 428 precondition_required, got %v", entry)
+		}
+	}
+
+	stored, _ := repo.GetByID(context.Background(), batchIDOne)
+	if stored.Visibility != constants.VisibilityPublic || stored.Version != 1 {
+		t.Fatalf("expected item without version untouched, got %+v", stored)
+	}
+	if _, err := repo.GetByID(context.Background(), batchIDTwo); err != nil {
+		t.Fatalf("expected item without version not deleted, got %v", err)
+	}
+}
+
+// plainExperienceRepository hides ApplyBatch to simulate a backend without atomic batches.
+type plainExperienceRepository struct {
+	repository.ExperienceRepository
+}
+
+func TestBatchExperiencesAtomicRequiresBackendSupport(t *testing.T) {
+	repo := plainExperienceRepository{newBatchTestRepo()}
+
+	status, result := postBatch(t, repo, map[string]any{
+		"atomic":     true,
+		"operations": []map[string]any{{"op": "setVisibility", "id": batchIDOne, "visibility": "private", "version": 1}},
+	})
+	if status != fiber.StatusNotImplemented {
+		t.Fatalf("expected 501, got %d %v", status, result)
+	}
+
+	status, _ = postBatch(t, repo, map[string]any{
+		"operations": []map[string]any{{"op": "setVisibility", "id": batchIDOne, "visibility": "private", "version": 1}},
+	})
+	if status != fiber.StatusOK {
+		t.Fatalf("expected non-atomic batch to work without backend support, got %d", status)
+	}
+}
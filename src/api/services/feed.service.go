@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"path"
+	"sort"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// FeedService renders public experiences as RSS 2.0, Atom and JSON Feed.
+type FeedService struct {
+	repo repository.ExperienceRepository
+}
+
+// NewFeedService creates a FeedService backed by the given ExperienceRepository.
+func NewFeedService(repo repository.ExperienceRepository) *FeedService {
+	return &FeedService{repo: repo}
+}
+
+// --- RSS 2.0 ---
+
+type rssDocument struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr"`
+	XMLNSAtom    string     `xml:"xmlns:atom,attr"`
+	Channel      rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Description string        `xml:"description"`
+	Content     xmlCDATA      `xml:"content:encoded"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type xmlCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+// --- Atom ---
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// --- JSON Feed 1.1 ---
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Authors     []jsonFeedUser `json:"authors"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedUser struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags"`
+}
+
+// experienceURL returns the public frontend URL of an experience.
+func experienceURL(id string) string {
+	return constants.PublicSiteURL() + "/experiences/" + id
+}
+
+// formatFeedDate converts an RFC3339 timestamp to the given layout, or "" if unparsable.
+func formatFeedDate(value, layout string) string {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ""
+	}
+	return parsed.UTC().Format(layout)
+}
+
+// imageContentType guesses the MIME type of an image URL from its extension.
+func imageContentType(rawURL string) string {
+	if ct := mime.TypeByExtension(path.Ext(stripQueryParams(rawURL))); ct != "" {
+		return ct
+	}
+	return "image/jpeg"
+}
+
+// feedItems loads public experiences sorted by CreatedAt (newest first),
+// limited to FeedMaxItems. The ETag is computed before signing image URLs, as
+// signatures change on every request.
+func (s *FeedService) feedItems(ctx context.Context) ([]models.Experience, string, error) {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items := make([]models.Experience, 0, len(all))
+	for _, item := range all {
+		if item.Visibility == constants.VisibilityPublic {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt > items[j].CreatedAt })
+	if len(items) > constants.FeedMaxItems {
+		items = items[:constants.FeedMaxItems]
+	}
+
+	etag := buildCollectionETag(items)
+	SignExperienceList(ctx, items)
+	return items, etag, nil
+}
+
+// serveFeed handles the shared conditional GET flow for all feed formats.
+func (s *FeedService) serveFeed(c fiber.Ctx, contentType string, render func([]models.Experience, string) ([]byte, error)) error {
+	items, etag, err := s.feedItems(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	etag = tenantETag(c, etag)
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	body, err := render(items, c.BaseURL()+c.Path())
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "feed_render_failed", "No se pudo generar el feed", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+// lastUpdated returns the most recent UpdatedAt among items, or now when empty.
+func lastUpdated(items []models.Experience) string {
+	latest := ""
+	for _, item := range items {
+		if item.UpdatedAt > latest {
+			latest = item.UpdatedAt
+		}
+	}
+	if latest == "" {
+		return time.Now().UTC().Format(time.RFC3339)
+	}
+	return latest
+}
+
+func renderRSS(items []models.Experience, selfURL string) ([]byte, error) {
+	doc := rssDocument{
+		Version:      "2.0",
+		XMLNSContent: "http://purl.org/rss/1.0/modules/content/",
+		XMLNSAtom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         constants.FeedTitle,
+			Link:          constants.PublicSiteURL(),
+			Description:   constants.FeedDescription,
+			Language:      constants.FeedLanguage,
+			LastBuildDate: formatFeedDate(lastUpdated(items), time.RFC1123Z),
+			AtomLink:      atomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, 0, len(items)),
+		},
+	}
+	for _, item := range items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        experienceURL(item.ID),
+			GUID:        rssGUID{IsPermaLink: "false", Value: item.ID},
+			PubDate:     formatFeedDate(item.CreatedAt, time.RFC1123Z),
+			Description: item.Summary,
+			Content:     xmlCDATA{Value: item.Body},
+			Categories:  item.Tags,
+		}
+		if len(item.ImageURLs) > 0 {
+			entry.Enclosure = &rssEnclosure{URL: item.ImageURLs[0], Type: imageContentType(item.ImageURLs[0]), Length: "0"}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func renderAtom(items []models.Experience, selfURL string) ([]byte, error) {
+	feed := atomFeed{
+		Title:   constants.FeedTitle,
+		ID:      constants.PublicSiteURL() + "/",
+		Updated: lastUpdated(items),
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: constants.PublicSiteURL(), Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomPerson{Name: constants.PortfolioAuthor()},
+		Entries: make([]atomEntry, 0, len(items)),
+	}
+	for _, item := range items {
+		updated := item.UpdatedAt
+		if updated == "" {
+			updated = item.CreatedAt
+		}
+		entry := atomEntry{
+			ID:        "urn:uuid:" + item.ID,
+			Title:     item.Title,
+			Updated:   updated,
+			Published: item.CreatedAt,
+			Links:     []atomLink{{Href: experienceURL(item.ID), Rel: "alternate", Type: "text/html"}},
+			Summary:   item.Summary,
+			Content:   atomContent{Type: "html", Value: item.Body},
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		for _, img := range item.ImageURLs {
+			entry.Links = append(entry.Links, atomLink{Href: img, Rel: "enclosure", Type: imageContentType(img)})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func renderJSONFeed(items []models.Experience, selfURL string) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       constants.FeedTitle,
+		HomePageURL: constants.PublicSiteURL(),
+		FeedURL:     selfURL,
+		Description: constants.FeedDescription,
+		Language:    constants.FeedLanguage,
+		Authors:     []jsonFeedUser{{Name: constants.PortfolioAuthor()}},
+		Items:       make([]jsonFeedItem, 0, len(items)),
+	}
+	for _, item := range items {
+		entry := jsonFeedItem{
+			ID:            item.ID,
+			URL:           experienceURL(item.ID),
+			Title:         item.Title,
+			ContentHTML:   item.Body,
+			Summary:       item.Summary,
+			DatePublished: item.CreatedAt,
+			DateModified:  item.UpdatedAt,
+			Tags:          item.Tags,
+		}
+		if entry.Tags == nil {
+			entry.Tags = []string{}
+		}
+		if len(item.ImageURLs) > 0 {
+			entry.Image = item.ImageURLs[0]
+		}
+		feed.Items = append(feed.Items, entry)
+	}
+	return json.Marshal(feed)
+}
+
+// RSSFeed godoc
+// @Summary      Feed RSS
+// @Description  Feed RSS 2.0 de experiencias publicas ordenadas por fecha de creacion. Soporta ETag/If-None-Match.
+// @Tags         Feeds
+// @Produce      xml
+// @Success      200  {string}  string  "RSS 2.0"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/feed.rss [get]
+func (s *FeedService) RSSFeed(c fiber.Ctx) error {
+	return s.serveFeed(c, "application/rss+xml; charset=utf-8", renderRSS)
+}
+
+// AtomFeed godoc
+// @Summary      Feed Atom
+// @Description  Feed Atom 1.0 de experiencias publicas ordenadas por fecha de creacion. Soporta ETag/If-None-Match.
+// @Tags         Feeds
+// @Produce      xml
+// @Success      200  {string}  string  "Atom 1.0"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/feed.atom [get]
+func (s *FeedService) AtomFeed(c fiber.Ctx) error {
+	return s.serveFeed(c, "application/atom+xml; charset=utf-8", renderAtom)
+}
+
+// JSONFeed godoc
+// @Summary      JSON Feed
+// @Description  JSON Feed 1.1 de experiencias publicas ordenadas por fecha de creacion. Soporta ETag/If-None-Match.
+// @Tags         Feeds
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/feed.json [get]
+func (s *FeedService) JSONFeed(c fiber.Ctx) error {
+	return s.serveFeed(c, "application/feed+json; charset=utf-8", renderJSONFeed)
+}
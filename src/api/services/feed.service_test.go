@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func newFeedTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	t.Setenv("PUBLIC_SITE_URL", "https://example.dev/")
+	repo := memory.NewExperienceRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, models.Experience{
+		ID: "00000000-0000-0000-0000-000000000001", Title: "Antigua", Summary: "uno",
+		Body: "<p>primero</p>", Tags: []string{"go"}, Visibility: constants.VisibilityPublic,
+		CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-01-01T00:00:00Z",
+	})
+	_ = repo.Create(ctx, models.Experience{
+		ID: "00000000-0000-0000-0000-000000000002", Title: "Reciente", Summary: "dos",
+		Body: "<p>segundo</p>", Tags: []string{"api", "cloud"}, ImageURLs: []string{"https://cdn.example.dev/a.png"},
+		Visibility: constants.VisibilityPublic, CreatedAt: "2024-03-01T00:00:00Z", UpdatedAt: "2024-03-02T00:00:00Z",
+	})
+	_ = repo.Create(ctx, models.Experience{
+		ID: "00000000-0000-0000-0000-000000000003", Title: "Borrador",
+		Visibility: constants.VisibilityPrivate, CreatedAt: "2024-05-01T00:00:00Z",
+	})
+
+	svc := NewFeedService(repo)
+	app := fiber.New()
+	app.Get("/feed.rss", svc.RSSFeed)
+	app.Get("/feed.atom", svc.AtomFeed)
+	app.Get("/feed.json", svc.JSONFeed)
+	return app
+}
+
+func TestRSSFeedListsPublicExperiencesNewestFirst(t *testing.T) {
+	app := newFeedTestApp(t)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed.rss", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("rss failed: err=%v status=%d", err, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	raw, _ := io.ReadAll(res.Body)
+
+	var doc rssDocument
+	if err := xml.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("invalid rss: %v", err)
+	}
+	items := doc.Channel.Items
+	if len(items) != 2 || items[0].Title != "Reciente" || items[1].Title != "Antigua" {
+		t.Fatalf("expected public items newest first, got %+v", items)
+	}
+	if items[0].Link != "https://example.dev/experiences/00000000-0000-0000-0000-000000000002" {
+		t.Errorf("unexpected link %q", items[0].Link)
+	}
+	if len(items[0].Categories) != 2 {
+		t.Errorf("expected tag categories, got %+v", items[0].Categories)
+	}
+	if items[0].Enclosure == nil || items[0].Enclosure.Type != "image/png" {
+		t.Errorf("expected png enclosure, got %+v", items[0].Enclosure)
+	}
+	if !strings.Contains(string(raw), "<content:encoded><![CDATA[<p>segundo</p>]]></content:encoded>") {
+		t.Error("expected body wrapped in CDATA inside content:encoded")
+	}
+}
+
+func TestAtomFeedIncludesEntries(t *testing.T) {
+	app := newFeedTestApp(t)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed.atom", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("atom failed: err=%v status=%d", err, res.StatusCode)
+	}
+	raw, _ := io.ReadAll(res.Body)
+
+	var feed atomFeed
+	if err := xml.Unmarshal(raw, &feed); err != nil {
+		t.Fatalf("invalid atom: %v", err)
+	}
+	if feed.Updated != "2024-03-02T00:00:00Z" {
+		t.Errorf("expected feed updated from latest entry, got %q", feed.Updated)
+	}
+	if len(feed.Entries) != 2 || feed.Entries[0].Content.Value != "<p>segundo</p>" {
+		t.Fatalf("unexpected entries: %+v", feed.Entries)
+	}
+	if len(feed.Entries[0].Categories) != 2 || feed.Entries[0].Categories[0].Term != "api" {
+		t.Errorf("expected tag categories, got %+v", feed.Entries[0].Categories)
+	}
+}
+
+func TestJSONFeedSupportsConditionalGet(t *testing.T) {
+	app := newFeedTestApp(t)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed.json", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("json feed failed: err=%v status=%d", err, res.StatusCode)
+	}
+	etag := res.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+	raw, _ := io.ReadAll(res.Body)
+
+	var feed jsonFeed
+	if err := json.Unmarshal(raw, &feed); err != nil {
+		t.Fatalf("invalid json feed: %v", err)
+	}
+	if feed.Version != "https://jsonfeed.org/version/1.1" || len(feed.Items) != 2 {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+	if feed.Items[0].Image != "https://cdn.example.dev/a.png" || feed.Items[1].Image != "" {
+		t.Errorf("expected image only on first item, got %+v", feed.Items)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/feed.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	res, err = app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("expected 304, got err=%v status=%d", err, res.StatusCode)
+	}
+}
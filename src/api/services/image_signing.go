@@ -8,6 +8,7 @@ import (
 
 	models "backend-yonathan/src/models"
 	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/resilience"
 )
 
 // signURLFunc is injectable for tests. Default: signGCSURL.
@@ -37,7 +38,11 @@ func signSingleURL(ctx context.Context, rawURL, gcsPrefix string, expiry time.Du
 	baseURL := stripQueryParams(rawURL)
 	objectPath := strings.TrimPrefix(baseURL, gcsPrefix)
 	bucket := constants.GCSBucketName()
-	signed, err := signURLFunc(ctx, bucket, objectPath, expiry)
+	var signed string
+	err := resilience.Do(ctx, resilience.BreakerFromEnv(gcsDependency), gcsPolicy(), func(ctx context.Context) (err error) {
+		signed, err = signURLFunc(ctx, bucket, objectPath, expiry)
+		return err
+	})
 	if err != nil {
 		log.Printf("[image_signing] failed to sign %s: %v", objectPath, err)
 		return rawURL
@@ -126,3 +131,27 @@ func SignExperienceList(ctx context.Context, items []models.Experience) {
 		SignExperienceImageURLs(ctx, &items[i])
 	}
 }
+
+// FindBodyImageURLs returns the URLs in an HTML body that start with prefix,
+// without query parameters, in order of appearance.
+func FindBodyImageURLs(body, prefix string) []string {
+	urls := []string{}
+	if prefix == "" {
+		return urls
+	}
+	idx := 0
+	for {
+		pos := strings.Index(body[idx:], prefix)
+		if pos < 0 {
+			break
+		}
+		start := idx + pos
+		end := start + len(prefix)
+		for end < len(body) && body[end] != '"' && body[end] != '\'' && body[end] != ' ' && body[end] != '<' && body[end] != '>' {
+			end++
+		}
+		urls = append(urls, stripQueryParams(body[start:end]))
+		idx = end
+	}
+	return urls
+}
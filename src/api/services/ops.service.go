@@ -35,7 +35,7 @@ func GetOpsAlerts(c fiber.Ctx) error {
 
 // GetOpsHealth godoc
 // @Summary      Estado de salud
-// @Description  Estado de salud con recomendaciones. Requiere JWT.
+// @Description  Estado de salud con recomendaciones y estado de los circuit breakers de dependencias (Firestore, DynamoDB, Postgres, GCS). Requiere JWT.
 // @Tags         Ops
 // @Produce      json
 // @Security     BearerAuth
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/jsonpatch"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// --- PATCH helpers ---
+
+// patchFailure describes why a PATCH request could not be applied.
+type patchFailure struct {
+	status  int
+	code    string
+	message string
+	details interface{}
+}
+
+// patchExperiencePayload applies the body of a PATCH request to the editable
+// fields of existing and returns the sanitized result. The body may be a JSON
+// Merge Patch or a JSON Patch, selected by Content-Type.
+func patchExperiencePayload(c fiber.Ctx, existing models.Experience) (experiencePayload, *patchFailure) {
+	current, err := json.Marshal(experiencePayload{
+		Title:      existing.Title,
+		Summary:    existing.Summary,
+		Body:       existing.Body,
+		ImageURLs:  existing.ImageURLs,
+		Tags:       existing.Tags,
+		Visibility: existing.Visibility,
+	})
+	if err != nil {
+		return experiencePayload{}, &patchFailure{fiber.StatusInternalServerError, "patch_failed", "No se pudo aplicar el parche", err.Error()}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	var patched []byte
+	switch mediaType {
+	case jsonpatch.MergePatchMediaType:
+		patched, err = jsonpatch.MergePatch(current, c.Body())
+	case jsonpatch.JSONPatchMediaType:
+		patched, err = jsonpatch.ApplyPatch(current, c.Body())
+	default:
+		return experiencePayload{}, &patchFailure{fiber.StatusUnsupportedMediaType, "unsupported_patch_type",
+			"Content-Type debe ser application/merge-patch+json o application/json-patch+json", mediaType}
+	}
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return experiencePayload{}, &patchFailure{fiber.StatusConflict, "patch_test_failed", "La operacion test del parche no se cumplio", err.Error()}
+		}
+		return experiencePayload{}, &patchFailure{fiber.StatusUnprocessableEntity, "invalid_patch", "El parche no se pudo aplicar", err.Error()}
+	}
+
+	var payload experiencePayload
+	if err := json.Unmarshal(patched, &payload); err != nil {
+		return experiencePayload{}, &patchFailure{fiber.StatusUnprocessableEntity, "invalid_patch", "El resultado del parche no es valido", err.Error()}
+	}
+	sanitizePayload(requestContext(c), &payload)
+	if payload.Title == "" {
+		return experiencePayload{}, &patchFailure{fiber.StatusBadRequest, "missing_title", "El titulo es requerido", nil}
+	}
+	return payload, nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// relatedItem is one recommendation. Source is "manual" for editor overrides
+// and "auto" for ranked results.
+type relatedItem struct {
+	models.Experience
+	Score  float64 `json:"score"`
+	Source string  `json:"source"`
+}
+
+// relatedOverridesPayload is the request body of PUT /api/private/experiences/:id/related.
+type relatedOverridesPayload struct {
+	IDs []string `json:"ids"`
+}
+
+// relatedLimit reads the limit query parameter, clamped to RelatedMaxLimit.
+func relatedLimit(c fiber.Ctx) int {
+	limit := fiber.Query[int](c, "limit", constants.RelatedDefaultLimit)
+	if limit <= 0 {
+		return constants.RelatedDefaultLimit
+	}
+	if limit > constants.RelatedMaxLimit {
+		return constants.RelatedMaxLimit
+	}
+	return limit
+}
+
+// ListRelatedExperiences godoc
+// @Summary      Experiencias relacionadas
+// @Description  Devuelve experiencias publicas relacionadas: primero las fijadas manualmente por el editor y luego las mejor puntuadas por coincidencia de tags (Jaccard) y similitud de texto TF-IDF sobre title, summary y body. Soporta ETag/If-None-Match.
+// @Tags         Experiences
+// @Produce      json
+// @Param        id     path   string  true   "ID de la experiencia"
+// @Param        limit  query  int     false  "Cantidad maxima (default 4, max 12)"
+// @Success      200  {object}  map[string]interface{}  "items"
+// @Success      304  "Not Modified"
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/experiences/{id}/related [get]
+func (s *ExperienceService) ListRelatedExperiences(c fiber.Ctx) error {
+	id := c.Params("id")
+	all, err := s.repo.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	public := make(map[string]models.Experience, len(all))
+	for _, item := range all {
+		if item.Visibility == constants.VisibilityPublic {
+			public[item.ID] = item
+		}
+	}
+	source, ok := public[id]
+	if !ok {
+		return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+	}
+
+	now := time.Now()
+	index := s.related.forTenant(tenantID(c))
+	if index.stale(now) {
+		index.rebuild(all, now)
+	}
+
+	limit := relatedLimit(c)
+	items := make([]relatedItem, 0, limit)
+	exclude := map[string]bool{id: true}
+	for _, relatedID := range source.RelatedIDs {
+		if item, ok := public[relatedID]; ok && !exclude[relatedID] && len(items) < limit {
+			items = append(items, relatedItem{Experience: item, Score: 1, Source: "manual"})
+			exclude[relatedID] = true
+		}
+	}
+	for _, scored := range index.ranked(id, exclude, limit-len(items)) {
+		if item, ok := public[scored.ID]; ok {
+			items = append(items, relatedItem{Experience: item, Score: scored.Score, Source: "auto"})
+		}
+	}
+
+	etag := tenantETag(c, buildPayloadETag(items))
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	experiences := make([]models.Experience, len(items))
+	for i := range items {
+		experiences[i] = items[i].Experience
+	}
+	SignExperienceList(requestContext(c), experiences)
+	for i := range items {
+		items[i].Experience = experiences[i]
+	}
+
+	return apiresponse.Success(c, fiber.Map{"items": items})
+}
+
+// SetRelatedExperiences godoc
+// @Summary      Fijar experiencias relacionadas
+// @Description  Define la lista manual de experiencias relacionadas (maximo 10, en orden), que se muestran antes de las calculadas automaticamente. Una lista vacia elimina los overrides. Requiere JWT e If-Match.
+// @Tags         Experiences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string  true  "ID de la experiencia"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
+// @Param        related   body    object{ids=[]string}  true  "IDs relacionados"
+// @Success      200  {object}  userModel.Experience
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/experiences/{id}/related [put]
+func (s *ExperienceService) SetRelatedExperiences(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	var payload relatedOverridesPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	if len(payload.IDs) > constants.MaxRelatedOverrides {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "too_many_related", "Demasiadas experiencias relacionadas", nil)
+	}
+
+	existing, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+
+	ids := make([]string, 0, len(payload.IDs))
+	seen := map[string]bool{}
+	for _, relatedID := range payload.IDs {
+		if !validatePayloadID(relatedID) || relatedID == id {
+			return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", relatedID)
+		}
+		if seen[relatedID] {
+			continue
+		}
+		if _, err := s.repo.GetByID(requestContext(c), relatedID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", relatedID)
+			}
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+		}
+		seen[relatedID] = true
+		ids = append(ids, relatedID)
+	}
+
+	expected := existing.Version
+	existing.RelatedIDs = ids
+	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := s.repo.Update(requestContext(c), existing, expected); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo actualizar la experiencia", err.Error())
+	}
+	existing.Version = expected + 1
+	s.related.forTenant(tenantID(c)).upsert(existing)
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeUpdated, existing, existing.Visibility)
+
+	c.Set("ETag", buildVersionETag(existing.Version))
+	return apiresponse.Success(c, existing)
+}
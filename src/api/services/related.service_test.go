@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	relatedGoAPI    = "00000000-0000-0000-0000-000000000001"
+	relatedGoCLI    = "00000000-0000-0000-0000-000000000002"
+	relatedDesign   = "00000000-0000-0000-0000-000000000003"
+	relatedPrivate  = "00000000-0000-0000-0000-000000000004"
+	relatedReactApp = "00000000-0000-0000-0000-000000000005"
+)
+
+func newRelatedTestService() (*ExperienceService, *memory.ExperienceRepository) {
+	repo := memory.NewExperienceRepository()
+	ctx := context.Background()
+	public := constants.VisibilityPublic
+	_ = repo.Create(ctx, models.Experience{ID: relatedGoAPI, Title: "API REST en Go", Summary: "Servicio backend con Fiber", Body: "<p>Backend Go con Fiber y Firestore</p>", Tags: []string{"go", "api", "backend"}, Visibility: public, Version: 1})
+	_ = repo.Create(ctx, models.Experience{ID: relatedGoCLI, Title: "CLI en Go", Summary: "Herramienta backend", Body: "<p>Backend Go para automatizar despliegues</p>", Tags: []string{"go", "backend"}, Visibility: public, Version: 1})
+	_ = repo.Create(ctx, models.Experience{ID: relatedDesign, Title: "Identidad visual", Summary: "Branding", Body: "<p>Logos y paleta de colores</p>", Tags: []string{"design"}, Visibility: public, Version: 1})
+	_ = repo.Create(ctx, models.Experience{ID: relatedPrivate, Title: "API privada en Go", Body: "Backend Go", Tags: []string{"go", "api", "backend"}, Visibility: constants.VisibilityPrivate, Version: 1})
+	_ = repo.Create(ctx, models.Experience{ID: relatedReactApp, Title: "Frontend React", Summary: "SPA que consume la API", Body: "<p>React consumiendo el backend</p>", Tags: []string{"react", "api"}, Visibility: public, Version: 1})
+	return NewExperienceService(repo), repo
+}
+
+func getRelated(t *testing.T, app *fiber.App, id string) []relatedItem {
+	t.Helper()
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/experiences/"+id+"/related", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("related failed: err=%v status=%d", err, res.StatusCode)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var result struct {
+		Items []relatedItem `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	return result.Items
+}
+
+func TestListRelatedExperiencesRanksBySimilarity(t *testing.T) {
+	svc, _ := newRelatedTestService()
+	app := fiber.New()
+	app.Get("/experiences/:id/related", svc.ListRelatedExperiences)
+
+	items := getRelated(t, app, relatedGoAPI)
+	if len(items) != 2 {
+		t.Fatalf("expected two related public experiences, got %+v", items)
+	}
+	if items[0].ID != relatedGoCLI || items[1].ID != relatedReactApp {
+		t.Fatalf("expected Go CLI first and React second, got %s, %s", items[0].ID, items[1].ID)
+	}
+	if items[0].Source != "auto" || items[0].Score <= items[1].Score {
+		t.Fatalf("expected descending auto scores, got %+v", items)
+	}
+
+	res, _ := app.Test(httptest.NewRequest(http.MethodGet, "/experiences/"+relatedPrivate+"/related", nil))
+	if res.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for private experience, got %d", res.StatusCode)
+	}
+}
+
+func TestRelatedIndexUpdatesOnWrites(t *testing.T) {
+	svc, _ := newRelatedTestService()
+	app := fiber.New()
+	app.Get("/experiences/:id/related", svc.ListRelatedExperiences)
+	app.Patch("/private/experiences/:id", svc.PatchExperience)
+	app.Delete("/private/experiences/:id", svc.DeleteExperience)
+
+	if items := getRelated(t, app, relatedDesign); len(items) != 0 {
+		t.Fatalf("expected no related items for design, got %+v", items)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/private/experiences/"+relatedReactApp, bytes.NewReader([]byte(`{"tags":["design","react"]}`)))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"1"`)
+	if res, err := app.Test(req); err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("patch failed: err=%v status=%d", err, res.StatusCode)
+	}
+	if items := getRelated(t, app, relatedDesign); len(items) != 1 || items[0].ID != relatedReactApp {
+		t.Fatalf("expected index to pick up new tags, got %+v", items)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/private/experiences/"+relatedGoCLI, nil)
+	req.Header.Set("If-Match", `"1"`)
+	if res, err := app.Test(req); err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("delete failed: err=%v status=%d", err, res.StatusCode)
+	}
+	for _, item := range getRelated(t, app, relatedGoAPI) {
+		if item.ID == relatedGoCLI {
+			t.Fatal("expected deleted experience to leave the index")
+		}
+	}
+}
+
+func TestSetRelatedExperiencesOverridesRanking(t *testing.T) {
+	svc, repo := newRelatedTestService()
+	app := fiber.New()
+	app.Get("/experiences/:id/related", svc.ListRelatedExperiences)
+	app.Put("/private/experiences/:id/related", svc.SetRelatedExperiences)
+
+	put := func(ids []string, ifMatch string) int {
+		body, _ := json.Marshal(map[string]any{"ids": ids})
+		req := httptest.NewRequest(http.MethodPut, "/private/experiences/"+relatedGoAPI+"/related", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", ifMatch)
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return res.StatusCode
+	}
+
+	if status := put([]string{relatedGoAPI}, `"1"`); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for self reference, got %d", status)
+	}
+	if status := put([]string{relatedDesign, relatedPrivate}, `"1"`); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	items := getRelated(t, app, relatedGoAPI)
+	if len(items) != 3 || items[0].ID != relatedDesign || items[0].Source != "manual" {
+		t.Fatalf("expected manual override first, got %+v", items)
+	}
+	for _, item := range items {
+		if item.ID == relatedPrivate {
+			t.Fatal("private overrides must not be exposed")
+		}
+	}
+
+	stored, _ := repo.GetByID(context.Background(), relatedGoAPI)
+	if len(stored.RelatedIDs) != 2 || stored.Version != 2 {
+		t.Fatalf("unexpected stored overrides: %+v", stored)
+	}
+}
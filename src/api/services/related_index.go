@@ -0,0 +1,272 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/sanitizer"
+)
+
+// --- Related-content index ---
+
+// relatedStopwords are frequent Spanish and English words ignored by TF-IDF.
+var relatedStopwords = map[string]bool{
+	"para": true, "con": true, "por": true, "los": true, "las": true, "del": true,
+	"una": true, "uno": true, "que": true, "como": true, "sobre": true, "entre": true,
+	"este": true, "esta": true, "sus": true, "mas": true, "más": true, "pero": true,
+	"the": true, "and": true, "for": true, "with": true, "from": true, "this": true,
+	"that": true, "are": true, "was": true, "into": true, "using": true,
+}
+
+// relatedDoc is the indexed representation of one public experience.
+type relatedDoc struct {
+	tags  map[string]bool
+	terms map[string]int // raw term frequencies
+	total int            // number of terms
+	vec   map[string]float64
+	norm  float64
+}
+
+// relatedIndex keeps term statistics of public experiences and a precomputed
+// pairwise similarity matrix. Writes update the affected rows incrementally;
+// the whole index is rebuilt once it is older than RelatedIndexTTL so IDF
+// drift from incremental updates does not accumulate.
+type relatedIndex struct {
+	mu      sync.RWMutex
+	built   bool
+	builtAt time.Time
+	docs    map[string]*relatedDoc
+	df      map[string]int
+	scores  map[string]map[string]float64
+}
+
+func newRelatedIndex() *relatedIndex {
+	return &relatedIndex{}
+}
+
+// relatedIndexes keeps one relatedIndex per tenant, so similarity is only
+// computed between experiences of the same portfolio.
+type relatedIndexes struct {
+	mu      sync.Mutex
+	tenants map[string]*relatedIndex
+}
+
+func newRelatedIndexes() *relatedIndexes {
+	return &relatedIndexes{tenants: map[string]*relatedIndex{}}
+}
+
+// forTenant returns the index of tenant, creating it on first use.
+func (r *relatedIndexes) forTenant(tenant string) *relatedIndex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idx, ok := r.tenants[tenant]
+	if !ok {
+		idx = newRelatedIndex()
+		r.tenants[tenant] = idx
+	}
+	return idx
+}
+
+// tokenize splits text into lowercase terms, dropping short words and stopwords.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) < constants.RelatedMinTokenRunes || relatedStopwords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+func newRelatedDoc(item models.Experience) *relatedDoc {
+	doc := &relatedDoc{tags: map[string]bool{}, terms: map[string]int{}}
+	for _, tag := range item.Tags {
+		doc.tags[tag] = true
+	}
+	text := item.Title + " " + item.Summary + " " + sanitizer.StripHTML(item.Body)
+	for _, token := range tokenize(text) {
+		doc.terms[token]++
+		doc.total++
+	}
+	return doc
+}
+
+// tagJaccard returns |A ∩ B| / |A ∪ B| of two tag sets.
+func tagJaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for tag := range a {
+		if b[tag] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// weigh computes the TF-IDF vector of doc with the current document frequencies.
+func (idx *relatedIndex) weigh(doc *relatedDoc) {
+	n := float64(len(idx.docs))
+	doc.vec = make(map[string]float64, len(doc.terms))
+	norm := 0.0
+	for term, count := range doc.terms {
+		idf := math.Log(1 + n/float64(1+idx.df[term]))
+		weight := float64(count) / float64(doc.total) * idf
+		doc.vec[term] = weight
+		norm += weight * weight
+	}
+	doc.norm = math.Sqrt(norm)
+}
+
+func similarity(a, b *relatedDoc) float64 {
+	text := 0.0
+	if a.norm > 0 && b.norm > 0 {
+		dot := 0.0
+		for term, weight := range a.vec {
+			dot += weight * b.vec[term]
+		}
+		text = dot / (a.norm * b.norm)
+	}
+	return constants.RelatedTagWeight*tagJaccard(a.tags, b.tags) + constants.RelatedTextWeight*text
+}
+
+// stale reports whether the index needs a full rebuild.
+func (idx *relatedIndex) stale(now time.Time) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return !idx.built || now.Sub(idx.builtAt) > constants.RelatedIndexTTL
+}
+
+// rebuild indexes all public experiences and recomputes every pair.
+func (idx *relatedIndex) rebuild(items []models.Experience, now time.Time) {
+	docs := map[string]*relatedDoc{}
+	df := map[string]int{}
+	for _, item := range items {
+		if item.Visibility != constants.VisibilityPublic {
+			continue
+		}
+		doc := newRelatedDoc(item)
+		docs[item.ID] = doc
+		for term := range doc.terms {
+			df[term]++
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs, idx.df = docs, df
+	idx.scores = make(map[string]map[string]float64, len(docs))
+	for id, doc := range docs {
+		idx.weigh(doc)
+		idx.scores[id] = map[string]float64{}
+	}
+	for id, doc := range docs {
+		for otherID, other := range docs {
+			if id < otherID {
+				score := similarity(doc, other)
+				idx.scores[id][otherID] = score
+				idx.scores[otherID][id] = score
+			}
+		}
+	}
+	idx.built = true
+	idx.builtAt = now
+}
+
+// upsert re-indexes one experience and recomputes its similarity row. Private
+// experiences are removed. It is a no-op until the index has been built.
+func (idx *relatedIndex) upsert(item models.Experience) {
+	if item.Visibility != constants.VisibilityPublic {
+		idx.remove(item.ID)
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built {
+		return
+	}
+	idx.removeLocked(item.ID)
+	doc := newRelatedDoc(item)
+	idx.docs[item.ID] = doc
+	for term := range doc.terms {
+		idx.df[term]++
+	}
+	idx.weigh(doc)
+	idx.scores[item.ID] = map[string]float64{}
+	for otherID, other := range idx.docs {
+		if otherID == item.ID {
+			continue
+		}
+		score := similarity(doc, other)
+		idx.scores[item.ID][otherID] = score
+		idx.scores[otherID][item.ID] = score
+	}
+}
+
+// remove drops an experience from the index.
+func (idx *relatedIndex) remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.built {
+		idx.removeLocked(id)
+	}
+}
+
+func (idx *relatedIndex) removeLocked(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		if idx.df[term]--; idx.df[term] <= 0 {
+			delete(idx.df, term)
+		}
+	}
+	delete(idx.docs, id)
+	delete(idx.scores, id)
+	for _, row := range idx.scores {
+		delete(row, id)
+	}
+}
+
+// relatedScore is a candidate with its similarity to the source experience.
+type relatedScore struct {
+	ID    string
+	Score float64
+}
+
+// ranked returns the experiences most similar to id with a positive score,
+// best first, skipping the IDs in exclude.
+func (idx *relatedIndex) ranked(id string, exclude map[string]bool, limit int) []relatedScore {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	result := []relatedScore{}
+	for otherID, score := range idx.scores[id] {
+		if score > 0 && !exclude[otherID] {
+			result = append(result, relatedScore{ID: otherID, Score: score})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].ID < result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
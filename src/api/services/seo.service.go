@@ -0,0 +1,175 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// SEOService exposes the sitemap and schema.org structured data for public experiences.
+type SEOService struct {
+	repo repository.ExperienceRepository
+}
+
+// NewSEOService creates an SEOService backed by the given ExperienceRepository.
+func NewSEOService(repo repository.ExperienceRepository) *SEOService {
+	return &SEOService{repo: repo}
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type jsonLDPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonLDCreativeWork struct {
+	Context       string       `json:"@context"`
+	Type          string       `json:"@type"`
+	ID            string       `json:"@id"`
+	URL           string       `json:"url"`
+	Name          string       `json:"name"`
+	Headline      string       `json:"headline"`
+	Description   string       `json:"description,omitempty"`
+	Text          string       `json:"text,omitempty"`
+	Keywords      string       `json:"keywords,omitempty"`
+	Image         []string     `json:"image,omitempty"`
+	DateCreated   string       `json:"dateCreated,omitempty"`
+	DatePublished string       `json:"datePublished,omitempty"`
+	DateModified  string       `json:"dateModified,omitempty"`
+	InLanguage    string       `json:"inLanguage"`
+	Author        jsonLDPerson `json:"author"`
+}
+
+// buildCreativeWork maps an experience to a schema.org CreativeWork authored by the portfolio owner.
+func buildCreativeWork(item models.Experience) jsonLDCreativeWork {
+	site := constants.PublicSiteURL()
+	url := experienceURL(item.ID)
+	return jsonLDCreativeWork{
+		Context:       "https://schema.org",
+		Type:          "CreativeWork",
+		ID:            url,
+		URL:           url,
+		Name:          item.Title,
+		Headline:      item.Title,
+		Description:   item.Summary,
+		Text:          item.Body,
+		Keywords:      strings.Join(item.Tags, ", "),
+		Image:         item.ImageURLs,
+		DateCreated:   item.CreatedAt,
+		DatePublished: item.CreatedAt,
+		DateModified:  item.UpdatedAt,
+		InLanguage:    constants.FeedLanguage,
+		Author:        jsonLDPerson{Type: "Person", Name: constants.PortfolioAuthor(), URL: site},
+	}
+}
+
+// Sitemap godoc
+// @Summary      Sitemap XML
+// @Description  Sitemap con la home y las experiencias publicas (lastmod desde updatedAt). Soporta ETag/If-None-Match.
+// @Tags         SEO
+// @Produce      xml
+// @Success      200  {string}  string  "sitemap"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /sitemap.xml [get]
+func (s *SEOService) Sitemap(c fiber.Ctx) error {
+	all, err := s.repo.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	sortExperiences(all)
+	set := sitemapURLSet{URLs: []sitemapURL{{Loc: constants.PublicSiteURL() + "/"}}}
+	latest := ""
+	for _, item := range all {
+		if item.Visibility != constants.VisibilityPublic {
+			continue
+		}
+		lastMod := item.UpdatedAt
+		if lastMod == "" {
+			lastMod = item.CreatedAt
+		}
+		if lastMod > latest {
+			latest = lastMod
+		}
+		set.URLs = append(set.URLs, sitemapURL{Loc: experienceURL(item.ID), LastMod: lastMod})
+	}
+	set.URLs[0].LastMod = latest
+
+	etag := tenantETag(c, buildPayloadETag(set))
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	out, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "sitemap_render_failed", "No se pudo generar el sitemap", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Send(append([]byte(xml.Header), out...))
+}
+
+// ExperienceJSONLD godoc
+// @Summary      JSON-LD de una experiencia
+// @Description  Datos estructurados schema.org (CreativeWork con autor Person) de una experiencia publica. Soporta ETag/If-None-Match.
+// @Tags         SEO
+// @Produce      json
+// @Param        id   path      string  true  "ID de la experiencia"
+// @Success      200  {object}  map[string]interface{}
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/experiences/{id}/jsonld [get]
+func (s *SEOService) ExperienceJSONLD(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	item, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experience_failed", "No se pudo cargar la experiencia", err.Error())
+	}
+	if item.Visibility != constants.VisibilityPublic {
+		return apiresponse.Error(c, fiber.StatusNotFound, "experience_not_found", "Experiencia no encontrada", nil)
+	}
+
+	// ETag over the unsigned document: signatures change on every request.
+	etag := tenantETag(c, buildPayloadETag(buildCreativeWork(item)))
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	signed := []models.Experience{item}
+	SignExperienceList(requestContext(c), signed)
+	body, err := json.Marshal(buildCreativeWork(signed[0]))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "jsonld_render_failed", "No se pudo generar el JSON-LD", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/ld+json; charset=utf-8")
+	return c.Send(body)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func newSEOTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	t.Setenv("PUBLIC_SITE_URL", "https://example.dev")
+	t.Setenv("PORTFOLIO_AUTHOR_NAME", "Ada")
+	repo := memory.NewExperienceRepository()
+	ctx := context.Background()
+	_ = repo.Create(ctx, models.Experience{
+		ID: "00000000-0000-0000-0000-000000000001", Title: "API", Summary: "resumen", Body: "<p>body</p>",
+		Tags: []string{"go", "api"}, Visibility: constants.VisibilityPublic,
+		CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-02-01T00:00:00Z",
+	})
+	_ = repo.Create(ctx, models.Experience{
+		ID: "00000000-0000-0000-0000-000000000002", Title: "Privada",
+		Visibility: constants.VisibilityPrivate, CreatedAt: "2024-03-01T00:00:00Z",
+	})
+
+	svc := NewSEOService(repo)
+	app := fiber.New()
+	app.Get("/sitemap.xml", svc.Sitemap)
+	app.Get("/experiences/:id/jsonld", svc.ExperienceJSONLD)
+	return app
+}
+
+func TestSitemapListsPublicExperiences(t *testing.T) {
+	app := newSEOTestApp(t)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("sitemap failed: err=%v status=%d", err, res.StatusCode)
+	}
+	etag := res.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+	raw, _ := io.ReadAll(res.Body)
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal(raw, &set); err != nil {
+		t.Fatalf("invalid sitemap: %v", err)
+	}
+	if len(set.URLs) != 2 {
+		t.Fatalf("expected home and one experience, got %+v", set.URLs)
+	}
+	if set.URLs[0].Loc != "https://example.dev/" || set.URLs[0].LastMod != "2024-02-01T00:00:00Z" {
+		t.Errorf("unexpected home entry: %+v", set.URLs[0])
+	}
+	if set.URLs[1].Loc != "https://example.dev/experiences/00000000-0000-0000-0000-000000000001" || set.URLs[1].LastMod != "2024-02-01T00:00:00Z" {
+		t.Errorf("unexpected experience entry: %+v", set.URLs[1])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	req.Header.Set("If-None-Match", etag)
+	res, err = app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("expected 304, got err=%v status=%d", err, res.StatusCode)
+	}
+}
+
+func TestExperienceJSONLD(t *testing.T) {
+	app := newSEOTestApp(t)
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/experiences/00000000-0000-0000-0000-000000000001/jsonld", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("jsonld failed: err=%v status=%d", err, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "application/ld+json; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	raw, _ := io.ReadAll(res.Body)
+
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("invalid json-ld: %v", err)
+	}
+	if doc["@context"] != "https://schema.org" || doc["@type"] != "CreativeWork" || doc["keywords"] != "go, api" {
+		t.Errorf("unexpected document: %v", doc)
+	}
+	author, _ := doc["author"].(map[string]any)
+	if author["@type"] != "Person" || author["name"] != "Ada" {
+		t.Errorf("unexpected author: %v", author)
+	}
+
+	for _, id := range []string{"00000000-0000-0000-0000-000000000002", "00000000-0000-0000-0000-000000000099"} {
+		res, err = app.Test(httptest.NewRequest(http.MethodGet, "/experiences/"+id+"/jsonld", nil))
+		if err != nil || res.StatusCode != fiber.StatusNotFound {
+			t.Fatalf("expected 404 for %s, got err=%v status=%d", id, err, res.StatusCode)
+		}
+	}
+
+	res, err = app.Test(httptest.NewRequest(http.MethodGet, "/experiences/missing/jsonld", nil))
+	if err != nil || res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a malformed id, got err=%v status=%d", err, res.StatusCode)
+	}
+}
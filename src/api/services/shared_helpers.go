@@ -3,7 +3,11 @@ package services
 import (
 	"backend-yonathan/src/pkg/constants"
 	"backend-yonathan/src/pkg/sanitizer"
+	"backend-yonathan/src/repository"
+	"context"
+	"log"
 	"strings"
+	"sync"
 )
 
 // experiencePayload is the common request body for creating/updating
@@ -46,22 +50,90 @@ func normalizeImageURLs(urls []string) []string {
 	return sanitizer.ValidateURLSlice(urls, constants.MaxImageURLCount)
 }
 
-func normalizeTags(tags []string) []string {
+// activeTagAliases holds the alias → canonical tag map of each tenant,
+// applied by normalizeTags. TagService loads and keeps it up to date; the
+// aliases of a tenant not seen yet are read through load on first use.
+var activeTagAliases = struct {
+	mu      sync.RWMutex
+	tenants map[string]map[string]string
+	load    func(ctx context.Context) (map[string]string, error)
+}{tenants: map[string]map[string]string{}}
+
+// aliasTenant returns the key of the aliases of the tenant of ctx: empty
+// when MULTI_TENANT is off, and the default tenant for calls without one.
+func aliasTenant(ctx context.Context) string {
+	if tenant, ok := repository.TenantFrom(ctx); ok {
+		return tenant
+	}
+	if constants.MultiTenant() {
+		return constants.DefaultTenantID()
+	}
+	return ""
+}
+
+// setActiveTagAliases replaces the active aliases of the tenant of ctx.
+func setActiveTagAliases(ctx context.Context, aliases map[string]string) {
+	copied := make(map[string]string, len(aliases))
+	for alias, canonical := range aliases {
+		copied[alias] = canonical
+	}
+	activeTagAliases.mu.Lock()
+	activeTagAliases.tenants[aliasTenant(ctx)] = copied
+	activeTagAliases.mu.Unlock()
+}
+
+// tagAliasesFor returns the active aliases of the tenant of ctx, loading
+// them on first use. Load errors are logged and no aliases are applied.
+func tagAliasesFor(ctx context.Context) map[string]string {
+	activeTagAliases.mu.RLock()
+	aliases, ok := activeTagAliases.tenants[aliasTenant(ctx)]
+	load := activeTagAliases.load
+	activeTagAliases.mu.RUnlock()
+	if ok || load == nil {
+		return aliases
+	}
+	loaded, err := load(ctx)
+	if err != nil {
+		log.Printf("[tags] could not load tag aliases of tenant %q: %v", aliasTenant(ctx), err)
+		return nil
+	}
+	setActiveTagAliases(ctx, loaded)
+	return loaded
+}
+
+// normalizeTag sanitizes and lowercases a single tag, without applying aliases.
+func normalizeTag(tag string) string {
+	return strings.ToLower(sanitizer.SanitizePlainText(tag, constants.MaxTagLength))
+}
+
+// normalizeTags sanitizes tags, replaces the aliases of the tenant of ctx by
+// their canonical tag and drops the duplicates this may produce.
+func normalizeTags(ctx context.Context, tags []string) []string {
+	aliases := tagAliasesFor(ctx)
+	seen := map[string]bool{}
 	return sanitizer.SanitizeSlice(tags, constants.MaxTagCount, func(tag string) string {
-		cleaned := sanitizer.SanitizePlainText(tag, constants.MaxTagLength)
-		return strings.ToLower(cleaned)
+		cleaned := normalizeTag(tag)
+		if canonical, ok := aliases[cleaned]; ok {
+			cleaned = canonical
+		}
+		if seen[cleaned] {
+			return ""
+		}
+		seen[cleaned] = true
+		return cleaned
 	})
 }
 
 // sanitizePayload applies input sanitization and length limits to an experience/skill payload.
 // Title and Summary are stripped of HTML. Body allows safe HTML (UGC policy).
-// ImageURLs are validated as HTTP/HTTPS URLs. Tags are sanitized and lowercased.
-func sanitizePayload(p *experiencePayload) {
+// ImageURLs are validated as HTTP/HTTPS URLs. Tags are sanitized, lowercased
+// and resolved with the aliases of the tenant of ctx.
+func sanitizePayload(ctx context.Context, p *experiencePayload) {
 	p.Title = sanitizer.SanitizePlainText(p.Title, constants.MaxTitleLength)
 	p.Summary = sanitizer.SanitizePlainText(p.Summary, constants.MaxSummaryLength)
 	p.Body = StripBodySignedParams(sanitizer.SanitizeRichText(p.Body, constants.MaxBodyLength))
 	p.ImageURLs = normalizeImageURLs(p.ImageURLs)
-	p.Tags = normalizeTags(p.Tags)
+	p.Tags = normalizeTags(ctx, p.Tags)
 	p.Visibility = normalizeVisibility(p.Visibility)
 }
 
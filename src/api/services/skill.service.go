@@ -1,7 +1,6 @@
 package services
 
 import (
-	"context"
 	"errors"
 	"strings"
 	"time"
@@ -30,13 +29,19 @@ func normalizeTagValue(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
 
+func isSkillTag(normalized string) bool {
+	for _, skillTag := range constants.SkillTags {
+		if normalized == skillTag {
+			return true
+		}
+	}
+	return false
+}
+
 func isSkillExperience(item models.Experience) bool {
 	for _, tag := range item.Tags {
-		normalized := normalizeTagValue(tag)
-		for _, skillTag := range constants.SkillTags {
-			if normalized == skillTag {
-				return true
-			}
+		if isSkillTag(normalizeTagValue(tag)) {
+			return true
 		}
 	}
 	return false
@@ -69,7 +74,7 @@ func ensureSkillTag(tags []string) []string {
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/skills [get]
 func (s *SkillService) ListPublicSkills(c fiber.Ctx) error {
-	all, err := s.repo.List(context.Background())
+	all, err := s.repo.List(requestContext(c))
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
 	}
@@ -81,9 +86,10 @@ func (s *SkillService) ListPublicSkills(c fiber.Ctx) error {
 		}
 	}
 
-	SignExperienceList(context.Background(), skills)
+	sortExperiences(skills)
+	SignExperienceList(requestContext(c), skills)
 
-	etag := buildCollectionETag(skills)
+	etag := tenantETag(c, buildCollectionETag(skills))
 	setPublicCollectionCacheHeaders(c, etag)
 	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
 		return c.SendStatus(fiber.StatusNotModified)
@@ -103,7 +109,7 @@ func (s *SkillService) ListPublicSkills(c fiber.Ctx) error {
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/skills [get]
 func (s *SkillService) ListAllSkills(c fiber.Ctx) error {
-	all, err := s.repo.List(context.Background())
+	all, err := s.repo.List(requestContext(c))
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
 	}
@@ -115,7 +121,8 @@ func (s *SkillService) ListAllSkills(c fiber.Ctx) error {
 		}
 	}
 
-	SignExperienceList(context.Background(), skills)
+	sortExperiences(skills)
+	SignExperienceList(requestContext(c), skills)
 	return apiresponse.Success(c, fiber.Map{"items": skills})
 }
 
@@ -137,44 +144,80 @@ func (s *SkillService) CreateSkill(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
 	}
 
-	sanitizePayload(&payload)
+	sanitizePayload(requestContext(c), &payload)
 
 	if payload.Title == "" {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "missing_title", "El titulo es requerido", nil)
 	}
 
+	all, err := s.repo.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 	item := models.Experience{
 		ID:         uuid.NewString(),
+		TenantID:   tenantID(c),
 		Title:      payload.Title,
 		Summary:    payload.Summary,
 		Body:       payload.Body,
 		ImageURLs:  payload.ImageURLs,
 		Tags:       ensureSkillTag(payload.Tags),
 		Visibility: payload.Visibility,
+		Position:   nextPosition(all),
+		Version:    1,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 
-	if err := s.repo.Create(context.Background(), item); err != nil {
+	if errs := validateContent(constants.ValidationCollectionSkills, item); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	if err := s.repo.Create(requestContext(c), item); err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo guardar la capacidad", err.Error())
 	}
 
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeCreated, item, "")
+	c.Set("ETag", buildVersionETag(item.Version))
 	return apiresponse.Success(c, item)
 }
 
+// ValidateSkill godoc
+// @Summary      Validar skill (dry-run)
+// @Description  Sanitiza el payload y lo evalua contra las reglas de contenido configuradas (VALIDATION_RULES_FILE) sin guardar nada. Devuelve los errores por campo. Requiere JWT.
+// @Tags         Skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skill  body  object{title=string,summary=string,body=string,imageUrls=[]string,tags=[]string,visibility=string}  true  "Datos"
+// @Success      200  {object}  map[string]interface{}  "valid, errors, payload"
+// @Failure      400  {object}  map[string]interface{}
+// @Router       /api/private/skills/validate [post]
+func (s *SkillService) ValidateSkill(c fiber.Ctx) error {
+	return dryRunValidation(c, constants.ValidationCollectionSkills, func(item models.Experience) models.Experience {
+		item.Tags = ensureSkillTag(item.Tags)
+		return item
+	})
+}
+
 // UpdateSkill godoc
 // @Summary      Actualizar skill
-// @Description  Actualiza una skill por ID. Requiere JWT. imageUrls solo acepta URLs http/https (máx. 10, ≤ 2048 chars); data: URLs se descartan.
+// @Description  Actualiza una skill por ID. Requiere JWT e If-Match con el ETag de la version actual. imageUrls solo acepta URLs http/https (máx. 10, ≤ 2048 chars); data: URLs se descartan.
 // @Tags         Skills
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id     path  string  true  "ID de la skill"
+// @Param        id        path    string  true  "ID de la skill"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
 // @Param        skill  body  object{title=string,summary=string,body=string,imageUrls=[]string,tags=[]string,visibility=string}  true  "Datos"
 // @Success      200  {object}  userModel.Experience
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/skills/{id} [put]
 func (s *SkillService) UpdateSkill(c fiber.Ctx) error {
@@ -188,9 +231,9 @@ func (s *SkillService) UpdateSkill(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
 	}
 
-	sanitizePayload(&payload)
+	sanitizePayload(requestContext(c), &payload)
 
-	existing, err := s.repo.GetByID(context.Background(), id)
+	existing, err := s.repo.GetByID(requestContext(c), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
@@ -202,6 +245,11 @@ func (s *SkillService) UpdateSkill(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
 	}
 
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+	previousVisibility := existing.Visibility
+
 	if payload.Title != "" {
 		existing.Title = payload.Title
 	}
@@ -212,26 +260,119 @@ func (s *SkillService) UpdateSkill(c fiber.Ctx) error {
 	existing.Visibility = payload.Visibility
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 
-	if err := s.repo.Update(context.Background(), existing); err != nil {
+	if errs := validateContent(constants.ValidationCollectionSkills, existing); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	expected := existing.Version
+	if err := s.repo.Update(requestContext(c), existing, expected); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo actualizar la capacidad", err.Error())
+	}
+	existing.Version = expected + 1
+
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeUpdated, existing, previousVisibility)
+	c.Set("ETag", buildVersionETag(existing.Version))
+	return apiresponse.Success(c, existing)
+}
+
+// PatchSkill godoc
+// @Summary      Actualizar parcialmente skill
+// @Description  Aplica un JSON Merge Patch (application/merge-patch+json) o un JSON Patch (application/json-patch+json) sobre la skill. El tag de skill se conserva. Requiere JWT e If-Match.
+// @Tags         Skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string  true  "ID de la skill"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
+// @Param        patch     body    object  true  "Merge patch u operaciones JSON Patch"
+// @Success      200  {object}  userModel.Experience
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      415  {object}  map[string]interface{}
+// @Failure      422  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/{id} [patch]
+func (s *SkillService) PatchSkill(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	existing, err := s.repo.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
+	if !isSkillExperience(existing) {
+		return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+	}
+
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+
+	payload, failure := patchExperiencePayload(c, existing)
+	if failure != nil {
+		return apiresponse.Error(c, failure.status, failure.code, failure.message, failure.details)
+	}
+
+	expected := existing.Version
+	previousVisibility := existing.Visibility
+	existing.Title = payload.Title
+	existing.Summary = payload.Summary
+	existing.Body = payload.Body
+	existing.ImageURLs = payload.ImageURLs
+	existing.Tags = ensureSkillTag(payload.Tags)
+	existing.Visibility = payload.Visibility
+	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if errs := validateContent(constants.ValidationCollectionSkills, existing); len(errs) > 0 {
+		return contentRulesError(c, errs)
+	}
+
+	if err := s.repo.Update(requestContext(c), existing, expected); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo actualizar la capacidad", err.Error())
 	}
+	existing.Version = expected + 1
 
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeUpdated, existing, previousVisibility)
+	c.Set("ETag", buildVersionETag(existing.Version))
 	return apiresponse.Success(c, existing)
 }
 
 // DeleteSkill godoc
 // @Summary      Eliminar skill
-// @Description  Elimina una skill por ID. Requiere JWT.
+// @Description  Elimina una skill por ID. Requiere JWT e If-Match con el ETag de la version actual.
 // @Tags         Skills
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id  path  string  true  "ID de la skill"
+// @Param        id        path    string  true  "ID de la skill"
+// @Param        If-Match  header  string  true  "ETag de la version actual"
 // @Success      200  {object}  map[string]interface{}  "deleted, id"
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      428  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /api/private/skills/{id} [delete]
 func (s *SkillService) DeleteSkill(c fiber.Ctx) error {
@@ -240,7 +381,7 @@ func (s *SkillService) DeleteSkill(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
 	}
 
-	existing, err := s.repo.GetByID(context.Background(), id)
+	existing, err := s.repo.GetByID(requestContext(c), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
@@ -252,12 +393,21 @@ func (s *SkillService) DeleteSkill(c fiber.Ctx) error {
 		return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
 	}
 
-	if err := s.repo.Delete(context.Background(), id); err != nil {
+	if status, code, msg := checkIfMatch(c.Get("If-Match"), existing.Version); status != 0 {
+		return apiresponse.Error(c, status, code, msg, nil)
+	}
+
+	if err := s.repo.Delete(requestContext(c), id, existing.Version); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", nil)
+		}
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo eliminar la capacidad", err.Error())
 	}
 
+	invalidatePublicStats()
+	publishExperienceChange(repository.ChangeDeleted, existing, existing.Visibility)
 	return apiresponse.Success(c, fiber.Map{"deleted": true, "id": id})
 }
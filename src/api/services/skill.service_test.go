@@ -2,12 +2,14 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	models "backend-yonathan/src/models"
 	"backend-yonathan/src/pkg/constants"
 	"backend-yonathan/src/repository/memory"
 
@@ -165,6 +167,7 @@ func TestUpdateDeleteAndListAllSkills(t *testing.T) {
 	})
 	updateReq := httptest.NewRequest(http.MethodPut, "/private/skills/"+id, bytes.NewReader(updateBody))
 	updateReq.Header.Set("Content-Type", "application/json")
+	updateReq.Header.Set("If-Match", createRes.Header.Get("ETag"))
 	updateRes, err := app.Test(updateReq)
 	if err != nil || updateRes.StatusCode != fiber.StatusOK {
 		t.Fatalf("update failed: %v status=%d", err, updateRes.StatusCode)
@@ -187,6 +190,7 @@ func TestUpdateDeleteAndListAllSkills(t *testing.T) {
 	}
 
 	deleteReq := httptest.NewRequest(http.MethodDelete, "/private/skills/"+id, nil)
+	deleteReq.Header.Set("If-Match", updateRes.Header.Get("ETag"))
 	deleteRes, err := app.Test(deleteReq)
 	if err != nil || deleteRes.StatusCode != fiber.StatusOK {
 		t.Fatalf("delete failed: %v status=%d", err, deleteRes.StatusCode)
@@ -359,3 +363,29 @@ func TestDeleteSkillNotASkill(t *testing.T) {
 		t.Fatalf("expected 404 for non-skill delete, got %d", res.StatusCode)
 	}
 }
+
+func TestPatchSkillKeepsSkillTag(t *testing.T) {
+	repo := memory.NewExperienceRepository()
+	svc := NewSkillService(repo)
+	id := "00000000-0000-0000-0000-000000000001"
+	_ = repo.Create(context.Background(), models.Experience{
+		ID: id, Title: "Docker", Summary: "Contenedores", Tags: []string{"skill", "devops"},
+		Visibility: constants.VisibilityPublic, Version: 1,
+	})
+
+	app := fiber.New()
+	app.Patch("/private/skills/:id", svc.PatchSkill)
+
+	req := httptest.NewRequest(http.MethodPatch, "/private/skills/"+id, bytes.NewReader([]byte(`{"tags":["devops"]}`)))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"1"`)
+	res, err := app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("patch failed: %v status=%d", err, res.StatusCode)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), id)
+	if stored.Summary != "Contenedores" || !isSkillExperience(stored) {
+		t.Fatalf("expected summary and skill tag preserved, got %+v", stored)
+	}
+}
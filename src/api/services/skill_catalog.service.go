@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/sanitizer"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// SkillCatalogService handles the structured skill catalog (models.Skill).
+// It lives next to SkillService, which keeps serving the legacy tagged
+// experiences on /api/skills.
+type SkillCatalogService struct {
+	skills      repository.SkillRepository
+	experiences repository.ExperienceRepository
+}
+
+// NewSkillCatalogService creates a SkillCatalogService backed by the given repositories.
+// The experience repository is only read, to migrate legacy tagged experiences.
+func NewSkillCatalogService(skills repository.SkillRepository, experiences repository.ExperienceRepository) *SkillCatalogService {
+	return &SkillCatalogService{skills: skills, experiences: experiences}
+}
+
+// skillPayload is the request body for creating/updating catalog skills.
+type skillPayload struct {
+	Name          string   `json:"name"`
+	Category      string   `json:"category"`
+	Proficiency   int      `json:"proficiency"`
+	Years         int      `json:"years"`
+	Icon          string   `json:"icon"`
+	ExperienceIDs []string `json:"experienceIds"`
+	Visibility    string   `json:"visibility"`
+}
+
+// skillCategoryGroup is one entry of the public catalog grouped by category.
+type skillCategoryGroup struct {
+	Category string         `json:"category"`
+	Items    []models.Skill `json:"items"`
+}
+
+// SkillMigrationReport summarizes a run of MigrateTaggedSkills.
+type SkillMigrationReport struct {
+	Scanned int      `json:"scanned"`
+	Created int      `json:"created"`
+	Skipped int      `json:"skipped"`
+	IDs     []string `json:"ids"`
+}
+
+func normalizeSkillCategory(category string) string {
+	cleaned := normalizeTagValue(sanitizer.SanitizePlainText(category, constants.MaxTagLength))
+	if cleaned == "" {
+		return constants.DefaultSkillCategory
+	}
+	return cleaned
+}
+
+func normalizeExperienceRefs(ids []string) []string {
+	refs := make([]string, 0, len(ids))
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if len(refs) >= constants.MaxSkillExperienceRefs {
+			break
+		}
+		if !sanitizer.IsValidUUID(id) || seen[id] {
+			continue
+		}
+		refs = append(refs, id)
+		seen[id] = true
+	}
+	return refs
+}
+
+// sanitizeSkillPayload applies input sanitization and defaults to a catalog skill payload.
+// Proficiency 0 means "not provided" and falls back to DefaultSkillProficiency.
+func sanitizeSkillPayload(p *skillPayload) {
+	p.Name = sanitizer.SanitizePlainText(p.Name, constants.MaxTitleLength)
+	p.Category = normalizeSkillCategory(p.Category)
+	if p.Proficiency == 0 {
+		p.Proficiency = constants.DefaultSkillProficiency
+	}
+	p.Icon = sanitizer.SanitizePlainText(p.Icon, constants.MaxSkillIconLength)
+	p.ExperienceIDs = normalizeExperienceRefs(p.ExperienceIDs)
+	p.Visibility = normalizeVisibility(p.Visibility)
+}
+
+// validateSkillPayload returns an error code and message for invalid payloads, or empty strings.
+func validateSkillPayload(p skillPayload) (string, string) {
+	if p.Name == "" {
+		return "missing_name", "El nombre es requerido"
+	}
+	if p.Proficiency < constants.MinSkillProficiency || p.Proficiency > constants.MaxSkillProficiency {
+		return "invalid_proficiency", "El nivel debe estar entre 1 y 5"
+	}
+	if p.Years < 0 || p.Years > constants.MaxSkillYears {
+		return "invalid_years", "Los años de experiencia no son validos"
+	}
+	return "", ""
+}
+
+// groupSkillsByCategory groups skills by category, sorting categories and
+// names alphabetically so the response is stable for ETag computation.
+func groupSkillsByCategory(skills []models.Skill) []skillCategoryGroup {
+	byCategory := map[string][]models.Skill{}
+	for _, skill := range skills {
+		byCategory[skill.Category] = append(byCategory[skill.Category], skill)
+	}
+
+	groups := make([]skillCategoryGroup, 0, len(byCategory))
+	for category, items := range byCategory {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+		groups = append(groups, skillCategoryGroup{Category: category, Items: items})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Category < groups[j].Category })
+	return groups
+}
+
+// skillFromExperience converts a legacy skill-tagged experience into a catalog
+// skill. The first non-skill tag becomes the category.
+func skillFromExperience(exp models.Experience, now string) models.Skill {
+	category := constants.DefaultSkillCategory
+	for _, tag := range exp.Tags {
+		normalized := normalizeTagValue(tag)
+		if normalized != "" && !isSkillTag(normalized) {
+			category = normalized
+			break
+		}
+	}
+
+	icon := ""
+	if len(exp.ImageURLs) > 0 {
+		icon = exp.ImageURLs[0]
+	}
+
+	createdAt := exp.CreatedAt
+	if createdAt == "" {
+		createdAt = now
+	}
+
+	return models.Skill{
+		ID:            exp.ID,
+		Name:          exp.Title,
+		Category:      category,
+		Proficiency:   constants.DefaultSkillProficiency,
+		Icon:          icon,
+		ExperienceIDs: []string{exp.ID},
+		Visibility:    normalizeVisibility(exp.Visibility),
+		CreatedAt:     createdAt,
+		UpdatedAt:     now,
+	}
+}
+
+// MigrateTaggedSkills creates a catalog skill for every experience carrying a
+// skill tag. The skill reuses the experience ID, so running it again skips
+// already migrated entries. Experiences are left untouched.
+func MigrateTaggedSkills(ctx context.Context, experiences repository.ExperienceRepository, skills repository.SkillRepository) (SkillMigrationReport, error) {
+	report := SkillMigrationReport{IDs: []string{}}
+
+	all, err := experiences.List(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, exp := range all {
+		if !isSkillExperience(exp) {
+			continue
+		}
+		report.Scanned++
+
+		if _, err := skills.GetByID(ctx, exp.ID); err == nil {
+			report.Skipped++
+			continue
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return report, err
+		}
+
+		if err := skills.Create(ctx, skillFromExperience(exp, now)); err != nil {
+			return report, err
+		}
+		report.Created++
+		report.IDs = append(report.IDs, exp.ID)
+	}
+	return report, nil
+}
+
+// ListPublicCatalog godoc
+// @Summary      Catalogo publico de skills por categoria
+// @Description  Devuelve las skills estructuradas con visibility=public agrupadas por categoria. Soporta ETag/If-None-Match.
+// @Tags         Skills
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "categories"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/skills/catalog [get]
+func (s *SkillCatalogService) ListPublicCatalog(c fiber.Ctx) error {
+	all, err := s.skills.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
+	public := make([]models.Skill, 0, len(all))
+	for _, item := range all {
+		if item.Visibility == constants.VisibilityPublic {
+			item.Icon = signSingleURL(requestContext(c), item.Icon, constants.GCSURLPrefix(), constants.SignedURLExpiry())
+			public = append(public, item)
+		}
+	}
+
+	groups := groupSkillsByCategory(public)
+
+	etag := tenantETag(c, buildPayloadETag(groups))
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return apiresponse.Success(c, fiber.Map{"categories": groups})
+}
+
+// ListCatalog godoc
+// @Summary      Listar catalogo de skills
+// @Description  Devuelve todas las skills estructuradas (publicas y privadas). Requiere JWT.
+// @Tags         Skills
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "items"
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/catalog [get]
+func (s *SkillCatalogService) ListCatalog(c fiber.Ctx) error {
+	all, err := s.skills.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+	return apiresponse.Success(c, fiber.Map{"items": all})
+}
+
+// CreateCatalogSkill godoc
+// @Summary      Crear skill del catalogo
+// @Description  Crea una skill estructurada. proficiency va de 1 a 5 (default 3); category default "general". Requiere JWT.
+// @Tags         Skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skill  body  object{name=string,category=string,proficiency=int,years=int,icon=string,experienceIds=[]string,visibility=string}  true  "Datos"
+// @Success      200  {object}  userModel.Skill
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/catalog [post]
+func (s *SkillCatalogService) CreateCatalogSkill(c fiber.Ctx) error {
+	var payload skillPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+
+	sanitizeSkillPayload(&payload)
+	if code, message := validateSkillPayload(payload); code != "" {
+		return apiresponse.Error(c, fiber.StatusBadRequest, code, message, nil)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	item := models.Skill{
+		ID:            uuid.NewString(),
+		Name:          payload.Name,
+		Category:      payload.Category,
+		Proficiency:   payload.Proficiency,
+		Years:         payload.Years,
+		Icon:          payload.Icon,
+		ExperienceIDs: payload.ExperienceIDs,
+		Visibility:    payload.Visibility,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.skills.Create(requestContext(c), item); err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo guardar la capacidad", err.Error())
+	}
+
+	return apiresponse.Success(c, item)
+}
+
+// UpdateCatalogSkill godoc
+// @Summary      Actualizar skill del catalogo
+// @Description  Actualiza una skill estructurada por ID. Requiere JWT.
+// @Tags         Skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path  string  true  "ID de la skill"
+// @Param        skill  body  object{name=string,category=string,proficiency=int,years=int,icon=string,experienceIds=[]string,visibility=string}  true  "Datos"
+// @Success      200  {object}  userModel.Skill
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/catalog/{id} [put]
+func (s *SkillCatalogService) UpdateCatalogSkill(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	var payload skillPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+
+	sanitizeSkillPayload(&payload)
+
+	existing, err := s.skills.GetByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_skills_failed", "No se pudo cargar capacidades", err.Error())
+	}
+
+	if payload.Name == "" {
+		payload.Name = existing.Name
+	}
+	if code, message := validateSkillPayload(payload); code != "" {
+		return apiresponse.Error(c, fiber.StatusBadRequest, code, message, nil)
+	}
+
+	existing.Name = payload.Name
+	existing.Category = payload.Category
+	existing.Proficiency = payload.Proficiency
+	existing.Years = payload.Years
+	existing.Icon = payload.Icon
+	existing.ExperienceIDs = payload.ExperienceIDs
+	existing.Visibility = payload.Visibility
+	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if err := s.skills.Update(requestContext(c), existing); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo actualizar la capacidad", err.Error())
+	}
+
+	return apiresponse.Success(c, existing)
+}
+
+// DeleteCatalogSkill godoc
+// @Summary      Eliminar skill del catalogo
+// @Description  Elimina una skill estructurada por ID. Requiere JWT.
+// @Tags         Skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "ID de la skill"
+// @Success      200  {object}  map[string]interface{}  "deleted, id"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/catalog/{id} [delete]
+func (s *SkillCatalogService) DeleteCatalogSkill(c fiber.Ctx) error {
+	id := c.Params("id")
+	if !validatePayloadID(id) {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_id", "Formato de ID invalido", nil)
+	}
+
+	if err := s.skills.Delete(requestContext(c), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "skill_not_found", "Capacidad no encontrada", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_skill_failed", "No se pudo eliminar la capacidad", err.Error())
+	}
+
+	return apiresponse.Success(c, fiber.Map{"deleted": true, "id": id})
+}
+
+// MigrateCatalog godoc
+// @Summary      Migrar skills etiquetadas
+// @Description  Convierte las experiencias con tag de skill en skills estructuradas. Idempotente: las ya migradas se omiten. Requiere JWT.
+// @Tags         Skills
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  services.SkillMigrationReport
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/skills/catalog/migrate [post]
+func (s *SkillCatalogService) MigrateCatalog(c fiber.Ctx) error {
+	report, err := MigrateTaggedSkills(requestContext(c), s.experiences, s.skills)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "skill_migration_failed", "No se pudo migrar las capacidades", err.Error())
+	}
+	return apiresponse.Success(c, report)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func newCatalogTestApp(svc *SkillCatalogService) *fiber.App {
+	app := fiber.New()
+	app.Get("/skills/catalog", svc.ListPublicCatalog)
+	app.Get("/private/skills/catalog", svc.ListCatalog)
+	app.Post("/private/skills/catalog", svc.CreateCatalogSkill)
+	app.Post("/private/skills/catalog/migrate", svc.MigrateCatalog)
+	app.Put("/private/skills/catalog/:id", svc.UpdateCatalogSkill)
+	app.Delete("/private/skills/catalog/:id", svc.DeleteCatalogSkill)
+	return app
+}
+
+func postCatalogSkill(t *testing.T, app *fiber.App, payload map[string]any) (*http.Response, map[string]any) {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/private/skills/catalog", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var decoded map[string]any
+	_ = json.Unmarshal(raw, &decoded)
+	return res, decoded
+}
+
+func TestCreateCatalogSkillAppliesDefaults(t *testing.T) {
+	svc := NewSkillCatalogService(memory.NewSkillRepository(), memory.NewExperienceRepository())
+	app := newCatalogTestApp(svc)
+
+	res, created := postCatalogSkill(t, app, map[string]any{
+		"name":          "Go",
+		"category":      "  Backend ",
+		"experienceIds": []string{"not-a-uuid", "00000000-0000-0000-0000-000000000001"},
+	})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	if created["category"] != "backend" {
+		t.Errorf("expected normalized category, got %v", created["category"])
+	}
+	if created["proficiency"] != float64(constants.DefaultSkillProficiency) {
+		t.Errorf("expected default proficiency, got %v", created["proficiency"])
+	}
+	if created["visibility"] != constants.VisibilityPublic {
+		t.Errorf("expected public visibility, got %v", created["visibility"])
+	}
+	refs, _ := created["experienceIds"].([]any)
+	if len(refs) != 1 {
+		t.Errorf("expected invalid experience IDs to be dropped, got %v", created["experienceIds"])
+	}
+}
+
+func TestCreateCatalogSkillValidation(t *testing.T) {
+	svc := NewSkillCatalogService(memory.NewSkillRepository(), memory.NewExperienceRepository())
+	app := newCatalogTestApp(svc)
+
+	cases := []struct {
+		name    string
+		payload map[string]any
+	}{
+		{"missing name", map[string]any{"category": "backend"}},
+		{"proficiency too high", map[string]any{"name": "Go", "proficiency": 6}},
+		{"negative years", map[string]any{"name": "Go", "years": -1}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _ := postCatalogSkill(t, app, tc.payload)
+			if res.StatusCode != fiber.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestListPublicCatalogGroupsByCategory(t *testing.T) {
+	svc := NewSkillCatalogService(memory.NewSkillRepository(), memory.NewExperienceRepository())
+	app := newCatalogTestApp(svc)
+
+	postCatalogSkill(t, app, map[string]any{"name": "Go", "category": "backend"})
+	postCatalogSkill(t, app, map[string]any{"name": "React", "category": "frontend"})
+	postCatalogSkill(t, app, map[string]any{"name": "Docker", "category": "backend"})
+	postCatalogSkill(t, app, map[string]any{"name": "Secret", "category": "backend", "visibility": constants.VisibilityPrivate})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/skills/catalog", nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("list failed: err=%v status=%d", err, res.StatusCode)
+	}
+	etag := res.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	var result struct {
+		Categories []skillCategoryGroup `json:"categories"`
+	}
+	raw, _ := io.ReadAll(res.Body)
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(result.Categories) != 2 || result.Categories[0].Category != "backend" {
+		t.Fatalf("expected backend and frontend groups, got %+v", result.Categories)
+	}
+	backend := result.Categories[0].Items
+	if len(backend) != 2 || backend[0].Name != "Docker" || backend[1].Name != "Go" {
+		t.Fatalf("expected public backend skills sorted by name, got %+v", backend)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/skills/catalog", nil)
+	req.Header.Set("If-None-Match", etag)
+	res, err = app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("expected 304, got err=%v status=%d", err, res.StatusCode)
+	}
+}
+
+func TestUpdateAndDeleteCatalogSkill(t *testing.T) {
+	svc := NewSkillCatalogService(memory.NewSkillRepository(), memory.NewExperienceRepository())
+	app := newCatalogTestApp(svc)
+
+	_, created := postCatalogSkill(t, app, map[string]any{"name": "Go", "proficiency": 2})
+	id, _ := created["id"].(string)
+
+	body, _ := json.Marshal(map[string]any{"category": "languages", "proficiency": 5, "years": 6})
+	req := httptest.NewRequest(http.MethodPut, "/private/skills/catalog/"+id, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("update failed: err=%v status=%d", err, res.StatusCode)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var updated models.Skill
+	_ = json.Unmarshal(raw, &updated)
+	if updated.Name != "Go" || updated.Proficiency != 5 || updated.Years != 6 || updated.Category != "languages" {
+		t.Fatalf("unexpected updated skill: %+v", updated)
+	}
+
+	res, err = app.Test(httptest.NewRequest(http.MethodDelete, "/private/skills/catalog/"+id, nil))
+	if err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("delete failed: err=%v status=%d", err, res.StatusCode)
+	}
+	res, err = app.Test(httptest.NewRequest(http.MethodDelete, "/private/skills/catalog/"+id, nil))
+	if err != nil || res.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 on second delete, got err=%v status=%d", err, res.StatusCode)
+	}
+}
+
+func TestMigrateTaggedSkillsIsIdempotent(t *testing.T) {
+	expRepo := memory.NewExperienceRepository()
+	skillRepo := memory.NewSkillRepository()
+	ctx := context.Background()
+
+	_ = expRepo.Create(ctx, models.Experience{
+		ID:         "00000000-0000-0000-0000-000000000001",
+		Title:      "Kubernetes",
+		Tags:       []string{"skill", "DevOps"},
+		Visibility: constants.VisibilityPublic,
+		CreatedAt:  "2024-01-01T00:00:00Z",
+	})
+	_ = expRepo.Create(ctx, models.Experience{
+		ID:    "00000000-0000-0000-0000-000000000002",
+		Title: "Proyecto",
+		Tags:  []string{"go"},
+	})
+
+	report, err := MigrateTaggedSkills(ctx, expRepo, skillRepo)
+	if err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	if report.Scanned != 1 || report.Created != 1 || report.Skipped != 0 {
+		t.Fatalf("unexpected first report: %+v", report)
+	}
+
+	skill, err := skillRepo.GetByID(ctx, "00000000-0000-0000-0000-000000000001")
+	if err != nil {
+		t.Fatalf("expected migrated skill: %v", err)
+	}
+	if skill.Name != "Kubernetes" || skill.Category != "devops" || skill.CreatedAt != "2024-01-01T00:00:00Z" {
+		t.Fatalf("unexpected migrated skill: %+v", skill)
+	}
+	if len(skill.ExperienceIDs) != 1 || skill.ExperienceIDs[0] != "00000000-0000-0000-0000-000000000001" {
+		t.Fatalf("expected link to source experience, got %v", skill.ExperienceIDs)
+	}
+
+	report, err = MigrateTaggedSkills(ctx, expRepo, skillRepo)
+	if err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	if report.Created != 0 || report.Skipped != 1 {
+		t.Fatalf("expected second run to skip, got %+v", report)
+	}
+}
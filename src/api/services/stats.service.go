@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// StatsService computes the public "at a glance" statistics of the portfolio.
+type StatsService struct {
+	repo repository.ExperienceRepository
+}
+
+// NewStatsService creates a StatsService backed by the given ExperienceRepository.
+func NewStatsService(repo repository.ExperienceRepository) *StatsService {
+	return &StatsService{repo: repo}
+}
+
+type statsTagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type statsYearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+type statsRecentItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Type      string `json:"type"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// portfolioStats is the response body of GET /api/stats.
+type portfolioStats struct {
+	Experiences    int               `json:"experiences"`
+	Skills         int               `json:"skills"`
+	ExperienceTags []statsTagCount   `json:"experienceTags"`
+	SkillTags      []statsTagCount   `json:"skillTags"`
+	Years          []statsYearCount  `json:"years"`
+	Recent         []statsRecentItem `json:"recent"`
+}
+
+// publicStats caches the computed statistics of each tenant ("" when
+// MULTI_TENANT is off). Writes in the services call invalidatePublicStats;
+// the generation counter keeps a computation that raced with a write from
+// repopulating the cache with stale data.
+var publicStats = struct {
+	mu         sync.Mutex
+	generation uint64
+	tenants    map[string]*cachedPortfolioStats
+}{}
+
+// cachedPortfolioStats is the cache entry of one tenant.
+type cachedPortfolioStats struct {
+	stats      *portfolioStats
+	etag       string
+	computedAt time.Time
+}
+
+// invalidatePublicStats drops the cached statistics of every tenant after a
+// content write.
+func invalidatePublicStats() {
+	publicStats.mu.Lock()
+	publicStats.generation++
+	publicStats.tenants = nil
+	publicStats.mu.Unlock()
+}
+
+// sortedTagCounts converts a histogram to a slice ordered by count, then tag.
+func sortedTagCounts(counts map[string]int) []statsTagCount {
+	result := make([]statsTagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, statsTagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// computePortfolioStats aggregates the public items. Skills are the items
+// carrying a skill tag; the skill tags themselves are left out of the
+// histograms.
+func computePortfolioStats(items []models.Experience) *portfolioStats {
+	stats := &portfolioStats{}
+	experienceTags := map[string]int{}
+	skillTags := map[string]int{}
+	years := map[int]int{}
+	public := make([]models.Experience, 0, len(items))
+
+	for _, item := range items {
+		if item.Visibility != constants.VisibilityPublic {
+			continue
+		}
+		public = append(public, item)
+
+		skill := isSkillExperience(item)
+		histogram := experienceTags
+		if skill {
+			stats.Skills++
+			histogram = skillTags
+		} else {
+			stats.Experiences++
+		}
+		for _, tag := range item.Tags {
+			if normalized := normalizeTagValue(tag); normalized != "" && !isSkillTag(normalized) {
+				histogram[normalized]++
+			}
+		}
+		if created, err := time.Parse(time.RFC3339, item.CreatedAt); err == nil {
+			years[created.Year()]++
+		}
+	}
+
+	stats.ExperienceTags = sortedTagCounts(experienceTags)
+	stats.SkillTags = sortedTagCounts(skillTags)
+	stats.Years = make([]statsYearCount, 0, len(years))
+	for year, count := range years {
+		stats.Years = append(stats.Years, statsYearCount{Year: year, Count: count})
+	}
+	sort.Slice(stats.Years, func(i, j int) bool { return stats.Years[i].Year < stats.Years[j].Year })
+
+	sort.SliceStable(public, func(i, j int) bool { return public[i].UpdatedAt > public[j].UpdatedAt })
+	if len(public) > constants.StatsRecentItems {
+		public = public[:constants.StatsRecentItems]
+	}
+	stats.Recent = make([]statsRecentItem, 0, len(public))
+	for _, item := range public {
+		kind := "experience"
+		if isSkillExperience(item) {
+			kind = "skill"
+		}
+		stats.Recent = append(stats.Recent, statsRecentItem{ID: item.ID, Title: item.Title, Type: kind, UpdatedAt: item.UpdatedAt})
+	}
+	return stats
+}
+
+// cachedStats returns the cached statistics of tenant, computing them when
+// the cache is empty or older than StatsCacheTTL.
+func (s *StatsService) cachedStats(ctx context.Context, tenant string) (*portfolioStats, string, error) {
+	now := time.Now()
+	publicStats.mu.Lock()
+	if cached := publicStats.tenants[tenant]; cached != nil && now.Sub(cached.computedAt) <= constants.StatsCacheTTL {
+		publicStats.mu.Unlock()
+		return cached.stats, cached.etag, nil
+	}
+	generation := publicStats.generation
+	publicStats.mu.Unlock()
+
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	stats := computePortfolioStats(items)
+	etag := buildPayloadETag(stats)
+
+	publicStats.mu.Lock()
+	if publicStats.generation == generation {
+		if publicStats.tenants == nil {
+			publicStats.tenants = map[string]*cachedPortfolioStats{}
+		}
+		publicStats.tenants[tenant] = &cachedPortfolioStats{stats: stats, etag: etag, computedAt: now}
+	}
+	publicStats.mu.Unlock()
+	return stats, etag, nil
+}
+
+// GetPublicStats godoc
+// @Summary      Estadisticas publicas del portfolio
+// @Description  Devuelve la cantidad de experiencias y skills publicas, histogramas de tags, items por año (segun createdAt) y los ultimos items actualizados. Se cachea en memoria y se invalida con cada escritura. Soporta ETag/If-None-Match.
+// @Tags         Experiences
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "experiences, skills, experienceTags, skillTags, years, recent"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/stats [get]
+func (s *StatsService) GetPublicStats(c fiber.Ctx) error {
+	stats, etag, err := s.cachedStats(requestContext(c), tenantID(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_stats_failed", "No se pudieron calcular las estadisticas", err.Error())
+	}
+
+	etag = tenantETag(c, etag)
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return apiresponse.Success(c, stats)
+}
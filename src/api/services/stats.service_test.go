@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestComputePortfolioStats(t *testing.T) {
+	public := constants.VisibilityPublic
+	items := []models.Experience{
+		{ID: "a", Title: "API", Tags: []string{"go", "api"}, Visibility: public, CreatedAt: "2023-05-01T00:00:00Z", UpdatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "b", Title: "CLI", Tags: []string{"go"}, Visibility: public, CreatedAt: "2024-02-01T00:00:00Z", UpdatedAt: "2024-03-01T00:00:00Z"},
+		{ID: "c", Title: "Go", Tags: []string{"skill", "backend"}, Visibility: public, CreatedAt: "2024-06-01T00:00:00Z", UpdatedAt: "2024-06-01T00:00:00Z"},
+		{ID: "d", Title: "Privada", Tags: []string{"go"}, Visibility: constants.VisibilityPrivate, CreatedAt: "2024-07-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z"},
+	}
+
+	stats := computePortfolioStats(items)
+	if stats.Experiences != 2 || stats.Skills != 1 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if len(stats.ExperienceTags) != 2 || stats.ExperienceTags[0] != (statsTagCount{Tag: "go", Count: 2}) {
+		t.Fatalf("unexpected experience tags: %+v", stats.ExperienceTags)
+	}
+	if len(stats.SkillTags) != 1 || stats.SkillTags[0].Tag != "backend" {
+		t.Fatalf("skill marker tags must be excluded: %+v", stats.SkillTags)
+	}
+	if len(stats.Years) != 2 || stats.Years[0] != (statsYearCount{Year: 2023, Count: 1}) || stats.Years[1].Count != 2 {
+		t.Fatalf("unexpected years: %+v", stats.Years)
+	}
+	if len(stats.Recent) != 3 || stats.Recent[0].ID != "c" || stats.Recent[0].Type != "skill" {
+		t.Fatalf("unexpected recent items: %+v", stats.Recent)
+	}
+}
+
+func TestGetPublicStatsCachesAndInvalidatesOnWrite(t *testing.T) {
+	invalidatePublicStats()
+	t.Cleanup(invalidatePublicStats)
+
+	repo := memory.NewExperienceRepository()
+	_ = repo.Create(context.Background(), models.Experience{ID: "00000000-0000-0000-0000-000000000001", Title: "API", Visibility: constants.VisibilityPublic, Version: 1})
+	stats := NewStatsService(repo)
+	exp := NewExperienceService(repo)
+
+	app := fiber.New()
+	app.Get("/stats", stats.GetPublicStats)
+	app.Delete("/private/experiences/:id", exp.DeleteExperience)
+
+	get := func(ifNoneMatch string) (*http.Response, portfolioStats) {
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var body portfolioStats
+		raw, _ := io.ReadAll(res.Body)
+		_ = json.Unmarshal(raw, &body)
+		return res, body
+	}
+
+	res, body := get("")
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != fiber.StatusOK || body.Experiences != 1 || etag == "" {
+		t.Fatalf("unexpected first response: status=%d body=%+v etag=%q", res.StatusCode, body, etag)
+	}
+	if res.Header.Get("Cache-Control") != constants.PublicCollectionCacheControl {
+		t.Fatalf("expected public cache headers, got %q", res.Header.Get("Cache-Control"))
+	}
+
+	// A write that bypasses the services is not seen until invalidation.
+	_ = repo.Create(context.Background(), models.Experience{ID: "00000000-0000-0000-0000-000000000002", Title: "CLI", Visibility: constants.VisibilityPublic, Version: 1})
+	if res, _ := get(etag); res.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("expected cached 304, got %d", res.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/private/experiences/00000000-0000-0000-0000-000000000001", nil)
+	req.Header.Set("If-Match", `"1"`)
+	if res, err := app.Test(req); err != nil || res.StatusCode != fiber.StatusOK {
+		t.Fatalf("delete failed: err=%v status=%d", err, res.StatusCode)
+	}
+
+	res, body = get(etag)
+	if res.StatusCode != fiber.StatusOK || body.Experiences != 1 || body.Recent[0].Title != "CLI" {
+		t.Fatalf("expected recomputed stats after delete, got status=%d body=%+v", res.StatusCode, body)
+	}
+}
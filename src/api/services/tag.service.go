@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/apiresponse"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TagService handles tag listing, bulk rename/merge and tag aliases.
+type TagService struct {
+	experiences repository.ExperienceRepository
+	aliases     repository.TagAliasRepository
+}
+
+// NewTagService creates a TagService backed by the given repositories.
+func NewTagService(experiences repository.ExperienceRepository, aliases repository.TagAliasRepository) *TagService {
+	return &TagService{experiences: experiences, aliases: aliases}
+}
+
+// tagCount is one entry of the tag listings.
+type tagCount struct {
+	Tag         string `json:"tag"`
+	Count       int    `json:"count"`
+	PublicCount int    `json:"publicCount"`
+}
+
+// tagRenamePayload is the request body of POST /api/private/tags/rename.
+type tagRenamePayload struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	CreateAlias bool   `json:"createAlias"`
+}
+
+// tagMergePayload is the request body of POST /api/private/tags/merge.
+type tagMergePayload struct {
+	Sources       []string `json:"sources"`
+	Target        string   `json:"target"`
+	CreateAliases bool     `json:"createAliases"`
+}
+
+// tagAliasPayload is the request body of PUT /api/private/tags/aliases/:alias.
+type tagAliasPayload struct {
+	Canonical string `json:"canonical"`
+}
+
+// aliasParam returns the normalized :alias path parameter.
+func aliasParam(c fiber.Ctx) string {
+	raw := c.Params("alias")
+	if unescaped, err := url.PathUnescape(raw); err == nil {
+		raw = unescaped
+	}
+	return normalizeTag(raw)
+}
+
+// LoadAliases reads the aliases of the tenant of ctx from the repository
+// into the map used by normalizeTags, which reads those of other tenants on
+// first use. Called at startup; errors are logged and the previous aliases
+// are kept.
+func (s *TagService) LoadAliases(ctx context.Context) {
+	activeTagAliases.mu.Lock()
+	activeTagAliases.load = s.aliases.ListAliases
+	activeTagAliases.mu.Unlock()
+	aliases, err := s.aliases.ListAliases(ctx)
+	if err != nil {
+		log.Printf("[tags] could not load tag aliases: %v", err)
+		return
+	}
+	setActiveTagAliases(ctx, aliases)
+}
+
+// countTags returns the tag usage counts sorted by count (desc) then tag.
+func countTags(items []models.Experience, publicOnly bool) []tagCount {
+	counts := map[string]*tagCount{}
+	for _, item := range items {
+		isPublic := item.Visibility == constants.VisibilityPublic
+		if publicOnly && !isPublic {
+			continue
+		}
+		for _, tag := range item.Tags {
+			entry, ok := counts[tag]
+			if !ok {
+				entry = &tagCount{Tag: tag}
+				counts[tag] = entry
+			}
+			entry.Count++
+			if isPublic {
+				entry.PublicCount++
+			}
+		}
+	}
+
+	result := make([]tagCount, 0, len(counts))
+	for _, entry := range counts {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// replaceTags rewrites every tag in sources to target, keeping order and
+// dropping duplicates. It reports whether anything changed.
+func replaceTags(tags []string, sources map[string]bool, target string) ([]string, bool) {
+	result := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	changed := false
+	for _, tag := range tags {
+		if sources[tag] {
+			tag = target
+			changed = true
+		}
+		if seen[tag] {
+			changed = true
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result, changed
+}
+
+// retagExperiences applies replaceTags to every experience and persists the
+// changed ones, in one atomic batch when the store supports it. Otherwise
+// they are written one by one and the returned IDs are those written before
+// a failure.
+func (s *TagService) retagExperiences(ctx context.Context, sources map[string]bool, target string) ([]string, error) {
+	all, err := s.experiences.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	writes := []repository.ExperienceWrite{}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, item := range all {
+		tags, changed := replaceTags(item.Tags, sources, target)
+		if !changed {
+			continue
+		}
+		item.Tags = tags
+		item.UpdatedAt = now
+		writes = append(writes, repository.ExperienceWrite{Kind: repository.WriteUpdate, Experience: item, ExpectedVersion: item.Version})
+	}
+
+	updated := []string{}
+	batcher, atomic := s.experiences.(repository.BatchExperienceRepository)
+	if atomic && len(writes) > 0 {
+		if err := batcher.ApplyBatch(ctx, writes); err != nil {
+			return updated, err
+		}
+	}
+	for _, w := range writes {
+		item := w.Experience
+		if !atomic {
+			if err := s.experiences.Update(ctx, item, w.ExpectedVersion); err != nil {
+				return updated, err
+			}
+		}
+		updated = append(updated, item.ID)
+		invalidatePublicStats()
+		item.Version = w.ExpectedVersion + 1
+		publishExperienceChange(repository.ChangeUpdated, item, item.Visibility)
+	}
+	return updated, nil
+}
+
+// saveAlias validates and persists an alias, refreshing the active aliases.
+// It returns an error code and message for invalid aliases.
+func (s *TagService) saveAlias(ctx context.Context, alias, canonical string) (string, string, error) {
+	aliases, err := s.aliases.ListAliases(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if code, message := validateAlias(aliases, alias, canonical); code != "" {
+		return code, message, nil
+	}
+
+	if err := s.aliases.SaveAlias(ctx, alias, canonical); err != nil {
+		return "", "", err
+	}
+	aliases[alias] = canonical
+	setActiveTagAliases(ctx, aliases)
+	return "", "", nil
+}
+
+// validateAlias checks an alias against the existing aliases, returning an
+// error code and message when it is invalid.
+func validateAlias(aliases map[string]string, alias, canonical string) (string, string) {
+	if alias == "" || canonical == "" {
+		return "invalid_tag", "El alias y el tag canonico son requeridos"
+	}
+	if alias == canonical {
+		return "invalid_alias", "El alias no puede ser igual al tag canonico"
+	}
+	if isSkillTag(alias) {
+		return "protected_tag", "Los tags de skill no se pueden redefinir"
+	}
+	if _, ok := aliases[canonical]; ok {
+		return "alias_chain", "El tag canonico ya es un alias de otro tag"
+	}
+	for _, target := range aliases {
+		if target == alias {
+			return "alias_chain", "El alias ya es el tag canonico de otros alias"
+		}
+	}
+	return "", ""
+}
+
+// ListPublicTags godoc
+// @Summary      Nube de tags publica
+// @Description  Devuelve los tags de las experiencias publicas con su cantidad de usos. Soporta ETag/If-None-Match.
+// @Tags         Tags
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "items"
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/tags [get]
+func (s *TagService) ListPublicTags(c fiber.Ctx) error {
+	all, err := s.experiences.List(requestContext(c))
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+
+	counts := countTags(all, true)
+	items := make([]fiber.Map, 0, len(counts))
+	for _, entry := range counts {
+		items = append(items, fiber.Map{"tag": entry.Tag, "count": entry.PublicCount})
+	}
+
+	etag := tenantETag(c, buildPayloadETag(items))
+	setPublicCollectionCacheHeaders(c, etag)
+	if matchesIfNoneMatchHeader(c.Get("If-None-Match"), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return apiresponse.Success(c, fiber.Map{"items": items})
+}
+
+// ListTags godoc
+// @Summary      Listar tags
+// @Description  Devuelve todos los tags con cantidad total y publica de usos, y los alias definidos. Requiere JWT.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "items, aliases"
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags [get]
+func (s *TagService) ListTags(c fiber.Ctx) error {
+	ctx := requestContext(c)
+	all, err := s.experiences.List(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_experiences_failed", "No se pudo cargar experiencias", err.Error())
+	}
+	aliases, err := s.aliases.ListAliases(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_tag_aliases_failed", "No se pudo cargar los alias de tags", err.Error())
+	}
+	return apiresponse.Success(c, fiber.Map{"items": countTags(all, false), "aliases": aliases})
+}
+
+// RenameTag godoc
+// @Summary      Renombrar tag
+// @Description  Renombra un tag en todas las experiencias. Con createAlias=true el nombre anterior queda como alias del nuevo. Requiere JWT.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        rename  body  object{from=string,to=string,createAlias=bool}  true  "Tag origen y destino"
+// @Success      200  {object}  map[string]interface{}  "updated, ids"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags/rename [post]
+func (s *TagService) RenameTag(c fiber.Ctx) error {
+	var payload tagRenamePayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	return s.mergeInto(c, []string{payload.From}, payload.To, payload.CreateAlias)
+}
+
+// MergeTags godoc
+// @Summary      Fusionar tags
+// @Description  Reemplaza varios tags por un tag destino en todas las experiencias. Con createAliases=true los tags origen quedan como alias del destino. Requiere JWT.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        merge  body  object{sources=[]string,target=string,createAliases=bool}  true  "Tags origen y destino"
+// @Success      200  {object}  map[string]interface{}  "updated, ids"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      412  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags/merge [post]
+func (s *TagService) MergeTags(c fiber.Ctx) error {
+	var payload tagMergePayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+	return s.mergeInto(c, payload.Sources, payload.Target, payload.CreateAliases)
+}
+
+func (s *TagService) mergeInto(c fiber.Ctx, rawSources []string, rawTarget string, createAliases bool) error {
+	target := normalizeTag(rawTarget)
+	if target == "" {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_tag", "El tag destino es requerido", nil)
+	}
+	if len(rawSources) == 0 || len(rawSources) > constants.MaxTagCount {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_tag", "Debe indicar entre 1 y 20 tags origen", nil)
+	}
+
+	sources := map[string]bool{}
+	for _, raw := range rawSources {
+		tag := normalizeTag(raw)
+		if tag == "" || tag == target {
+			continue
+		}
+		if isSkillTag(tag) {
+			return apiresponse.Error(c, fiber.StatusBadRequest, "protected_tag", "Los tags de skill no se pueden renombrar", tag)
+		}
+		sources[tag] = true
+	}
+	if len(sources) == 0 {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_tag", "Los tags origen deben ser distintos del destino", nil)
+	}
+
+	ctx := requestContext(c)
+	// Validate every alias before writing anything, so an invalid alias does
+	// not leave experiences retagged or only some aliases saved.
+	var aliases map[string]string
+	if createAliases {
+		var err error
+		if aliases, err = s.aliases.ListAliases(ctx); err != nil {
+			return apiresponse.Error(c, fiber.StatusInternalServerError, "load_tag_aliases_failed", "No se pudo cargar los alias de tags", err.Error())
+		}
+		for source := range sources {
+			if code, message := validateAlias(aliases, source, target); code != "" {
+				return apiresponse.Error(c, fiber.StatusBadRequest, code, message, source)
+			}
+		}
+	}
+
+	updated, err := s.retagExperiences(ctx, sources, target)
+	if err != nil {
+		details := fiber.Map{"error": err.Error(), "updatedIds": updated}
+		if errors.Is(err, repository.ErrVersionConflict) || errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusPreconditionFailed, "version_conflict", "El recurso fue modificado por otra persona. Recarga e intenta de nuevo", details)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_experience_failed", "No se pudo actualizar los tags", details)
+	}
+
+	if createAliases {
+		for source := range sources {
+			if err := s.aliases.SaveAlias(ctx, source, target); err != nil {
+				return apiresponse.Error(c, fiber.StatusInternalServerError, "save_tag_alias_failed", "No se pudo guardar el alias", fiber.Map{"error": err.Error(), "alias": source, "updatedIds": updated})
+			}
+			aliases[source] = target
+		}
+		setActiveTagAliases(ctx, aliases)
+	}
+
+	return apiresponse.Success(c, fiber.Map{"updated": len(updated), "ids": updated, "target": target})
+}
+
+// ListTagAliases godoc
+// @Summary      Listar alias de tags
+// @Description  Devuelve el mapa alias → tag canonico aplicado al guardar experiencias. Requiere JWT.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "aliases"
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags/aliases [get]
+func (s *TagService) ListTagAliases(c fiber.Ctx) error {
+	ctx := requestContext(c)
+	aliases, err := s.aliases.ListAliases(ctx)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "load_tag_aliases_failed", "No se pudo cargar los alias de tags", err.Error())
+	}
+	setActiveTagAliases(ctx, aliases)
+	return apiresponse.Success(c, fiber.Map{"aliases": aliases})
+}
+
+// SaveTagAlias godoc
+// @Summary      Definir alias de tag
+// @Description  Define que el tag {alias} se guarde como {canonical} en adelante. No modifica experiencias existentes (usar /tags/merge). Requiere JWT.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        alias  path  string                    true  "Alias"
+// @Param        body   body  object{canonical=string}  true  "Tag canonico"
+// @Success      200  {object}  map[string]interface{}  "alias, canonical"
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags/aliases/{alias} [put]
+func (s *TagService) SaveTagAlias(c fiber.Ctx) error {
+	var payload tagAliasPayload
+	if err := c.Bind().Body(&payload); err != nil {
+		return apiresponse.Error(c, fiber.StatusBadRequest, "invalid_payload", "Payload invalido", err.Error())
+	}
+
+	alias := aliasParam(c)
+	canonical := normalizeTag(payload.Canonical)
+	code, message, err := s.saveAlias(requestContext(c), alias, canonical)
+	if err != nil {
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_tag_alias_failed", "No se pudo guardar el alias", err.Error())
+	}
+	if code != "" {
+		return apiresponse.Error(c, fiber.StatusBadRequest, code, message, nil)
+	}
+	return apiresponse.Success(c, fiber.Map{"alias": alias, "canonical": canonical})
+}
+
+// DeleteTagAlias godoc
+// @Summary      Eliminar alias de tag
+// @Description  Elimina un alias. Requiere JWT.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        alias  path  string  true  "Alias"
+// @Success      200  {object}  map[string]interface{}  "deleted, alias"
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/private/tags/aliases/{alias} [delete]
+func (s *TagService) DeleteTagAlias(c fiber.Ctx) error {
+	alias := aliasParam(c)
+	ctx := requestContext(c)
+	if err := s.aliases.DeleteAlias(ctx, alias); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apiresponse.Error(c, fiber.StatusNotFound, "tag_alias_not_found", "Alias no encontrado", nil)
+		}
+		return apiresponse.Error(c, fiber.StatusInternalServerError, "save_tag_alias_failed", "No se pudo eliminar el alias", err.Error())
+	}
+	s.LoadAliases(ctx)
+	return apiresponse.Success(c, fiber.Map{"deleted": true, "alias": alias})
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+	tenantrepo "backend-yonathan/src/repository/tenant"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func newTagTestApp(t *testing.T) (*fiber.App, *memory.ExperienceRepository, *memory.TagAliasRepository) {
+	t.Helper()
+	t.Cleanup(resetActiveTagAliases)
+
+	expRepo := memory.NewExperienceRepository()
+	aliasRepo := memory.NewTagAliasRepository()
+	svc := NewTagService(expRepo, aliasRepo)
+	exp := NewExperienceService(expRepo)
+
+	app := fiber.New()
+	app.Get("/tags", svc.ListPublicTags)
+	app.Get("/private/tags", svc.ListTags)
+	app.Post("/private/tags/rename", svc.RenameTag)
+	app.Post("/private/tags/merge", svc.MergeTags)
+	app.Get("/private/tags/aliases", svc.ListTagAliases)
+	app.Put("/private/tags/aliases/:alias", svc.SaveTagAlias)
+	app.Delete("/private/tags/aliases/:alias", svc.DeleteTagAlias)
+	app.Post("/private/experiences", exp.CreateExperience)
+	return app, expRepo, aliasRepo
+}
+
+// resetActiveTagAliases forgets the active aliases of every tenant and how
+// to load them.
+func resetActiveTagAliases() {
+	activeTagAliases.mu.Lock()
+	activeTagAliases.tenants = map[string]map[string]string{}
+	activeTagAliases.load = nil
+	activeTagAliases.mu.Unlock()
+}
+
+func sendTagJSON(t *testing.T, app *fiber.App, method, path string, payload any) (*http.Response, map[string]any) {
+	t.Helper()
+	var body io.Reader
+	if payload != nil {
+		raw, _ := json.Marshal(payload)
+		body = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error on %s %s: %v", method, path, err)
+	}
+	raw, _ := io.ReadAll(res.Body)
+	var decoded map[string]any
+	_ = json.Unmarshal(raw, &decoded)
+	return res, decoded
+}
+
+func seedTaggedExperiences(repo *memory.ExperienceRepository) {
+	ctx := context.Background()
+	_ = repo.Create(ctx, models.Experience{ID: "00000000-0000-0000-0000-000000000001", Title: "A", Tags: []string{"golang", "api"}, Visibility: constants.VisibilityPublic})
+	_ = repo.Create(ctx, models.Experience{ID: "00000000-0000-0000-0000-000000000002", Title: "B", Tags: []string{"go", "go-lang"}, Visibility: constants.VisibilityPublic})
+	_ = repo.Create(ctx, models.Experience{ID: "00000000-0000-0000-0000-000000000003", Title: "C", Tags: []string{"go"}, Visibility: constants.VisibilityPrivate})
+}
+
+func TestListTagsCountsUsage(t *testing.T) {
+	app, expRepo, _ := newTagTestApp(t)
+	seedTaggedExperiences(expRepo)
+
+	res, body := sendTagJSON(t, app, http.MethodGet, "/private/tags", nil)
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	items := body["items"].([]any)
+	first := items[0].(map[string]any)
+	if first["tag"] != "go" || first["count"] != float64(2) || first["publicCount"] != float64(1) {
+		t.Fatalf("expected go to lead with 2 uses (1 public), got %v", first)
+	}
+
+	res, body = sendTagJSON(t, app, http.MethodGet, "/tags", nil)
+	if res.StatusCode != fiber.StatusOK || res.Header.Get("ETag") == "" {
+		t.Fatalf("expected 200 with ETag, got %d", res.StatusCode)
+	}
+	for _, raw := range body["items"].([]any) {
+		item := raw.(map[string]any)
+		if item["tag"] == "go" && item["count"] != float64(1) {
+			t.Fatalf("expected public cloud to count only public items, got %v", item)
+		}
+	}
+}
+
+func TestMergeTagsRewritesExperiencesAndCreatesAliases(t *testing.T) {
+	app, expRepo, aliasRepo := newTagTestApp(t)
+	seedTaggedExperiences(expRepo)
+
+	res, body := sendTagJSON(t, app, http.MethodPost, "/private/tags/merge", map[string]any{
+		"sources":       []string{"golang", "Go-Lang"},
+		"target":        "go",
+		"createAliases": true,
+	})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", res.StatusCode, body)
+	}
+	if body["updated"] != float64(2) {
+		t.Fatalf("expected 2 updated experiences, got %v", body["updated"])
+	}
+
+	b, _ := expRepo.GetByID(context.Background(), "00000000-0000-0000-0000-000000000002")
+	if len(b.Tags) != 1 || b.Tags[0] != "go" {
+		t.Fatalf("expected merged tags to be de-duplicated, got %v", b.Tags)
+	}
+
+	aliases, _ := aliasRepo.ListAliases(context.Background())
+	if aliases["golang"] != "go" || aliases["go-lang"] != "go" {
+		t.Fatalf("expected aliases to be created, got %v", aliases)
+	}
+
+	res, created := sendTagJSON(t, app, http.MethodPost, "/private/experiences", map[string]any{
+		"title": "Nuevo",
+		"tags":  []string{"Golang", "go", "api"},
+	})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("create failed: %d", res.StatusCode)
+	}
+	tags := created["tags"].([]any)
+	if len(tags) != 2 || tags[0] != "go" || tags[1] != "api" {
+		t.Fatalf("expected aliases applied on write, got %v", tags)
+	}
+}
+
+func TestMergeTagsWithInvalidAliasWritesNothing(t *testing.T) {
+	app, expRepo, aliasRepo := newTagTestApp(t)
+	seedTaggedExperiences(expRepo)
+	// "go-lang" is already the canonical tag of another alias.
+	_ = aliasRepo.SaveAlias(context.Background(), "golang-dev", "go-lang")
+
+	res, body := sendTagJSON(t, app, http.MethodPost, "/private/tags/merge", map[string]any{
+		"sources":       []string{"golang", "go-lang"},
+		"target":        "go",
+		"createAliases": true,
+	})
+	if res.StatusCode != fiber.StatusBadRequest || body["code"] != "alias_chain" {
+		t.Fatalf("expected alias chain to be rejected, got %d %v", res.StatusCode, body)
+	}
+
+	a, _ := expRepo.GetByID(context.Background(), "00000000-0000-0000-0000-000000000001")
+	if a.Tags[0] != "golang" {
+		t.Fatalf("expected experiences untouched, got %v", a.Tags)
+	}
+	aliases, _ := aliasRepo.ListAliases(context.Background())
+	if len(aliases) != 1 {
+		t.Fatalf("expected no alias saved, got %v", aliases)
+	}
+}
+
+// staleListRepository lists one experience with an outdated version, as if
+// it was edited after the list was read.
+type staleListRepository struct {
+	*memory.ExperienceRepository
+	staleID string
+}
+
+func (r staleListRepository) List(ctx context.Context) ([]models.Experience, error) {
+	all, err := r.ExperienceRepository.List(ctx)
+	for i := range all {
+		if all[i].ID == r.staleID {
+			all[i].Version--
+		}
+	}
+	return all, err
+}
+
+func TestMergeTagsReportsVersionConflict(t *testing.T) {
+	t.Cleanup(resetActiveTagAliases)
+	expRepo := memory.NewExperienceRepository()
+	seedTaggedExperiences(expRepo)
+	ctx := context.Background()
+	b, _ := expRepo.GetByID(ctx, "00000000-0000-0000-0000-000000000002")
+	_ = expRepo.Update(ctx, b, b.Version)
+	app := fiber.New()
+	app.Post("/private/tags/merge", NewTagService(staleListRepository{expRepo, b.ID}, memory.NewTagAliasRepository()).MergeTags)
+
+	res, body := sendTagJSON(t, app, http.MethodPost, "/private/tags/merge", map[string]any{
+		"sources": []string{"golang", "go-lang"},
+		"target":  "go",
+	})
+	if res.StatusCode != fiber.StatusPreconditionFailed || body["code"] != "version_conflict" {
+		t.Fatalf("expected 412 version_conflict, got %d %v", res.StatusCode, body)
+	}
+	if a, _ := expRepo.GetByID(ctx, "00000000-0000-0000-0000-000000000001"); a.Tags[0] != "golang" {
+		t.Fatalf("expected no experience retagged, got %v", a.Tags)
+	}
+}
+
+func TestRenameTagRejectsSkillTags(t *testing.T) {
+	app, _, _ := newTagTestApp(t)
+
+	res, _ := sendTagJSON(t, app, http.MethodPost, "/private/tags/rename", map[string]any{"from": "skill", "to": "ability"})
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for protected tag, got %d", res.StatusCode)
+	}
+	res, _ = sendTagJSON(t, app, http.MethodPost, "/private/tags/rename", map[string]any{"from": "go", "to": ""})
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for empty target, got %d", res.StatusCode)
+	}
+}
+
+func TestTagAliasesCRUDAndChains(t *testing.T) {
+	app, _, _ := newTagTestApp(t)
+
+	res, _ := sendTagJSON(t, app, http.MethodPut, "/private/tags/aliases/js", map[string]any{"canonical": "JavaScript"})
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	if tagAliasesFor(context.Background())["js"] != "javascript" {
+		t.Fatalf("expected active alias to be refreshed")
+	}
+
+	res, body := sendTagJSON(t, app, http.MethodPut, "/private/tags/aliases/ecmascript", map[string]any{"canonical": "js"})
+	if res.StatusCode != fiber.StatusBadRequest || body["code"] != "alias_chain" {
+		t.Fatalf("expected alias chain to be rejected, got %d %v", res.StatusCode, body)
+	}
+
+	res, body = sendTagJSON(t, app, http.MethodGet, "/private/tags/aliases", nil)
+	if res.StatusCode != fiber.StatusOK || body["aliases"].(map[string]any)["js"] != "javascript" {
+		t.Fatalf("unexpected alias list: %d %v", res.StatusCode, body)
+	}
+
+	res, _ = sendTagJSON(t, app, http.MethodDelete, "/private/tags/aliases/js", nil)
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200 on delete, got %d", res.StatusCode)
+	}
+	if _, ok := tagAliasesFor(context.Background())["js"]; ok {
+		t.Fatalf("expected alias removed from active aliases")
+	}
+	res, _ = sendTagJSON(t, app, http.MethodDelete, "/private/tags/aliases/js", nil)
+	if res.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 on second delete, got %d", res.StatusCode)
+	}
+}
+
+func TestTagAliasesAreScopedByTenant(t *testing.T) {
+	t.Setenv("MULTI_TENANT", "true")
+	t.Setenv("DEFAULT_TENANT_ID", "ana")
+	t.Cleanup(resetActiveTagAliases)
+	aliasRepo := tenantrepo.NewTagAliasRepository(memory.NewTagAliasRepository(), "ana")
+	ana := repository.WithTenant(context.Background(), "ana")
+	bob := repository.WithTenant(context.Background(), "bob")
+	_ = aliasRepo.SaveAlias(bob, "js", "javascript")
+	NewTagService(memory.NewExperienceRepository(), aliasRepo).LoadAliases(context.Background())
+
+	if tags := normalizeTags(ana, []string{"JS"}); len(tags) != 1 || tags[0] != "js" {
+		t.Fatalf("expected bob's alias not to apply to ana, got %v", tags)
+	}
+	if tags := normalizeTags(bob, []string{"JS"}); len(tags) != 1 || tags[0] != "javascript" {
+		t.Fatalf("expected bob's alias loaded on first use, got %v", tags)
+	}
+}
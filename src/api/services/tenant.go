@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net"
+	"strings"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// --- Tenant resolution ---
+
+// tenantID returns the tenant whose portfolio a request acts on. Private
+// routes use the authenticated user; public routes use the :tenant path
+// segment, then TENANT_HOSTS for the Host header, then DEFAULT_TENANT_ID.
+// It is always empty when MULTI_TENANT is off.
+func tenantID(c fiber.Ctx) string {
+	if !constants.MultiTenant() {
+		return ""
+	}
+	if userID, _ := c.Locals("userId").(string); userID != "" {
+		return userID
+	}
+	if tenant := strings.TrimSpace(c.Params("tenant")); tenant != "" {
+		return tenant
+	}
+	host := strings.ToLower(c.Hostname())
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	if tenant, ok := constants.TenantHosts()[host]; ok {
+		return tenant
+	}
+	return constants.DefaultTenantID()
+}
+
+// requestContext returns the context for the repository and network calls
+// of a request: the request context, which carries its deadline, scoped to
+// the request's tenant when MULTI_TENANT is on.
+func requestContext(c fiber.Ctx) context.Context {
+	ctx := c.Context()
+	if constants.MultiTenant() {
+		ctx = repository.WithTenant(ctx, tenantID(c))
+	}
+	return ctx
+}
+
+// experienceTenant returns the tenant that owns item, treating experiences
+// stored before tenants existed as owned by the default tenant.
+func experienceTenant(item models.Experience) string {
+	if item.TenantID == "" {
+		return constants.DefaultTenantID()
+	}
+	return item.TenantID
+}
+
+// tenantETag qualifies a collection ETag with the tenant of the request, so
+// that equal content of two portfolios never validates across them.
+func tenantETag(c fiber.Ctx, etag string) string {
+	tenant := tenantID(c)
+	if tenant == "" || etag == "" {
+		return etag
+	}
+	sum := sha1.Sum([]byte(tenant + "\x00" + etag))
+	return "W/\"" + hex.EncodeToString(sum[:]) + "\""
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository/memory"
+	tenantrepo "backend-yonathan/src/repository/tenant"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestTenantScopedRoutes(t *testing.T) {
+	t.Setenv("MULTI_TENANT", "true")
+	t.Setenv("DEFAULT_TENANT_ID", "ana")
+	t.Setenv("TENANT_HOSTS", "bob.example.com=bob")
+	invalidatePublicStats()
+	t.Cleanup(invalidatePublicStats)
+
+	inner := memory.NewExperienceRepository()
+	public := constants.VisibilityPublic
+	_ = inner.Create(context.Background(), models.Experience{ID: "00000000-0000-0000-0000-00000000000a", Title: "Igual", Visibility: public, Version: 1})
+	_ = inner.Create(context.Background(), models.Experience{ID: "00000000-0000-0000-0000-00000000000b", TenantID: "bob", Title: "Igual", Visibility: public, Version: 1})
+	repo := tenantrepo.NewExperienceRepository(inner, constants.DefaultTenantID())
+	exp := NewExperienceService(repo)
+	stats := NewStatsService(repo)
+
+	app := fiber.New()
+	app.Get("/api/experiences", exp.ListPublicExperiences)
+	app.Get("/api/tenants/:tenant/experiences", exp.ListPublicExperiences)
+	app.Get("/api/tenants/:tenant/stats", stats.GetPublicStats)
+	private := app.Group("/api/private", func(c fiber.Ctx) error {
+		c.Locals("userId", c.Get("X-User"))
+		return c.Next()
+	})
+	private.Get("/experiences", exp.ListAllExperiences)
+	private.Post("/experiences", exp.CreateExperience)
+
+	do := func(req *http.Request) (*http.Response, []models.Experience) {
+		t.Helper()
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Items []models.Experience `json:"items"`
+		}
+		raw, _ := io.ReadAll(res.Body)
+		_ = json.Unmarshal(raw, &body)
+		return res, body.Items
+	}
+	get := func(path, host string) (*http.Response, []models.Experience) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if host != "" {
+			req.Host = host
+		}
+		return do(req)
+	}
+
+	res, items := get("/api/experiences", "")
+	if len(items) != 1 || items[0].ID != "00000000-0000-0000-0000-00000000000a" {
+		t.Fatalf("default tenant items = %+v", items)
+	}
+	if etag := res.Header.Get("ETag"); etag == "" || etag == buildCollectionETag(items) {
+		t.Fatalf("ETag %q is not qualified with the tenant", etag)
+	}
+	if _, items := get("/api/tenants/bob/experiences", ""); len(items) != 1 || items[0].TenantID != "bob" {
+		t.Fatalf("path tenant items = %+v", items)
+	}
+	if _, items := get("/api/experiences", "bob.example.com"); len(items) != 1 || items[0].TenantID != "bob" {
+		t.Fatalf("host tenant items = %+v", items)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/private/experiences", strings.NewReader(`{"title":"Nueva","visibility":"public"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "carla")
+	if res, _ := do(req); res.StatusCode != fiber.StatusOK {
+		t.Fatalf("create status = %d", res.StatusCode)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/private/experiences", nil)
+	req.Header.Set("X-User", "carla")
+	if _, items := do(req); len(items) != 1 || items[0].Title != "Nueva" || items[0].TenantID != "carla" {
+		t.Fatalf("private items of carla = %+v", items)
+	}
+	if _, items := get("/api/experiences", ""); len(items) != 1 {
+		t.Fatalf("another tenant's write leaked into the default portfolio: %+v", items)
+	}
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/tenants/carla/stats", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body portfolioStats
+	raw, _ := io.ReadAll(res.Body)
+	_ = json.Unmarshal(raw, &body)
+	if body.Experiences != 1 || len(body.Recent) != 1 || body.Recent[0].Title != "Nueva" {
+		t.Fatalf("carla stats = %+v", body)
+	}
+}
+
+func TestSkillCatalogETagIsScopedByTenant(t *testing.T) {
+	t.Setenv("MULTI_TENANT", "true")
+	t.Setenv("DEFAULT_TENANT_ID", "ana")
+
+	skills := tenantrepo.NewSkillRepository(memory.NewSkillRepository(), "ana")
+	catalog := NewSkillCatalogService(skills, memory.NewExperienceRepository())
+	app := fiber.New()
+	app.Get("/api/skills/catalog", catalog.ListPublicCatalog)
+	app.Get("/api/tenants/:tenant/skills/catalog", catalog.ListPublicCatalog)
+
+	etags := map[string]bool{}
+	for _, path := range []string{"/api/skills/catalog", "/api/tenants/bob/skills/catalog"} {
+		res, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil || res.StatusCode != fiber.StatusOK {
+			t.Fatalf("GET %s: %v %v", path, res, err)
+		}
+		etags[res.Header.Get("ETag")] = true
+	}
+	if len(etags) != 2 {
+		t.Fatalf("empty catalogs of two tenants share an ETag: %v", etags)
+	}
+}
@@ -2,16 +2,30 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"sync"
 	"time"
 
+	"backend-yonathan/src/pkg/resilience"
+
 	"cloud.google.com/go/compute/metadata"
 	"cloud.google.com/go/storage"
 )
 
+// gcsDependency names the breaker shared by every GCS call.
+const gcsDependency = "gcs"
+
+// gcsPolicy is the retry policy of GCS calls. Attempts are bounded only by
+// the request deadline, since an upload can take longer than a read.
+func gcsPolicy() resilience.Policy {
+	policy := resilience.PolicyFromEnv()
+	policy.AttemptTimeout = 0
+	return policy
+}
+
 // saEmail caches the service account email used for GCS URL signing.
 var (
 	saEmail     string
@@ -80,3 +94,28 @@ func signGCSURL(ctx context.Context, bucketName, objectPath string, expiry time.
 	}
 	return client.Bucket(bucketName).SignedURL(objectPath, opts)
 }
+
+// downloadFromGCS reads an object from GCS, returning its content and content type.
+// Objects larger than maxBytes are rejected to bound memory usage.
+func downloadFromGCS(ctx context.Context, bucketName, objectPath string, maxBytes int64) ([]byte, string, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer client.Close()
+
+	r, err := client.Bucket(bucketName).Object(objectPath).NewReader(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("object %s exceeds %d bytes", objectPath, maxBytes)
+	}
+	return data, r.Attrs.ContentType, nil
+}
@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"io"
 	"path/filepath"
 	"strings"
 
 	"backend-yonathan/src/pkg/apiresponse"
 	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/pkg/resilience"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
@@ -64,8 +66,15 @@ func UploadImage(c fiber.Ctx) error {
 	}
 	defer opened.Close()
 
-	ctx := context.Background()
-	publicURL, err := uploadToBucketFunc(ctx, bucket, objectPath, contentType, opened)
+	var publicURL string
+	err = resilience.Do(requestContext(c), resilience.BreakerFromEnv(gcsDependency), gcsPolicy(), func(ctx context.Context) error {
+		// A failed attempt may have consumed part of the file.
+		if _, err := opened.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		publicURL, err = uploadToBucketFunc(ctx, bucket, objectPath, contentType, opened)
+		return err
+	})
 	if err != nil {
 		return apiresponse.Error(c, fiber.StatusInternalServerError, "upload_failed", "No se pudo subir la imagen", err.Error())
 	}
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
+	"google.golang.org/api/googleapi"
 )
 
 func TestUploadImage_NotConfigured(t *testing.T) {
@@ -162,6 +163,48 @@ func TestUploadImage_Success(t *testing.T) {
 	}
 }
 
+func TestUploadImage_RetriesTransientFailures(t *testing.T) {
+	t.Setenv("GCS_BUCKET_NAME", "test-bucket")
+	t.Setenv("RETRY_BASE_DELAY_MS", "1")
+
+	original := uploadToBucketFunc
+	attempts := 0
+	uploadToBucketFunc = func(ctx context.Context, bucket, objectPath, contentType string, content io.Reader) (string, error) {
+		attempts++
+		data, _ := io.ReadAll(content)
+		if string(data) != "fake jpeg content" {
+			t.Errorf("attempt %d read %q, want the whole file", attempts, data)
+		}
+		if attempts == 1 {
+			return "", &googleapi.Error{Code: http.StatusServiceUnavailable}
+		}
+		return "https://storage.googleapis.com/test-bucket/" + objectPath, nil
+	}
+	t.Cleanup(func() { uploadToBucketFunc = original })
+
+	app := fiber.New()
+	app.Post("/upload-image", UploadImage)
+
+	body := &bytes.Buffer{}
+	mp := multipart.NewWriter(body)
+	part, _ := mp.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="file"; filename="photo.jpg"`},
+		"Content-Type":        {"image/jpeg"},
+	})
+	_, _ = part.Write([]byte("fake jpeg content"))
+	_ = mp.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload-image", body)
+	req.Header.Set("Content-Type", mp.FormDataContentType())
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode != fiber.StatusOK || attempts != 2 {
+		t.Fatalf("expected 200 after 2 attempts, got %d after %d", res.StatusCode, attempts)
+	}
+}
+
 func TestIsAllowedImageContentType(t *testing.T) {
 	tests := []struct {
 		ct   string
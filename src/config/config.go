@@ -12,13 +12,18 @@ import (
 // ConfigAWS initialises a DynamoDB client using the default AWS credential
 // chain (env vars → shared credentials → IAM role). Requires AWS_REGION.
 func ConfigAWS() (*dynamodb.Client, error) {
-	region := os.Getenv("AWS_REGION")
+	return NewDynamoDBClient(context.TODO(), os.Getenv("AWS_REGION"))
+}
+
+// NewDynamoDBClient initialises a DynamoDB client for region using the
+// default AWS credential chain.
+func NewDynamoDBClient(ctx context.Context, region string) (*dynamodb.Client, error) {
 	if region == "" {
 		return nil, errors.New("AWS_REGION is not set")
 	}
 
 	cfg, err := config.LoadDefaultConfig(
-		context.TODO(),
+		ctx,
 		config.WithRegion(region),
 	)
 	if err != nil {
@@ -11,12 +11,17 @@ import (
 // ConfigFirestore initialises a Firestore client using Application Default
 // Credentials. Requires GCP_PROJECT_ID env var.
 func ConfigFirestore() (*firestore.Client, error) {
-	projectID := os.Getenv("GCP_PROJECT_ID")
+	return NewFirestoreClient(context.Background(), os.Getenv("GCP_PROJECT_ID"))
+}
+
+// NewFirestoreClient initialises a Firestore client for projectID using
+// Application Default Credentials.
+func NewFirestoreClient(ctx context.Context, projectID string) (*firestore.Client, error) {
 	if projectID == "" {
 		return nil, errors.New("GCP_PROJECT_ID is not set")
 	}
 
-	client, err := firestore.NewClient(context.Background(), projectID)
+	client, err := firestore.NewClient(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -2,12 +2,17 @@ package userModel
 
 type Experience struct {
 	ID         string   `json:"id"`
+	TenantID   string   `json:"tenantId,omitempty"`
 	Title      string   `json:"title"`
 	Summary    string   `json:"summary"`
 	Body       string   `json:"body"`
 	ImageURLs  []string `json:"imageUrls"`
 	Tags       []string `json:"tags"`
+	RelatedIDs []string `json:"relatedIds,omitempty"`
 	Visibility string   `json:"visibility"`
+	Position   int      `json:"position"`
+	Pinned     bool     `json:"pinned"`
+	Version    int64    `json:"version"`
 	CreatedAt  string   `json:"createdAt"`
 	UpdatedAt  string   `json:"updatedAt"`
 }
@@ -0,0 +1,18 @@
+package userModel
+
+// Skill is a structured skill entry of the portfolio (e.g. "Go", category
+// "backend", proficiency 4 of 5) optionally linked to the experiences where
+// it was applied.
+type Skill struct {
+	ID            string   `json:"id"`
+	TenantID      string   `json:"tenantId,omitempty"`
+	Name          string   `json:"name"`
+	Category      string   `json:"category"`
+	Proficiency   int      `json:"proficiency"`
+	Years         int      `json:"years"`
+	Icon          string   `json:"icon"`
+	ExperienceIDs []string `json:"experienceIds"`
+	Visibility    string   `json:"visibility"`
+	CreatedAt     string   `json:"createdAt"`
+	UpdatedAt     string   `json:"updatedAt"`
+}
@@ -7,4 +7,7 @@ type User struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	UserName string `json:"username"`
+	// Encrypted holds the sealed sensitive fields when user field encryption
+	// is on; Email then holds the blind index of the email.
+	Encrypted string `json:"encrypted,omitempty"`
 }
@@ -15,8 +15,11 @@ const (
 
 // DynamoDB defaults.
 const (
-	DefaultDynamoDBTable = "users"
-	DynamoDBEmailIndex   = "email-index"
+	DefaultDynamoDBTable            = "users"
+	DynamoDBEmailIndex              = "email-index"
+	DefaultDynamoDBExperiencesTable = "experiences"
+	// DynamoDBMaxTransactItems is the TransactWriteItems limit per request.
+	DynamoDBMaxTransactItems = 100
 )
 
 // DNS defaults.
@@ -35,6 +38,112 @@ var DNSBLProviders = []string{
 // Cache-Control header for public collection responses.
 const PublicCollectionCacheControl = "public, max-age=60, stale-while-revalidate=300"
 
+// Public site and feed defaults.
+const (
+	DefaultPublicSiteURL   = "https://yonathangutierrez.dev"
+	DefaultPortfolioAuthor = "Yonathan Gutierrez"
+	FeedTitle              = "Portfolio - Yonathan Gutierrez"
+	FeedDescription        = "Proyectos y experiencias publicadas en el portfolio"
+	FeedLanguage           = "es"
+	FeedMaxItems           = 50
+)
+
+// PublicSiteURL returns the public frontend URL (no trailing slash) used to
+// build absolute links in feeds and sitemaps.
+func PublicSiteURL() string {
+	if val := strings.TrimRight(os.Getenv("PUBLIC_SITE_URL"), "/"); val != "" {
+		return val
+	}
+	return DefaultPublicSiteURL
+}
+
+// PortfolioAuthor returns the portfolio owner name used in feeds and structured data.
+func PortfolioAuthor() string {
+	if val := os.Getenv("PORTFOLIO_AUTHOR_NAME"); val != "" {
+		return val
+	}
+	return DefaultPortfolioAuthor
+}
+
+// Content validation rules, evaluated per collection on experience and skill writes.
+const (
+	ValidationCollectionExperiences = "experiences"
+	ValidationCollectionSkills      = "skills"
+)
+
+// ValidationRulesFile returns the path of the JSON/YAML content rules file, or
+// "" when no rules are configured.
+func ValidationRulesFile() string {
+	return os.Getenv("VALIDATION_RULES_FILE")
+}
+
+// Related-content recommendations. The score of a candidate is
+// RelatedTagWeight * tag Jaccard + RelatedTextWeight * TF-IDF cosine.
+const (
+	RelatedTagWeight     = 0.6
+	RelatedTextWeight    = 0.4
+	RelatedDefaultLimit  = 4
+	RelatedMaxLimit      = 12
+	MaxRelatedOverrides  = 10
+	RelatedMinTokenRunes = 3
+	RelatedIndexTTL      = 10 * time.Minute
+)
+
+// Public statistics (GET /api/stats). The cache is invalidated on writes;
+// StatsCacheTTL bounds staleness when another instance writes.
+const (
+	StatsRecentItems = 5
+	StatsCacheTTL    = 5 * time.Minute
+)
+
+// Experience repository cache. Writes through the cache invalidate it;
+// the TTL bounds staleness when another instance writes.
+const (
+	DefaultExperienceCacheTTL      = 30 * time.Second
+	DefaultExperienceCacheMaxItems = 500
+)
+
+// Request deadlines. Every route gets DefaultRequestTimeout except the
+// uploads, archive and slow DNS tools, which get DefaultLongRequestTimeout;
+// change feed streams have none.
+const (
+	DefaultRequestTimeout     = 10 * time.Second
+	DefaultLongRequestTimeout = 60 * time.Second
+)
+
+// Cloud dependency resilience. Reads from Firestore, DynamoDB and Postgres
+// and calls to GCS are retried up to DefaultRetryAttempts times with
+// exponential backoff and jitter; DefaultBreakerFailureThreshold consecutive
+// transient failures open the breaker of the dependency, which then fails
+// fast with 503 for DefaultBreakerOpenTimeout.
+const (
+	DefaultRetryAttempts           = 3
+	DefaultRetryBaseDelay          = 100 * time.Millisecond
+	DefaultRetryMaxDelay           = 2 * time.Second
+	DefaultAttemptTimeout          = 5 * time.Second
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenTimeout      = 30 * time.Second
+)
+
+// DefaultUserEncryptedFields are the user fields encrypted at rest when
+// USER_ENCRYPTION_KEY_FILE is set and USER_ENCRYPTED_FIELDS is not.
+const DefaultUserEncryptedFields = "email"
+
+// Change feed (GET /api/experiences/stream). ChangeFeedBufferSize bounds the
+// events kept for Last-Event-ID replay; a subscriber that falls
+// ChangeFeedSubscriberBuffer events behind is disconnected and must resume.
+// Streams end after ChangeFeedMaxStream so proxies with request timeouts see
+// a clean close; clients reconnect with Last-Event-ID.
+const (
+	ChangeFeedBufferSize       = 256
+	ChangeFeedSubscriberBuffer = 64
+	ChangeFeedHeartbeat        = 15 * time.Second
+	ChangeFeedMaxStream        = 5 * time.Minute
+	ChangeFeedRetryDelay       = 5 * time.Second
+	ChangeFeedSourceLocal      = "local"
+	ChangeFeedSourceFirestore  = "firestore"
+)
+
 // Certificate generation defaults.
 const (
 	DefaultCertCommonName   = "localhost"
@@ -51,6 +160,17 @@ var SkillTags = []string{
 	"capacidad", "capacidades",
 }
 
+// Structured skill defaults and limits.
+const (
+	DefaultSkillCategory    = "general"
+	DefaultSkillProficiency = 3
+	MinSkillProficiency     = 1
+	MaxSkillProficiency     = 5
+	MaxSkillYears           = 60
+	MaxSkillIconLength      = 200
+	MaxSkillExperienceRefs  = 20
+)
+
 // File permissions.
 const (
 	DirPermission  = 0o755
@@ -64,8 +184,15 @@ const APIContactPath = "/api/contact"
 const (
 	DefaultDataDir      = "data"
 	ExperiencesFilename = "experiences.json"
+	SkillsFilename      = "skills.json"
+	TagAliasesFilename  = "tag_aliases.json"
 	UsersFilename       = "users.json"
 	DataDirEnvVar       = "PORTFOLIO_DATA_DIR"
+	SQLiteFilename      = "portfolio.db"
+	DatabaseURLEnvVar   = "DATABASE_URL"
+	// DefaultJSONBackups is how many rotated backups the JSON backend keeps
+	// of each data file (<file>.bak.1 is the most recent).
+	DefaultJSONBackups = 3
 )
 
 // Input validation limits.
@@ -75,6 +202,8 @@ const (
 	MaxBodyLength      = 50000
 	MaxTagLength       = 50
 	MaxTagCount        = 20
+	MaxReorderItems    = 500
+	MaxBatchOperations = 100
 	MaxImageURLCount   = 10
 	MaxImageURLLength  = 2048 // GCS signed URLs can be long; keep below browser/server limits
 	MaxBase64InputSize = 1_000_000
@@ -113,6 +242,17 @@ const (
 	StorageImagePrefix  = "portfolio-images"
 )
 
+// Content archive (export/import) format and limits.
+const (
+	ArchiveFormat          = "portfolio-archive"
+	ArchiveFormatVersion   = 1
+	ArchiveManifestFile    = "manifest.json"
+	ArchiveExperiencesFile = "experiences.json"
+	ArchiveSkillsFile      = "skills.json"
+	ArchiveImagesDir       = "images/"
+	MaxImportItems         = 1000
+)
+
 // Allowed image MIME types for upload.
 var AllowedImageContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
 
@@ -142,6 +282,8 @@ func GCSURLPrefix() string {
 const (
 	FirestoreUsersCollection       = "users"
 	FirestoreExperiencesCollection = "experiences"
+	FirestoreSkillsCollection      = "skills"
+	FirestoreTagAliasesCollection  = "tagAliases"
 )
 
 // RegistrationEnabled returns whether public user registration is allowed.
@@ -164,6 +306,171 @@ func JWTExpiryDuration() time.Duration {
 	return DefaultJWTExpiryHours * time.Hour
 }
 
+// ExperienceCacheTTL reads EXPERIENCES_CACHE_TTL_SECONDS from env with a
+// fallback. Zero disables the experience repository cache.
+func ExperienceCacheTTL() time.Duration {
+	if val := os.Getenv("EXPERIENCES_CACHE_TTL_SECONDS"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return DefaultExperienceCacheTTL
+}
+
+// ExperienceCacheMaxItems reads EXPERIENCES_CACHE_MAX_ITEMS from env with a
+// fallback.
+func ExperienceCacheMaxItems() int {
+	if val := os.Getenv("EXPERIENCES_CACHE_MAX_ITEMS"); val != "" {
+		if count, err := strconv.Atoi(val); err == nil && count > 0 {
+			return count
+		}
+	}
+	return DefaultExperienceCacheMaxItems
+}
+
+// ChangeFeedSource reads CHANGE_FEED_SOURCE from env. "firestore" feeds the
+// change stream from Firestore snapshot listeners, so every instance sees
+// writes made by the others; anything else publishes local writes only.
+func ChangeFeedSource() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("CHANGE_FEED_SOURCE")), ChangeFeedSourceFirestore) {
+		return ChangeFeedSourceFirestore
+	}
+	return ChangeFeedSourceLocal
+}
+
+// RequestTimeout reads REQUEST_TIMEOUT_SECONDS from env with a fallback.
+// Zero disables request deadlines.
+func RequestTimeout() time.Duration {
+	return durationSecondsEnv("REQUEST_TIMEOUT_SECONDS", DefaultRequestTimeout)
+}
+
+// LongRequestTimeout reads LONG_REQUEST_TIMEOUT_SECONDS from env with a
+// fallback. Zero disables the deadline of long routes.
+func LongRequestTimeout() time.Duration {
+	return durationSecondsEnv("LONG_REQUEST_TIMEOUT_SECONDS", DefaultLongRequestTimeout)
+}
+
+// RetryAttempts reads RETRY_MAX_ATTEMPTS from env with a fallback. One
+// disables retries.
+func RetryAttempts() int {
+	return positiveIntEnv("RETRY_MAX_ATTEMPTS", DefaultRetryAttempts)
+}
+
+// RetryBaseDelay reads RETRY_BASE_DELAY_MS from env with a fallback.
+func RetryBaseDelay() time.Duration {
+	return durationMillisEnv("RETRY_BASE_DELAY_MS", DefaultRetryBaseDelay)
+}
+
+// RetryMaxDelay reads RETRY_MAX_DELAY_MS from env with a fallback.
+func RetryMaxDelay() time.Duration {
+	return durationMillisEnv("RETRY_MAX_DELAY_MS", DefaultRetryMaxDelay)
+}
+
+// AttemptTimeout reads ATTEMPT_TIMEOUT_SECONDS from env with a fallback. It
+// bounds each attempt of a repository call; zero leaves only the request
+// deadline.
+func AttemptTimeout() time.Duration {
+	return durationSecondsEnv("ATTEMPT_TIMEOUT_SECONDS", DefaultAttemptTimeout)
+}
+
+// BreakerFailureThreshold reads BREAKER_FAILURE_THRESHOLD from env with a
+// fallback. Zero disables the circuit breakers.
+func BreakerFailureThreshold() int {
+	if val := os.Getenv("BREAKER_FAILURE_THRESHOLD"); val != "" {
+		if count, err := strconv.Atoi(val); err == nil && count >= 0 {
+			return count
+		}
+	}
+	return DefaultBreakerFailureThreshold
+}
+
+// BreakerOpenTimeout reads BREAKER_OPEN_SECONDS from env with a fallback.
+func BreakerOpenTimeout() time.Duration {
+	return durationSecondsEnv("BREAKER_OPEN_SECONDS", DefaultBreakerOpenTimeout)
+}
+
+// UserEncryptionKeyFile reads USER_ENCRYPTION_KEY_FILE from env: the key
+// file that encrypts sensitive user fields at rest. Empty disables
+// encryption.
+func UserEncryptionKeyFile() string {
+	return strings.TrimSpace(os.Getenv("USER_ENCRYPTION_KEY_FILE"))
+}
+
+// UserEncryptedFields reads USER_ENCRYPTED_FIELDS from env with a fallback:
+// the comma-separated user fields that are encrypted.
+func UserEncryptedFields() string {
+	if val := strings.TrimSpace(os.Getenv("USER_ENCRYPTED_FIELDS")); val != "" {
+		return val
+	}
+	return DefaultUserEncryptedFields
+}
+
+func positiveIntEnv(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if count, err := strconv.Atoi(val); err == nil && count > 0 {
+			return count
+		}
+	}
+	return fallback
+}
+
+func durationMillisEnv(key string, fallback time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if millis, err := strconv.Atoi(val); err == nil && millis >= 0 {
+			return time.Duration(millis) * time.Millisecond
+		}
+	}
+	return fallback
+}
+
+func durationSecondsEnv(key string, fallback time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return fallback
+}
+
+// MultiTenant reports whether MULTI_TENANT enables several portfolios in one
+// deployment, each scoped to the tenant of the request.
+func MultiTenant() bool {
+	val := strings.ToLower(os.Getenv("MULTI_TENANT"))
+	return val == "true" || val == "1"
+}
+
+// DefaultTenantID reads DEFAULT_TENANT_ID from env. It owns experiences
+// stored before tenants existed and serves public requests that name no
+// tenant.
+func DefaultTenantID() string {
+	return strings.TrimSpace(os.Getenv("DEFAULT_TENANT_ID"))
+}
+
+// TenantHosts parses TENANT_HOSTS ("host=tenant,host=tenant") into a map of
+// lowercase host names to tenant IDs. Malformed entries are ignored.
+func TenantHosts() map[string]string {
+	hosts := map[string]string{}
+	for _, entry := range strings.Split(os.Getenv("TENANT_HOSTS"), ",") {
+		host, tenant, ok := strings.Cut(entry, "=")
+		host, tenant = strings.ToLower(strings.TrimSpace(host)), strings.TrimSpace(tenant)
+		if ok && host != "" && tenant != "" {
+			hosts[host] = tenant
+		}
+	}
+	return hosts
+}
+
+// JSONBackupCount reads PORTFOLIO_JSON_BACKUPS from env with a fallback.
+// Zero disables backups.
+func JSONBackupCount() int {
+	if val := os.Getenv("PORTFOLIO_JSON_BACKUPS"); val != "" {
+		if count, err := strconv.Atoi(val); err == nil && count >= 0 {
+			return count
+		}
+	}
+	return DefaultJSONBackups
+}
+
 // TableName returns the DynamoDB table name from env or the default.
 func TableName() string {
 	if name := os.Getenv("DYNAMO_DB_TABLE"); name != "" {
@@ -172,6 +479,14 @@ func TableName() string {
 	return DefaultDynamoDBTable
 }
 
+// ExperiencesTableName returns the DynamoDB experiences table name from env or the default.
+func ExperiencesTableName() string {
+	if name := os.Getenv("DYNAMO_DB_EXPERIENCES_TABLE"); name != "" {
+		return name
+	}
+	return DefaultDynamoDBExperiencesTable
+}
+
 // GCSBucketName returns the GCP Storage bucket name for image uploads from env.
 // If empty, the upload endpoint will return 503 (service not configured).
 func GCSBucketName() string {
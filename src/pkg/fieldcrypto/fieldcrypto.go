@@ -0,0 +1,111 @@
+// Package fieldcrypto encrypts individual fields at rest with envelope
+// encryption: every value is sealed with its own random data key, which is
+// wrapped by a key encryption key of a KeyProvider. Equality lookups on
+// encrypted fields use blind indexes, keyed hashes of the plaintext.
+package fieldcrypto
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMalformed is returned for sealed values that cannot be parsed or fail
+// authentication, e.g. because they were modified or moved to another record.
+var ErrMalformed = errors.New("malformed sealed value")
+
+const (
+	// sealedPrefix versions the layout of sealed values:
+	// v1:<key ID>:<wrapped data key>:<nonce + ciphertext>, base64url.
+	sealedPrefix = "v1"
+	// BlindIndexPrefix marks blind indexes, so they can be told apart from
+	// plaintext values stored before encryption was enabled.
+	BlindIndexPrefix = "bidx1:"
+)
+
+// Cipher seals and opens values and computes blind indexes.
+type Cipher struct {
+	keys     KeyProvider
+	indexKey []byte
+}
+
+// NewCipher returns a Cipher that wraps data keys with keys and computes
+// blind indexes with indexKey.
+func NewCipher(keys KeyProvider, indexKey []byte) *Cipher {
+	return &Cipher{keys: keys, indexKey: indexKey}
+}
+
+// CurrentKeyID returns the key that seals new values.
+func (c *Cipher) CurrentKeyID() string {
+	return c.keys.CurrentKeyID()
+}
+
+// BlindIndex returns a deterministic keyed hash of value (HMAC-SHA256), so
+// that equal values can be found without storing them. It reveals which
+// records share a value, nothing else.
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return BlindIndexPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Seal encrypts plaintext with a new data key wrapped by the current key.
+// additionalData (e.g. the record ID) is authenticated but not stored:
+// Open must be given the same value.
+func (c *Cipher) Seal(ctx context.Context, plaintext, additionalData []byte) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	keyID := c.keys.CurrentKeyID()
+	wrapped, err := c.keys.WrapKey(ctx, keyID, dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := sealAESGCM(dataKey, plaintext, additionalData)
+	if err != nil {
+		return "", err
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	return strings.Join([]string{sealedPrefix, keyID, encode(wrapped), encode(sealed)}, ":"), nil
+}
+
+// Open decrypts a value sealed by Seal with any key the provider holds.
+func (c *Cipher) Open(ctx context.Context, sealed string, additionalData []byte) ([]byte, error) {
+	keyID, wrapped, ciphertext, err := parseSealed(sealed)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := c.keys.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return openAESGCM(dataKey, ciphertext, additionalData)
+}
+
+// KeyID returns the key that wrapped the data key of a sealed value.
+func KeyID(sealed string) (string, error) {
+	keyID, _, _, err := parseSealed(sealed)
+	return keyID, err
+}
+
+func parseSealed(sealed string) (keyID string, wrapped, ciphertext []byte, err error) {
+	parts := strings.Split(sealed, ":")
+	if len(parts) != 4 || parts[0] != sealedPrefix {
+		return "", nil, nil, ErrMalformed
+	}
+	decode := base64.RawURLEncoding.DecodeString
+	if wrapped, err = decode(parts[2]); err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if ciphertext, err = decode(parts[3]); err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return parts[1], wrapped, ciphertext, nil
+}
@@ -0,0 +1,115 @@
+package fieldcrypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+// stubKeyFile makes LoadKeyFile read content.
+func stubKeyFile(t *testing.T, content string) {
+	orig := readFileFunc
+	readFileFunc = func(string) ([]byte, error) { return []byte(content), nil }
+	t.Cleanup(func() { readFileFunc = orig })
+}
+
+func mustLoadKeys(t *testing.T, content string) *LocalKeys {
+	t.Helper()
+	stubKeyFile(t, content)
+	keys, err := LoadKeyFile("keys.json")
+	if err != nil {
+		t.Fatalf("LoadKeyFile: %v", err)
+	}
+	return keys
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	keys := mustLoadKeys(t, `{"current":"k1","keys":{"k1":"`+testKey(1)+`"},"indexKey":"`+testKey(9)+`"}`)
+	cipher := NewCipher(keys, keys.IndexKey())
+
+	sealed, err := cipher.Seal(ctx, []byte("ñandú@example.com"), []byte("u-1"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if strings.Contains(sealed, "example") || !strings.HasPrefix(sealed, "v1:k1:") {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	if keyID, err := KeyID(sealed); err != nil || keyID != "k1" {
+		t.Fatalf("KeyID = %q, %v", keyID, err)
+	}
+	if plaintext, err := cipher.Open(ctx, sealed, []byte("u-1")); err != nil || string(plaintext) != "ñandú@example.com" {
+		t.Fatalf("Open = %q, %v", plaintext, err)
+	}
+	if _, err := cipher.Open(ctx, sealed, []byte("u-2")); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("Open with other additional data: %v, want ErrMalformed", err)
+	}
+	if _, err := cipher.Open(ctx, "v1:k1:%%:%%", []byte("u-1")); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("Open of garbage: %v, want ErrMalformed", err)
+	}
+	if again, _ := cipher.Seal(ctx, []byte("ñandú@example.com"), []byte("u-1")); again == sealed {
+		t.Fatal("Seal is deterministic")
+	}
+}
+
+func TestOpenWithRetiredAndUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+	old := mustLoadKeys(t, `{"current":"k1","keys":{"k1":"`+testKey(1)+`"},"indexKey":"`+testKey(9)+`"}`)
+	sealed, err := NewCipher(old, old.IndexKey()).Seal(ctx, []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	rotated := mustLoadKeys(t, `{"current":"k2","keys":{"k1":"`+testKey(1)+`","k2":"`+testKey(2)+`"},"indexKey":"`+testKey(9)+`"}`)
+	if plaintext, err := NewCipher(rotated, rotated.IndexKey()).Open(ctx, sealed, nil); err != nil || string(plaintext) != "secret" {
+		t.Fatalf("Open with retired key = %q, %v", plaintext, err)
+	}
+
+	retired := mustLoadKeys(t, `{"current":"k2","keys":{"k2":"`+testKey(2)+`"},"indexKey":"`+testKey(9)+`"}`)
+	if _, err := NewCipher(retired, retired.IndexKey()).Open(ctx, sealed, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Open after removing the key: %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	keys := mustLoadKeys(t, `{"current":"k1","keys":{"k1":"`+testKey(1)+`"},"indexKey":"`+testKey(9)+`"}`)
+	cipher := NewCipher(keys, keys.IndexKey())
+	index := cipher.BlindIndex("ana@example.com")
+	if !strings.HasPrefix(index, BlindIndexPrefix) || strings.Contains(index, "ana") {
+		t.Fatalf("BlindIndex = %q", index)
+	}
+	if cipher.BlindIndex("ana@example.com") != index {
+		t.Fatal("BlindIndex is not deterministic")
+	}
+	if cipher.BlindIndex("eva@example.com") == index {
+		t.Fatal("different values share a blind index")
+	}
+	if NewCipher(keys, bytes.Repeat([]byte{8}, 32)).BlindIndex("ana@example.com") == index {
+		t.Fatal("blind index does not depend on the index key")
+	}
+}
+
+func TestLoadKeyFileValidation(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":        `{`,
+		"missing current key": `{"current":"k2","keys":{"k1":"` + testKey(1) + `"},"indexKey":"` + testKey(9) + `"}`,
+		"short key":           `{"current":"k1","keys":{"k1":"c2hvcnQ="},"indexKey":"` + testKey(9) + `"}`,
+		"invalid key ID":      `{"current":"k:1","keys":{"k:1":"` + testKey(1) + `"},"indexKey":"` + testKey(9) + `"}`,
+		"missing index key":   `{"current":"k1","keys":{"k1":"` + testKey(1) + `"}}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			stubKeyFile(t, content)
+			if _, err := LoadKeyFile("keys.json"); err == nil {
+				t.Fatal("LoadKeyFile succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package fieldcrypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Injectable file I/O (swap in tests).
+var readFileFunc = os.ReadFile
+
+// ErrUnknownKey is returned for data wrapped with a key the provider does
+// not hold.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider wraps and unwraps the data keys of sealed values with key
+// encryption keys it holds. LocalKeys keeps them in a file; a KMS adapter
+// (Cloud KMS or AWS KMS Encrypt/Decrypt, with the key name as ID) satisfies
+// the same contract without the keys leaving the KMS.
+type KeyProvider interface {
+	// CurrentKeyID returns the key that wraps new data keys.
+	CurrentKeyID() string
+	// WrapKey encrypts a data key with the key keyID.
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key wrapped with the key keyID, returning an
+	// error wrapping ErrUnknownKey if the provider does not hold it.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// keyIDPattern keeps key IDs free of the separator of sealed values.
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// keyFile is the JSON layout of a local key file: 32-byte keys encoded in
+// standard base64. Retired keys stay listed so that values sealed with them
+// can be opened until they are re-encrypted.
+type keyFile struct {
+	Current  string            `json:"current"`
+	Keys     map[string]string `json:"keys"`
+	IndexKey string            `json:"indexKey"`
+}
+
+// LocalKeys is a KeyProvider backed by keys read from a local file.
+type LocalKeys struct {
+	current  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+// LoadKeyFile reads a key file:
+//
+//	{"current": "2026-10", "keys": {"2026-01": "<base64>", "2026-10": "<base64>"}, "indexKey": "<base64>"}
+//
+// Every key, including the blind index key, must be 32 bytes.
+func LoadKeyFile(path string) (*LocalKeys, error) {
+	data, err := readFileFunc(path)
+	if err != nil {
+		return nil, err
+	}
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("key file %s: %w", path, err)
+	}
+	keys := &LocalKeys{current: file.Current, keys: map[string][]byte{}}
+	for id, encoded := range file.Keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("key file %s: invalid key ID %q", path, id)
+		}
+		if keys.keys[id], err = decodeKey(encoded); err != nil {
+			return nil, fmt.Errorf("key file %s: key %s: %w", path, id, err)
+		}
+	}
+	if _, ok := keys.keys[file.Current]; !ok {
+		return nil, fmt.Errorf("key file %s: current key %q is not listed in keys", path, file.Current)
+	}
+	if keys.indexKey, err = decodeKey(file.IndexKey); err != nil {
+		return nil, fmt.Errorf("key file %s: indexKey: %w", path, err)
+	}
+	return keys, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("got %d bytes, want 32", len(key))
+	}
+	return key, nil
+}
+
+// IndexKey returns the key of the blind indexes.
+func (k *LocalKeys) IndexKey() []byte {
+	return k.indexKey
+}
+
+// CurrentKeyID returns the key that wraps new data keys.
+func (k *LocalKeys) CurrentKeyID() string {
+	return k.current
+}
+
+// WrapKey encrypts a data key with AES-256-GCM under the key keyID.
+func (k *LocalKeys) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return sealAESGCM(key, dataKey, []byte(keyID))
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey.
+func (k *LocalKeys) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return openAESGCM(key, wrapped, []byte(keyID))
+}
+
+// sealAESGCM encrypts plaintext with a random nonce, which it prepends to
+// the ciphertext.
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openAESGCM(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,338 @@
+// Package jsonpatch implements JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) over raw JSON documents.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types accepted by PATCH endpoints.
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// MaxOperations limits the number of operations in a single JSON Patch document.
+const MaxOperations = 100
+
+// Errors returned while applying patches. ErrTestFailed lets callers tell a
+// failed "test" operation apart from a malformed patch.
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrTestFailed   = errors.New("test operation failed")
+)
+
+// Operation is a single RFC 6902 operation.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to doc and returns the result.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, fmt.Errorf("%w: document: %v", ErrInvalidPatch, err)
+	}
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// ApplyPatch applies an RFC 6902 JSON Patch to doc and returns the result.
+// Operations are applied in order; if any fails, doc is left untouched and
+// the error identifies the failing operation.
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	var ops []Operation
+	if err := decode(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if len(ops) > MaxOperations {
+		return nil, fmt.Errorf("%w: more than %d operations", ErrInvalidPatch, MaxOperations)
+	}
+
+	var target interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, fmt.Errorf("%w: document: %v", ErrInvalidPatch, err)
+	}
+
+	for i, op := range ops {
+		var err error
+		target, err = applyOperation(target, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		var value interface{}
+		if err := decode(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := get(doc, path); err != nil {
+				return nil, err
+			}
+			if len(path) == 0 {
+				return value, nil
+			}
+			if doc, err = remove(doc, path); err != nil {
+				return nil, err
+			}
+			return add(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("%w: cannot move a value into its own child", ErrInvalidPath)
+			}
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+		return add(doc, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPath, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPath, token)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, token)
+		}
+	}
+	return current, nil
+}
+
+// add inserts value at path, returning the (possibly new) root.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		var index int
+		if last == "-" {
+			index = len(node)
+		} else if index, err = arrayIndex(last, len(node)); err != nil {
+			return nil, err
+		}
+		grown := append(node[:index:index], append([]interface{}{value}, node[index:]...)...)
+		return replaceAt(doc, path[:len(path)-1], grown)
+	default:
+		return nil, fmt.Errorf("%w: parent of %q is not a container", ErrInvalidPath, last)
+	}
+}
+
+// remove deletes the value at path, returning the (possibly new) root.
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the root", ErrInvalidPath)
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPath, last)
+		}
+		delete(node, last)
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		shrunk := append(node[:index:index], node[index+1:]...)
+		return replaceAt(doc, path[:len(path)-1], shrunk)
+	default:
+		return nil, fmt.Errorf("%w: parent of %q is not a container", ErrInvalidPath, last)
+	}
+}
+
+// replaceAt stores value at path. Needed for arrays, whose slice header
+// changes when elements are inserted or removed.
+func replaceAt(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+	return doc, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPath, token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max {
+		return 0, fmt.Errorf("%w: array index %q out of range", ErrInvalidPath, token)
+	}
+	return index, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = deepCopy(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = deepCopy(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// decode unmarshals JSON keeping numbers as json.Number so test comparisons
+// are exact.
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
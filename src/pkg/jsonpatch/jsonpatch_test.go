@@ -0,0 +1,93 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	cases := []struct {
+		name, doc, patch, want string
+	}{
+		{"replace field", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add field", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"remove field", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"replace array", `{"a":["b"]}`, `{"a":["c","d"]}`, `{"a":["c","d"]}`},
+		{"nested", `{"a":{"b":"c","d":"e"}}`, `{"a":{"d":null,"f":"g"}}`, `{"a":{"b":"c","f":"g"}}`},
+		{"non-object patch", `{"a":"b"}`, `["c"]`, `["c"]`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tc.doc), []byte(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	doc := `{"title":"x","tags":["go","api"],"meta":{"a/b":1,"c~d":2}}`
+	cases := []struct {
+		name, patch, want string
+	}{
+		{"replace", `[{"op":"replace","path":"/title","value":"y"}]`, `{"title":"y","tags":["go","api"],"meta":{"a/b":1,"c~d":2}}`},
+		{"append", `[{"op":"add","path":"/tags/-","value":"cloud"}]`, `{"title":"x","tags":["go","api","cloud"],"meta":{"a/b":1,"c~d":2}}`},
+		{"insert", `[{"op":"add","path":"/tags/0","value":"k8s"}]`, `{"title":"x","tags":["k8s","go","api"],"meta":{"a/b":1,"c~d":2}}`},
+		{"remove element", `[{"op":"remove","path":"/tags/0"}]`, `{"title":"x","tags":["api"],"meta":{"a/b":1,"c~d":2}}`},
+		{"escaped pointer", `[{"op":"remove","path":"/meta/a~1b"},{"op":"remove","path":"/meta/c~0d"}]`, `{"title":"x","tags":["go","api"],"meta":{}}`},
+		{"move", `[{"op":"move","from":"/title","path":"/summary"}]`, `{"summary":"x","tags":["go","api"],"meta":{"a/b":1,"c~d":2}}`},
+		{"copy", `[{"op":"copy","from":"/tags/1","path":"/tags/0"}]`, `{"title":"x","tags":["api","go","api"],"meta":{"a/b":1,"c~d":2}}`},
+		{"test then replace", `[{"op":"test","path":"/title","value":"x"},{"op":"replace","path":"/title","value":"z"}]`, `{"title":"z","tags":["go","api"],"meta":{"a/b":1,"c~d":2}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(doc), []byte(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	doc := []byte(`{"title":"x","tags":["go"]}`)
+	cases := []struct {
+		name, patch string
+		want        error
+	}{
+		{"failed test", `[{"op":"test","path":"/title","value":"y"}]`, ErrTestFailed},
+		{"replace missing", `[{"op":"replace","path":"/summary","value":"y"}]`, ErrInvalidPath},
+		{"remove out of range", `[{"op":"remove","path":"/tags/3"}]`, ErrInvalidPath},
+		{"leading zero index", `[{"op":"add","path":"/tags/01","value":"y"}]`, ErrInvalidPath},
+		{"bad pointer", `[{"op":"remove","path":"title"}]`, ErrInvalidPath},
+		{"unknown op", `[{"op":"frobnicate","path":"/title"}]`, ErrInvalidPatch},
+		{"missing value", `[{"op":"add","path":"/title"}]`, ErrInvalidPatch},
+		{"not an array", `{"op":"add"}`, ErrInvalidPatch},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ApplyPatch(doc, []byte(tc.patch)); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
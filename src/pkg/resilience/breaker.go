@@ -0,0 +1,148 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-yonathan/src/pkg/telemetry"
+)
+
+// ErrCircuitOpen is returned without calling the dependency while its
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Breaker states as reported in telemetry.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Injectable clock (swap in tests).
+var nowFunc = time.Now
+
+// BreakerConfig configures a Breaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive transient failures that
+	// opens the breaker; zero or less disables it.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker rejects calls before letting a
+	// single trial call through.
+	OpenTimeout time.Duration
+}
+
+// Breaker is a circuit breaker for one dependency. It opens after
+// FailureThreshold consecutive transient failures and rejects calls with
+// ErrCircuitOpen for OpenTimeout; then one trial call decides whether it
+// closes again or stays open for another OpenTimeout.
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial call is in flight
+}
+
+// breakers shares one breaker per dependency name, so every repository and
+// client of a dependency sees the same state.
+var breakers = struct {
+	mu     sync.Mutex
+	byName map[string]*Breaker
+}{byName: map[string]*Breaker{}}
+
+// BreakerFor returns the breaker of the named dependency, creating it with
+// cfg on first use.
+func BreakerFor(name string, cfg BreakerConfig) *Breaker {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+	if breaker, ok := breakers.byName[name]; ok {
+		return breaker
+	}
+	breaker := &Breaker{name: name, cfg: cfg, state: StateClosed}
+	breakers.byName[name] = breaker
+	telemetry.TrackBreakerState(name, StateClosed)
+	return breaker
+}
+
+// Name returns the dependency the breaker protects.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow reports whether a call may proceed. When it may not, it returns an
+// error wrapping ErrCircuitOpen and how long until the next trial.
+func (b *Breaker) allow() (time.Duration, error) {
+	if b == nil || b.cfg.FailureThreshold <= 0 {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		wait := b.openedAt.Add(b.cfg.OpenTimeout).Sub(nowFunc())
+		if wait > 0 {
+			return wait, fmt.Errorf("%w: %s", ErrCircuitOpen, b.name)
+		}
+		b.setState(StateHalfOpen)
+		b.trial = true
+		return 0, nil
+	case StateHalfOpen:
+		if b.trial {
+			return b.cfg.OpenTimeout, fmt.Errorf("%w: %s", ErrCircuitOpen, b.name)
+		}
+		b.trial = true
+	}
+	return 0, nil
+}
+
+// record settles a call allowed by allow. failed reports a transient
+// failure of the dependency; other outcomes count as success.
+func (b *Breaker) record(failed bool) {
+	if b == nil || b.cfg.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		if b.state != StateClosed {
+			b.setState(StateClosed)
+		}
+		return
+	}
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = nowFunc()
+		b.setState(StateOpen)
+	}
+}
+
+// release settles a call allowed by allow whose outcome says nothing about
+// the dependency, e.g. because the caller gave up.
+func (b *Breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
+
+// setState must be called with b.mu held.
+func (b *Breaker) setState(state string) {
+	b.state = state
+	telemetry.TrackBreakerState(b.name, state)
+}
@@ -0,0 +1,23 @@
+package resilience
+
+import "backend-yonathan/src/pkg/constants"
+
+// PolicyFromEnv returns the retry policy configured by RETRY_MAX_ATTEMPTS,
+// RETRY_BASE_DELAY_MS, RETRY_MAX_DELAY_MS and ATTEMPT_TIMEOUT_SECONDS.
+func PolicyFromEnv() Policy {
+	return Policy{
+		Attempts:       constants.RetryAttempts(),
+		BaseDelay:      constants.RetryBaseDelay(),
+		MaxDelay:       constants.RetryMaxDelay(),
+		AttemptTimeout: constants.AttemptTimeout(),
+	}
+}
+
+// BreakerFromEnv returns the breaker of the named dependency, configured on
+// first use by BREAKER_FAILURE_THRESHOLD and BREAKER_OPEN_SECONDS.
+func BreakerFromEnv(name string) *Breaker {
+	return BreakerFor(name, BreakerConfig{
+		FailureThreshold: constants.BreakerFailureThreshold(),
+		OpenTimeout:      constants.BreakerOpenTimeout(),
+	})
+}
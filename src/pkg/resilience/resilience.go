@@ -0,0 +1,206 @@
+// Package resilience wraps calls to cloud dependencies (Firestore,
+// DynamoDB, GCS) with retries for transient errors, exponential backoff with
+// jitter, per-attempt timeouts and per-dependency circuit breakers.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"backend-yonathan/src/pkg/telemetry"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Injectable jitter source (swap in tests). It returns a value in [0, n).
+var randFunc = rand.Int64N
+
+// Policy configures the retries of a call.
+type Policy struct {
+	// Attempts is the maximum number of calls, including the first one;
+	// values below 1 mean a single call.
+	Attempts int
+	// BaseDelay is the backoff before the second attempt; it doubles for
+	// every further attempt up to MaxDelay. The actual wait is a random
+	// duration up to that value (full jitter).
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// AttemptTimeout bounds each attempt; zero leaves only the deadline of
+	// the caller's context.
+	AttemptTimeout time.Duration
+	// Retryable classifies errors; nil uses IsTransient.
+	Retryable func(error) bool
+}
+
+// NoRetry returns p limited to a single attempt, for writes that are not
+// safe to repeat after an ambiguous failure.
+func (p Policy) NoRetry() Policy {
+	p.Attempts = 1
+	return p
+}
+
+func (p Policy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransient(err)
+}
+
+// backoff returns the wait before attempt+1.
+func (p Policy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < attempt && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(randFunc(int64(ceiling)) + 1)
+}
+
+// Do calls fn until it succeeds, fails with an error the policy does not
+// retry, runs out of attempts or ctx is done, waiting between attempts.
+// With a breaker, calls are rejected with an error wrapping ErrCircuitOpen
+// while it is open, and transient failures count towards opening it.
+func Do(ctx context.Context, breaker *Breaker, policy Policy, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		if retryAfter, err := breaker.allow(); err != nil {
+			telemetry.TrackBreakerRejection(breaker.name)
+			recordRejection(ctx, breaker.name, retryAfter)
+			return err
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.AttemptTimeout)
+		}
+		err := fn(attemptCtx)
+		cancel()
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up; that says nothing about the dependency.
+			breaker.release()
+			return err
+		}
+		transient := err != nil && policy.retryable(err)
+		breaker.record(transient)
+		if !transient || attempt >= policy.Attempts {
+			return err
+		}
+
+		if breaker != nil {
+			telemetry.TrackRetry(breaker.name)
+		}
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryableAPICodes are the AWS error codes (smithy APIError.ErrorCode) of
+// throttling and server-side failures.
+var retryableAPICodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"ThrottlingException":                    true,
+	"InternalServerError":                    true,
+	"ServiceUnavailable":                     true,
+	"TransactionInProgressException":         true,
+}
+
+// IsTransient reports whether err is a failure of the dependency that may
+// not happen again: timeouts, dropped connections, throttling and 5xx-like
+// errors from gRPC (Firestore), the AWS SDK (DynamoDB) and Google APIs
+// (GCS). Cancellation, open breakers and the repository sentinels are not
+// transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+	var awsErr interface{ ErrorCode() string }
+	if errors.As(err, &awsErr) {
+		return retryableAPICodes[awsErr.ErrorCode()]
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+			return true
+		}
+	}
+	return false
+}
+
+// --- Fast-fail tracking ---
+
+type rejectionsKey struct{}
+
+// Rejection describes a call rejected by an open breaker.
+type Rejection struct {
+	Dependency string
+	RetryAfter time.Duration
+}
+
+type rejections struct {
+	mu    sync.Mutex
+	first *Rejection
+}
+
+// WithRejections returns a context that records the calls made with it that
+// an open breaker rejects, for Rejected to report.
+func WithRejections(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rejectionsKey{}, &rejections{})
+}
+
+// Rejected returns the first call made with ctx that an open breaker
+// rejected, if any.
+func Rejected(ctx context.Context) (Rejection, bool) {
+	r, ok := ctx.Value(rejectionsKey{}).(*rejections)
+	if !ok {
+		return Rejection{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.first == nil {
+		return Rejection{}, false
+	}
+	return *r.first, true
+}
+
+func recordRejection(ctx context.Context, dependency string, retryAfter time.Duration) {
+	r, ok := ctx.Value(rejectionsKey{}).(*rejections)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.first == nil {
+		r.first = &Rejection{Dependency: dependency, RetryAfter: retryAfter}
+	}
+}
@@ -0,0 +1,168 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "backend unavailable")
+
+type apiError string
+
+func (e apiError) Error() string     { return string(e) }
+func (e apiError) ErrorCode() string { return string(e) }
+
+func fastPolicy(attempts int) Policy {
+	return Policy{Attempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("boom"), false},
+		{context.Canceled, false},
+		{fmt.Errorf("%w: firestore", ErrCircuitOpen), false},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("list: %w", errUnavailable), true},
+		{status.Error(codes.NotFound, "missing"), false},
+		{status.Error(codes.ResourceExhausted, "quota"), true},
+		{&googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{&googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{&googleapi.Error{Code: http.StatusForbidden}, false},
+		{apiError("ProvisionedThroughputExceededException"), true},
+		{apiError("ConditionalCheckFailedException"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDoRetriesTransientErrors(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), nil, fastPolicy(3), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errUnavailable
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	permanent := errors.New("invalid argument")
+	if err := Do(context.Background(), nil, fastPolicy(3), func(context.Context) error {
+		calls++
+		return permanent
+	}); !errors.Is(err, permanent) || calls != 1 {
+		t.Fatalf("Do = %v after %d calls, want the permanent error after 1", err, calls)
+	}
+
+	calls = 0
+	if err := Do(context.Background(), nil, fastPolicy(3).NoRetry(), func(context.Context) error {
+		calls++
+		return errUnavailable
+	}); !errors.Is(err, errUnavailable) || calls != 1 {
+		t.Fatalf("NoRetry: Do = %v after %d calls, want 1 call", err, calls)
+	}
+}
+
+func TestDoAppliesAttemptTimeout(t *testing.T) {
+	policy := fastPolicy(2)
+	policy.AttemptTimeout = 10 * time.Millisecond
+	calls := 0
+	err := Do(context.Background(), nil, policy, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done() // hung call
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("Do = %v after %d calls, want a retry after the attempt timeout", err, calls)
+	}
+}
+
+func TestDoStopsWhenCallerGivesUp(t *testing.T) {
+	breaker := BreakerFor(t.Name(), BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, breaker, fastPolicy(5), func(ctx context.Context) error {
+		calls++
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("Do = %v after %d calls, want Canceled after 1", err, calls)
+	}
+	if state := breaker.State(); state != StateClosed {
+		t.Fatalf("breaker state = %s, want closed: cancellations are not failures", state)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+
+	breaker := BreakerFor(t.Name(), BreakerConfig{FailureThreshold: 2, OpenTimeout: 30 * time.Second})
+	failing := func(context.Context) error { return errUnavailable }
+	for i := 0; i < 2; i++ {
+		_ = Do(context.Background(), breaker, fastPolicy(1), failing)
+	}
+	if state := breaker.State(); state != StateOpen {
+		t.Fatalf("breaker state = %s, want open after 2 transient failures", state)
+	}
+
+	ctx := WithRejections(context.Background())
+	calls := 0
+	err := Do(ctx, breaker, fastPolicy(3), func(context.Context) error { calls++; return nil })
+	if !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Fatalf("Do while open = %v after %d calls, want ErrCircuitOpen without calling", err, calls)
+	}
+	if rejection, ok := Rejected(ctx); !ok || rejection.Dependency != t.Name() || rejection.RetryAfter != 30*time.Second {
+		t.Fatalf("Rejected = %+v, %v", rejection, ok)
+	}
+
+	// After OpenTimeout a failed trial reopens the breaker...
+	now = now.Add(30 * time.Second)
+	if err := Do(context.Background(), breaker, fastPolicy(1), failing); !errors.Is(err, errUnavailable) {
+		t.Fatalf("trial call = %v, want the dependency error", err)
+	}
+	if state := breaker.State(); state != StateOpen {
+		t.Fatalf("breaker state = %s, want open after a failed trial", state)
+	}
+
+	// ...and a successful one closes it.
+	now = now.Add(30 * time.Second)
+	if err := Do(context.Background(), breaker, fastPolicy(1), func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("trial call = %v", err)
+	}
+	if state := breaker.State(); state != StateClosed {
+		t.Fatalf("breaker state = %s, want closed after a successful trial", state)
+	}
+}
+
+func TestBreakerIgnoresPermanentErrors(t *testing.T) {
+	breaker := BreakerFor(t.Name(), BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	_ = Do(context.Background(), breaker, fastPolicy(1), func(context.Context) error { return errors.New("not found") })
+	if state := breaker.State(); state != StateClosed {
+		t.Fatalf("breaker state = %s, want closed", state)
+	}
+}
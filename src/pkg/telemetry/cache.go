@@ -0,0 +1,52 @@
+package telemetry
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type cacheCounters struct {
+	hits   uint64
+	misses uint64
+}
+
+var caches sync.Map // name → *cacheCounters
+
+func countersFor(name string) *cacheCounters {
+	if counters, ok := caches.Load(name); ok {
+		return counters.(*cacheCounters)
+	}
+	counters, _ := caches.LoadOrStore(name, &cacheCounters{})
+	return counters.(*cacheCounters)
+}
+
+// TrackCacheHit counts a lookup served by the named cache.
+func TrackCacheHit(name string) {
+	atomic.AddUint64(&countersFor(name).hits, 1)
+}
+
+// TrackCacheMiss counts a lookup the named cache had to load.
+func TrackCacheMiss(name string) {
+	atomic.AddUint64(&countersFor(name).misses, 1)
+}
+
+// CacheStats returns hits, misses and hit rate per cache name.
+func CacheStats() map[string]interface{} {
+	stats := map[string]interface{}{}
+	caches.Range(func(key, value interface{}) bool {
+		counters := value.(*cacheCounters)
+		hits := atomic.LoadUint64(&counters.hits)
+		misses := atomic.LoadUint64(&counters.misses)
+		var hitRate float64
+		if total := hits + misses; total > 0 {
+			hitRate = float64(hits) / float64(total)
+		}
+		stats[key.(string)] = map[string]interface{}{
+			"hits":    hits,
+			"misses":  misses,
+			"hitRate": hitRate,
+		}
+		return true
+	})
+	return stats
+}
@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type dependencyCounters struct {
+	mu            sync.Mutex
+	state         string
+	changedAtUnix int64
+	retries       uint64
+	rejections    uint64
+}
+
+var dependencies sync.Map // name → *dependencyCounters
+
+func dependencyFor(name string) *dependencyCounters {
+	if counters, ok := dependencies.Load(name); ok {
+		return counters.(*dependencyCounters)
+	}
+	counters, _ := dependencies.LoadOrStore(name, &dependencyCounters{})
+	return counters.(*dependencyCounters)
+}
+
+// TrackBreakerState records the circuit breaker state of a dependency
+// ("closed", "open" or "half_open").
+func TrackBreakerState(name, state string) {
+	counters := dependencyFor(name)
+	counters.mu.Lock()
+	defer counters.mu.Unlock()
+	counters.state = state
+	counters.changedAtUnix = time.Now().UTC().Unix()
+}
+
+// TrackRetry counts a call to a dependency retried after a transient error.
+func TrackRetry(name string) {
+	atomic.AddUint64(&dependencyFor(name).retries, 1)
+}
+
+// TrackBreakerRejection counts a call rejected by an open breaker.
+func TrackBreakerRejection(name string) {
+	atomic.AddUint64(&dependencyFor(name).rejections, 1)
+}
+
+// DependencyStats returns breaker state, retries and rejections per
+// dependency name.
+func DependencyStats() map[string]interface{} {
+	stats := map[string]interface{}{}
+	dependencies.Range(func(key, value interface{}) bool {
+		counters := value.(*dependencyCounters)
+		counters.mu.Lock()
+		state, changedAt := counters.state, counters.changedAtUnix
+		counters.mu.Unlock()
+		stats[key.(string)] = map[string]interface{}{
+			"state":         state,
+			"changedAtUnix": changedAt,
+			"retries":       atomic.LoadUint64(&counters.retries),
+			"rejections":    atomic.LoadUint64(&counters.rejections),
+		}
+		return true
+	})
+	return stats
+}
+
+// dependenciesInState returns the names of the dependencies whose breaker
+// is in the given state, sorted.
+func dependenciesInState(stats map[string]interface{}, state string) []string {
+	names := []string{}
+	for name, value := range stats {
+		if value.(map[string]interface{})["state"] == state {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -3,6 +3,7 @@ package telemetry
 import (
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -72,6 +73,7 @@ func Snapshot() map[string]interface{} {
 		"errorRate":     errorRate,
 		"authFailRate":  authFailRate,
 		"window":        windowStats,
+		"caches":        CacheStats(),
 	}
 }
 
@@ -168,10 +170,25 @@ func Health() map[string]interface{} {
 		)
 	}
 
+	// An open breaker means requests to that dependency are failing fast.
+	dependencyStats := DependencyStats()
+	if open := dependenciesInState(dependencyStats, "open"); len(open) > 0 {
+		level = "critical"
+		recommendations = append(recommendations,
+			"Dependencias con circuito abierto (responden 503): "+strings.Join(open, ", "),
+		)
+	} else if halfOpen := dependenciesInState(dependencyStats, "half_open"); len(halfOpen) > 0 && level == "ok" {
+		level = "warn"
+		recommendations = append(recommendations,
+			"Dependencias recuperandose tras fallos: "+strings.Join(halfOpen, ", "),
+		)
+	}
+
 	return map[string]interface{}{
 		"status":          level,
 		"generatedAtUnix": time.Now().UTC().Unix(),
 		"alerts":          alerts,
+		"dependencies":    dependencyStats,
 		"recommendations": recommendations,
 	}
 }
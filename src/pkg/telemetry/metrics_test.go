@@ -142,3 +142,18 @@ func TestAlertsLevelsAndScopes(t *testing.T) {
 	_ = os.Unsetenv("OPS_CRITICAL_AUTH_FAIL_RATE")
 	_ = os.Unsetenv("OPS_HEALTH_HISTORY_LIMIT")
 }
+
+func TestHealthReportsOpenBreakers(t *testing.T) {
+	TrackBreakerState("test-dependency", "open")
+	TrackRetry("test-dependency")
+	t.Cleanup(func() { dependencies.Delete("test-dependency") })
+
+	health := Health()
+	if health["status"] != "critical" {
+		t.Fatalf("expected critical status with an open breaker, got %v", health["status"])
+	}
+	stats := health["dependencies"].(map[string]interface{})["test-dependency"].(map[string]interface{})
+	if stats["state"] != "open" || stats["retries"] != uint64(1) {
+		t.Fatalf("unexpected dependency stats: %v", stats)
+	}
+}
@@ -0,0 +1,264 @@
+// Package validation evaluates configurable content rules (required fields,
+// lengths, patterns, tags, images and body links) loaded from a JSON or YAML
+// file.
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Injectable file read (swap in tests).
+var readFileFunc = os.ReadFile
+
+// FieldRules constrains a single text field.
+type FieldRules struct {
+	Required  bool   `json:"required" yaml:"required"`
+	MinLength int    `json:"minLength" yaml:"minLength"`
+	MaxLength int    `json:"maxLength" yaml:"maxLength"`
+	Pattern   string `json:"pattern" yaml:"pattern"`
+	// PatternMessage replaces the default message when Pattern does not match.
+	PatternMessage string `json:"patternMessage" yaml:"patternMessage"`
+
+	pattern *regexp.Regexp
+}
+
+// LinkRules constrains the links found in the body.
+type LinkRules struct {
+	RequireHTTPS bool     `json:"requireHttps" yaml:"requireHttps"`
+	AllowedHosts []string `json:"allowedHosts" yaml:"allowedHosts"`
+	DeniedHosts  []string `json:"deniedHosts" yaml:"deniedHosts"`
+	MaxLinks     int      `json:"maxLinks" yaml:"maxLinks"`
+}
+
+// CollectionRules are the rules of one collection (e.g. "experiences").
+type CollectionRules struct {
+	Fields      map[string]*FieldRules `json:"fields" yaml:"fields"`
+	AllowedTags []string               `json:"allowedTags" yaml:"allowedTags"`
+	MinTags     int                    `json:"minTags" yaml:"minTags"`
+	MaxTags     int                    `json:"maxTags" yaml:"maxTags"`
+	MinImages   int                    `json:"minImages" yaml:"minImages"`
+	MaxImages   int                    `json:"maxImages" yaml:"maxImages"`
+	BodyLinks   *LinkRules             `json:"bodyLinks" yaml:"bodyLinks"`
+}
+
+// RuleSet maps collection names to their rules.
+type RuleSet struct {
+	Collections map[string]*CollectionRules `json:"collections" yaml:"collections"`
+}
+
+// Input is the content to validate. Fields holds plain-text values keyed by
+// field name; BodyHTML is scanned for links.
+type Input struct {
+	Fields    map[string]string
+	Tags      []string
+	ImageURLs []string
+	BodyHTML  string
+}
+
+// FieldError describes one failed rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`)
+
+// Parse decodes a rule set from JSON or YAML (chosen by format: "json",
+// "yaml" or "yml") and compiles its patterns.
+func Parse(data []byte, format string) (*RuleSet, error) {
+	rules := &RuleSet{}
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.Unmarshal(data, rules)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, rules)
+	default:
+		return nil, fmt.Errorf("unsupported rules format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.compile(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// LoadFile reads a rule set from path; the extension selects the format.
+func LoadFile(path string) (*RuleSet, error) {
+	data, err := readFileFunc(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+func (rs *RuleSet) compile() error {
+	for collection, rules := range rs.Collections {
+		if rules == nil {
+			continue
+		}
+		for field, fr := range rules.Fields {
+			if fr == nil || fr.Pattern == "" {
+				continue
+			}
+			compiled, err := regexp.Compile(fr.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s.%s: invalid pattern: %w", collection, field, err)
+			}
+			fr.pattern = compiled
+		}
+	}
+	return nil
+}
+
+// Validate evaluates the rules of collection against in. A nil rule set or
+// an unknown collection yields no errors. Errors are ordered by field name
+// within each rule group, so results are stable.
+func (rs *RuleSet) Validate(collection string, in Input) []FieldError {
+	errs := []FieldError{}
+	if rs == nil || rs.Collections[collection] == nil {
+		return errs
+	}
+	rules := rs.Collections[collection]
+
+	for _, field := range sortedKeys(rules.Fields) {
+		if fr := rules.Fields[field]; fr != nil {
+			errs = append(errs, fr.check(field, in.Fields[field])...)
+		}
+	}
+	errs = append(errs, rules.checkTags(in.Tags)...)
+	errs = append(errs, rules.checkImages(in.ImageURLs)...)
+	if rules.BodyLinks != nil {
+		errs = append(errs, rules.BodyLinks.check(in.BodyHTML)...)
+	}
+	return errs
+}
+
+func (fr *FieldRules) check(field, value string) []FieldError {
+	value = strings.TrimSpace(value)
+	length := utf8.RuneCountInString(value)
+	if value == "" {
+		if fr.Required {
+			return []FieldError{{field, "required", fmt.Sprintf("%s es requerido", field)}}
+		}
+		return nil
+	}
+
+	errs := []FieldError{}
+	if fr.MinLength > 0 && length < fr.MinLength {
+		errs = append(errs, FieldError{field, "minLength", fmt.Sprintf("%s debe tener al menos %d caracteres", field, fr.MinLength)})
+	}
+	if fr.MaxLength > 0 && length > fr.MaxLength {
+		errs = append(errs, FieldError{field, "maxLength", fmt.Sprintf("%s debe tener como maximo %d caracteres", field, fr.MaxLength)})
+	}
+	if fr.pattern != nil && !fr.pattern.MatchString(value) {
+		message := fr.PatternMessage
+		if message == "" {
+			message = fmt.Sprintf("%s no tiene el formato esperado", field)
+		}
+		errs = append(errs, FieldError{field, "pattern", message})
+	}
+	return errs
+}
+
+func (rules *CollectionRules) checkTags(tags []string) []FieldError {
+	errs := []FieldError{}
+	if rules.MinTags > 0 && len(tags) < rules.MinTags {
+		errs = append(errs, FieldError{"tags", "minTags", fmt.Sprintf("se requieren al menos %d tags", rules.MinTags)})
+	}
+	if rules.MaxTags > 0 && len(tags) > rules.MaxTags {
+		errs = append(errs, FieldError{"tags", "maxTags", fmt.Sprintf("se permiten como maximo %d tags", rules.MaxTags)})
+	}
+	if len(rules.AllowedTags) > 0 {
+		allowed := map[string]bool{}
+		for _, tag := range rules.AllowedTags {
+			allowed[strings.ToLower(tag)] = true
+		}
+		for _, tag := range tags {
+			if !allowed[strings.ToLower(tag)] {
+				errs = append(errs, FieldError{"tags", "allowedTags", fmt.Sprintf("el tag %q no esta permitido", tag)})
+			}
+		}
+	}
+	return errs
+}
+
+func (rules *CollectionRules) checkImages(images []string) []FieldError {
+	errs := []FieldError{}
+	if rules.MinImages > 0 && len(images) < rules.MinImages {
+		errs = append(errs, FieldError{"imageUrls", "minImages", fmt.Sprintf("se requieren al menos %d imagenes", rules.MinImages)})
+	}
+	if rules.MaxImages > 0 && len(images) > rules.MaxImages {
+		errs = append(errs, FieldError{"imageUrls", "maxImages", fmt.Sprintf("se permiten como maximo %d imagenes", rules.MaxImages)})
+	}
+	return errs
+}
+
+func (lr *LinkRules) check(body string) []FieldError {
+	errs := []FieldError{}
+	matches := hrefPattern.FindAllStringSubmatch(body, -1)
+	if lr.MaxLinks > 0 && len(matches) > lr.MaxLinks {
+		errs = append(errs, FieldError{"body", "maxLinks", fmt.Sprintf("el body tiene mas de %d enlaces", lr.MaxLinks)})
+	}
+	for _, match := range matches {
+		link := match[1]
+		if strings.HasPrefix(link, "#") || isLocalPath(link) || strings.HasPrefix(strings.ToLower(link), "mailto:") {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" {
+			errs = append(errs, FieldError{"body", "invalidLink", fmt.Sprintf("enlace invalido: %s", link)})
+			continue
+		}
+		if lr.RequireHTTPS && parsed.Scheme != "https" {
+			errs = append(errs, FieldError{"body", "requireHttps", fmt.Sprintf("el enlace debe usar https: %s", link)})
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if matchesHost(host, lr.DeniedHosts) {
+			errs = append(errs, FieldError{"body", "deniedHost", fmt.Sprintf("dominio no permitido: %s", host)})
+		} else if len(lr.AllowedHosts) > 0 && !matchesHost(host, lr.AllowedHosts) {
+			errs = append(errs, FieldError{"body", "allowedHosts", fmt.Sprintf("dominio no permitido: %s", host)})
+		}
+	}
+	return errs
+}
+
+// isLocalPath reports whether link is a path on the same site. Links that
+// start with "//" (or "/\", which browsers read the same way) are
+// protocol-relative URLs of another host, not paths.
+func isLocalPath(link string) bool {
+	return strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//") && !strings.HasPrefix(link, "/\\")
+}
+
+// matchesHost reports whether host equals or is a subdomain of any entry.
+func matchesHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]*FieldRules) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+const yamlRules = `
+collections:
+  experiences:
+    fields:
+      title:
+        required: true
+        minLength: 5
+        maxLength: 20
+      summary:
+        required: true
+        pattern: "^[A-Z]"
+        patternMessage: "summary debe empezar con mayuscula"
+    allowedTags: [go, react]
+    minTags: 1
+    minImages: 1
+    maxImages: 2
+    bodyLinks:
+      requireHttps: true
+      deniedHosts: [spam.example]
+      maxLinks: 2
+`
+
+func rules(errs []FieldError) map[string]bool {
+	found := map[string]bool{}
+	for _, e := range errs {
+		found[e.Field+"."+e.Rule] = true
+	}
+	return found
+}
+
+func TestParseYAMLAndValidate(t *testing.T) {
+	rs, err := Parse([]byte(yamlRules), "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	valid := Input{
+		Fields:    map[string]string{"title": "API en Go", "summary": "Backend"},
+		Tags:      []string{"go"},
+		ImageURLs: []string{"https://img.example/a.png"},
+		BodyHTML:  `<p>Ver <a href="https://github.com/x">repo</a> y <a href="#top">arriba</a></p>`,
+	}
+	if errs := rs.Validate("experiences", valid); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %+v", errs)
+	}
+
+	invalid := Input{
+		Fields:    map[string]string{"title": "API", "summary": "backend"},
+		Tags:      []string{"go", "php"},
+		ImageURLs: nil,
+		BodyHTML:  `<a href="http://ok.example">a</a><a href="https://www.spam.example">b</a><a href="https://c.example">c</a>`,
+	}
+	got := rules(rs.Validate("experiences", invalid))
+	for _, want := range []string{
+		"title.minLength", "summary.pattern", "tags.allowedTags", "imageUrls.minImages",
+		"body.maxLinks", "body.requireHttps", "body.deniedHost",
+	} {
+		if !got[want] {
+			t.Errorf("expected %s, got %v", want, got)
+		}
+	}
+}
+
+func TestValidateRequiredAndUnknownCollection(t *testing.T) {
+	rs, err := Parse([]byte(`{"collections":{"skills":{"fields":{"body":{"required":true}}}}}`), "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errs := rs.Validate("skills", Input{Fields: map[string]string{"body": "   "}})
+	if len(errs) != 1 || errs[0].Field != "body" || errs[0].Rule != "required" {
+		t.Fatalf("expected body required error, got %+v", errs)
+	}
+	if errs := rs.Validate("experiences", Input{}); len(errs) != 0 {
+		t.Fatalf("expected unknown collection to pass, got %+v", errs)
+	}
+	var nilRules *RuleSet
+	if errs := nilRules.Validate("skills", Input{}); len(errs) != 0 {
+		t.Fatalf("expected nil rule set to pass, got %+v", errs)
+	}
+}
+
+func TestAllowedHostsMatchSubdomains(t *testing.T) {
+	rs, _ := Parse([]byte(`{"collections":{"experiences":{"bodyLinks":{"allowedHosts":["github.com"]}}}}`), "json")
+	body := `<a href="https://gist.github.com/x">a</a><a href="https://evil.com/github.com">b</a><a href="/local">c</a>`
+	errs := rs.Validate("experiences", Input{BodyHTML: body})
+	if len(errs) != 1 || errs[0].Rule != "allowedHosts" {
+		t.Fatalf("expected only evil.com to be rejected, got %+v", errs)
+	}
+}
+
+func TestProtocolRelativeLinksAreCheckedAsURLs(t *testing.T) {
+	rs, _ := Parse([]byte(`{"collections":{"experiences":{"bodyLinks":{"requireHttps":true,"deniedHosts":["evil.example"]}}}}`), "json")
+	body := `<a href="//evil.example/x">a</a><a href="/local">b</a>`
+	found := rules(rs.Validate("experiences", Input{BodyHTML: body}))
+	if !found["body.deniedHost"] || !found["body.requireHttps"] {
+		t.Fatalf("expected //evil.example to be checked like an absolute URL, got %v", found)
+	}
+
+	rs, _ = Parse([]byte(`{"collections":{"experiences":{"bodyLinks":{"allowedHosts":["github.com"]}}}}`), "json")
+	if errs := rs.Validate("experiences", Input{BodyHTML: `<a href="//evil.example/x">a</a>`}); len(errs) != 1 || errs[0].Rule != "allowedHosts" {
+		t.Fatalf("expected //evil.example outside allowedHosts, got %+v", errs)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte(`{}`), "toml"); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+	if _, err := Parse([]byte(`{"collections":{"experiences":{"fields":{"title":{"pattern":"("}}}}}`), "json"); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+}
+
+func TestLoadFileSelectsFormatByExtension(t *testing.T) {
+	original := readFileFunc
+	defer func() { readFileFunc = original }()
+
+	readFileFunc = func(path string) ([]byte, error) {
+		if path == "rules.yml" {
+			return []byte(yamlRules), nil
+		}
+		return nil, errors.New("not found")
+	}
+	rs, err := LoadFile("rules.yml")
+	if err != nil || rs.Collections["experiences"] == nil {
+		t.Fatalf("expected rules to load, got %+v, %v", rs, err)
+	}
+	if _, err := LoadFile("missing.json"); err == nil {
+		t.Fatal("expected read error")
+	}
+}
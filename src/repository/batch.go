@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+)
+
+// WriteKind identifies the type of a batched experience write.
+type WriteKind int
+
+const (
+	WriteCreate WriteKind = iota
+	WriteUpdate
+	WriteDelete
+)
+
+// ExperienceWrite is a single write of a batch. Updates and deletes only
+// apply if the stored Version equals ExpectedVersion; updates store the
+// experience with Version ExpectedVersion+1. Deletes only use Experience.ID.
+type ExperienceWrite struct {
+	Kind            WriteKind
+	Experience      models.Experience
+	ExpectedVersion int64
+}
+
+// BatchExperienceRepository is implemented by experience backends that can
+// apply several writes atomically: either every write succeeds or none is
+// persisted.
+type BatchExperienceRepository interface {
+	ApplyBatch(ctx context.Context, writes []ExperienceWrite) error
+}
+
+// CheckBatch validates writes in order against the current version of each
+// stored experience, so several writes may target the same ID. It returns an
+// error wrapping ErrNotFound or ErrVersionConflict for the first write that
+// cannot apply. versions is not modified.
+func CheckBatch(versions map[string]int64, writes []ExperienceWrite) error {
+	state := make(map[string]int64, len(versions))
+	for id, version := range versions {
+		state[id] = version
+	}
+	for i, w := range writes {
+		id := w.Experience.ID
+		current, exists := state[id]
+		switch w.Kind {
+		case WriteCreate:
+			if exists {
+				return fmt.Errorf("%w: write %d: experience %s already exists", ErrVersionConflict, i, id)
+			}
+			state[id] = w.Experience.Version
+		case WriteUpdate, WriteDelete:
+			if !exists {
+				return fmt.Errorf("%w: write %d: experience %s", ErrNotFound, i, id)
+			}
+			if current != w.ExpectedVersion {
+				return fmt.Errorf("%w: write %d: experience %s", ErrVersionConflict, i, id)
+			}
+			if w.Kind == WriteDelete {
+				delete(state, id)
+			} else {
+				state[id] = w.ExpectedVersion + 1
+			}
+		default:
+			return fmt.Errorf("unknown write kind %d", w.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package cachedrepo provides read-through caching decorators for
+// repositories whose reads are expensive, such as full Firestore scans.
+package cachedrepo
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/telemetry"
+	"backend-yonathan/src/repository"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Injectable clock (swap in tests).
+var nowFunc = time.Now
+
+// Options configures an ExperienceRepository cache.
+type Options struct {
+	// Name identifies the cache in telemetry.
+	Name string
+	// TTL bounds how long a read is served without asking the wrapped
+	// repository; it is the staleness limit for writes made elsewhere.
+	TTL time.Duration
+	// MaxItems bounds the experiences cached by ID; the least recently used
+	// entry is evicted first.
+	MaxItems int
+	// LoadTimeout bounds a shared load. Loads outlive the caller that
+	// started them, so they do not inherit its deadline; zero means no bound.
+	LoadTimeout time.Duration
+}
+
+type listEntry struct {
+	experiences []models.Experience
+	expires     time.Time
+}
+
+type itemEntry struct {
+	id         string
+	experience models.Experience
+	expires    time.Time
+}
+
+// ExperienceRepository serves List and GetByID from memory and forwards
+// writes to the wrapped repository, dropping the cache after each one.
+// Concurrent misses for the same key share a single load.
+type ExperienceRepository struct {
+	inner repository.ExperienceRepository
+	opts  Options
+	group singleflight.Group
+
+	mu sync.Mutex
+	// generation changes on every invalidation. Loads are shared only
+	// between callers of the same generation, and loads that started before
+	// it changed are returned to their callers but not cached.
+	generation uint64
+	list       *listEntry
+	items      map[string]*list.Element
+	lru        *list.List
+}
+
+// batchExperienceRepository adds ApplyBatch when the wrapped repository
+// supports atomic batches.
+type batchExperienceRepository struct {
+	*ExperienceRepository
+	batcher repository.BatchExperienceRepository
+}
+
+// NewExperienceRepository wraps inner with a cache. The result implements
+// repository.BatchExperienceRepository exactly when inner does.
+func NewExperienceRepository(inner repository.ExperienceRepository, opts Options) repository.ExperienceRepository {
+	if opts.Name == "" {
+		opts.Name = "experiences"
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = 1
+	}
+	cached := &ExperienceRepository{
+		inner: inner,
+		opts:  opts,
+		items: map[string]*list.Element{},
+		lru:   list.New(),
+	}
+	if batcher, ok := inner.(repository.BatchExperienceRepository); ok {
+		return &batchExperienceRepository{ExperienceRepository: cached, batcher: batcher}
+	}
+	return cached
+}
+
+// cloneExperience copies exp including its slices, so callers cannot
+// modify cached values.
+func cloneExperience(exp models.Experience) models.Experience {
+	exp.ImageURLs = append([]string(nil), exp.ImageURLs...)
+	exp.Tags = append([]string(nil), exp.Tags...)
+	exp.RelatedIDs = append([]string(nil), exp.RelatedIDs...)
+	return exp
+}
+
+func cloneExperiences(experiences []models.Experience) []models.Experience {
+	clone := make([]models.Experience, len(experiences))
+	for i, exp := range experiences {
+		clone[i] = cloneExperience(exp)
+	}
+	return clone
+}
+
+// List returns all experiences, from the cache while it is fresh.
+func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
+	r.mu.Lock()
+	if r.list != nil && nowFunc().Before(r.list.expires) {
+		experiences := cloneExperiences(r.list.experiences)
+		r.mu.Unlock()
+		telemetry.TrackCacheHit(r.opts.Name)
+		return experiences, nil
+	}
+	generation := r.generation
+	r.mu.Unlock()
+	telemetry.TrackCacheMiss(r.opts.Name)
+
+	result, err := r.load(ctx, fmt.Sprintf("list@%d", generation), func(ctx context.Context) (interface{}, error) {
+		experiences, err := r.inner.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		if r.generation == generation {
+			r.list = &listEntry{experiences: cloneExperiences(experiences), expires: nowFunc().Add(r.opts.TTL)}
+		}
+		r.mu.Unlock()
+		return experiences, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneExperiences(result.([]models.Experience)), nil
+}
+
+// load runs fn once for all concurrent callers of key. fn gets a context
+// that is not cancelled with the caller's, so a caller that gives up does
+// not fail the others; each caller still stops waiting when its ctx is done.
+func (r *ExperienceRepository) load(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	results := r.group.DoChan(key, func() (interface{}, error) {
+		shared, cancel := context.WithoutCancel(ctx), context.CancelFunc(func() {})
+		if r.opts.LoadTimeout > 0 {
+			shared, cancel = context.WithTimeout(shared, r.opts.LoadTimeout)
+		}
+		defer cancel()
+		return fn(shared)
+	})
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cachedItem returns a fresh experience from the ID cache or the list cache.
+// It must be called with r.mu held.
+func (r *ExperienceRepository) cachedItem(id string) (models.Experience, bool) {
+	now := nowFunc()
+	if element, ok := r.items[id]; ok {
+		entry := element.Value.(*itemEntry)
+		if now.Before(entry.expires) {
+			r.lru.MoveToFront(element)
+			return entry.experience, true
+		}
+		r.lru.Remove(element)
+		delete(r.items, id)
+	}
+	if r.list != nil && now.Before(r.list.expires) {
+		for _, exp := range r.list.experiences {
+			if exp.ID == id {
+				return exp, true
+			}
+		}
+	}
+	return models.Experience{}, false
+}
+
+// storeItem caches exp by ID, evicting the least recently used entries
+// beyond MaxItems. It must be called with r.mu held.
+func (r *ExperienceRepository) storeItem(exp models.Experience) {
+	entry := &itemEntry{id: exp.ID, experience: cloneExperience(exp), expires: nowFunc().Add(r.opts.TTL)}
+	if element, ok := r.items[exp.ID]; ok {
+		element.Value = entry
+		r.lru.MoveToFront(element)
+		return
+	}
+	r.items[exp.ID] = r.lru.PushFront(entry)
+	for r.lru.Len() > r.opts.MaxItems {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.items, oldest.Value.(*itemEntry).id)
+	}
+}
+
+// GetByID returns an experience, from the cache while it is fresh. Missing
+// IDs are not cached.
+func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	r.mu.Lock()
+	if exp, ok := r.cachedItem(id); ok {
+		exp = cloneExperience(exp)
+		r.mu.Unlock()
+		telemetry.TrackCacheHit(r.opts.Name)
+		return exp, nil
+	}
+	generation := r.generation
+	r.mu.Unlock()
+	telemetry.TrackCacheMiss(r.opts.Name)
+
+	result, err := r.load(ctx, fmt.Sprintf("id@%d:%s", generation, id), func(ctx context.Context) (interface{}, error) {
+		exp, err := r.inner.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		if r.generation == generation {
+			r.storeItem(exp)
+		}
+		r.mu.Unlock()
+		return exp, nil
+	})
+	if err != nil {
+		return models.Experience{}, err
+	}
+	return cloneExperience(result.(models.Experience)), nil
+}
+
+// Invalidate drops every cached read.
+func (r *ExperienceRepository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.list = nil
+	r.items = map[string]*list.Element{}
+	r.lru.Init()
+}
+
+// Create stores a new experience and invalidates the cache.
+func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
+	defer r.Invalidate()
+	return r.inner.Create(ctx, exp)
+}
+
+// Update replaces an experience and invalidates the cache. A failed write is
+// also followed by an invalidation, since a version conflict means the cache
+// is behind the store.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	defer r.Invalidate()
+	return r.inner.Update(ctx, exp, expectedVersion)
+}
+
+// Delete removes an experience and invalidates the cache.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	defer r.Invalidate()
+	return r.inner.Delete(ctx, id, expectedVersion)
+}
+
+// UpdatePositions forwards the position updates and invalidates the cache.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	defer r.Invalidate()
+	return r.inner.UpdatePositions(ctx, updates)
+}
+
+// ApplyBatch forwards the batch and invalidates the cache.
+func (r *batchExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	defer r.Invalidate()
+	return r.batcher.ApplyBatch(ctx, writes)
+}
@@ -0,0 +1,263 @@
+package cachedrepo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/telemetry"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+	"backend-yonathan/src/repository/repotest"
+)
+
+// countingRepo is an in-memory experience repository that counts reads and
+// can block List until released.
+type countingRepo struct {
+	mu          sync.Mutex
+	experiences map[string]models.Experience
+	lists       int32
+	gets        int32
+	release     chan struct{}
+}
+
+func newCountingRepo(ids ...string) *countingRepo {
+	repo := &countingRepo{experiences: map[string]models.Experience{}}
+	for _, id := range ids {
+		repo.experiences[id] = models.Experience{ID: id, Title: "Titulo " + id, Tags: []string{"go"}, Version: 1}
+	}
+	return repo
+}
+
+func (c *countingRepo) List(ctx context.Context) ([]models.Experience, error) {
+	atomic.AddInt32(&c.lists, 1)
+	if c.release != nil {
+		<-c.release
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := []models.Experience{}
+	for _, exp := range c.experiences {
+		result = append(result, exp)
+	}
+	return result, nil
+}
+
+func (c *countingRepo) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	atomic.AddInt32(&c.gets, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exp, ok := c.experiences[id]
+	if !ok {
+		return models.Experience{}, fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	}
+	return exp, nil
+}
+
+func (c *countingRepo) Create(ctx context.Context, exp models.Experience) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.experiences[exp.ID] = exp
+	return nil
+}
+
+func (c *countingRepo) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	return c.Create(ctx, exp)
+}
+
+func (c *countingRepo) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.experiences, id)
+	return nil
+}
+
+func (c *countingRepo) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	return nil
+}
+
+type batchCountingRepo struct {
+	*countingRepo
+	batches int
+}
+
+func (b *batchCountingRepo) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	b.batches++
+	return nil
+}
+
+func TestListAndGetByIDAreServedFromCache(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo("a", "b")
+	name := fmt.Sprintf("test-hits-%p", inner)
+	repo := NewExperienceRepository(inner, Options{Name: name, TTL: time.Minute, MaxItems: 10})
+
+	for i := 0; i < 3; i++ {
+		if list, err := repo.List(ctx); err != nil || len(list) != 2 {
+			t.Fatalf("List = %v, %v", list, err)
+		}
+		if _, err := repo.GetByID(ctx, "a"); err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+	}
+	if inner.lists != 1 || inner.gets != 0 {
+		t.Fatalf("inner reads: lists=%d gets=%d, want 1 and 0", inner.lists, inner.gets)
+	}
+
+	stats := telemetry.CacheStats()[name].(map[string]interface{})
+	if stats["hits"].(uint64) != 5 || stats["misses"].(uint64) != 1 {
+		t.Fatalf("cache stats = %v", stats)
+	}
+
+	// Returned values are copies.
+	list, _ := repo.List(ctx)
+	list[0].Tags[0] = "mutated"
+	again, _ := repo.List(ctx)
+	for _, exp := range again {
+		if exp.Tags[0] == "mutated" {
+			t.Fatal("cached experience was modified through a returned value")
+		}
+	}
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo("a")
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute, MaxItems: 10})
+
+	if _, err := repo.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Create(ctx, models.Experience{ID: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := repo.List(ctx); len(list) != 2 {
+		t.Fatalf("List after Create has %d items, want 2", len(list))
+	}
+	if err := repo.Delete(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(ctx, "a"); err == nil {
+		t.Fatal("GetByID served a deleted experience")
+	}
+}
+
+func TestEntriesExpireAfterTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := nowFunc
+	defer func() { nowFunc = original }()
+	nowFunc = func() time.Time { return now }
+
+	ctx := context.Background()
+	inner := newCountingRepo("a")
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute, MaxItems: 10})
+	_, _ = repo.GetByID(ctx, "a")
+	_, _ = repo.GetByID(ctx, "a")
+	now = now.Add(2 * time.Minute)
+	_, _ = repo.GetByID(ctx, "a")
+	if inner.gets != 2 {
+		t.Fatalf("inner gets = %d, want 2", inner.gets)
+	}
+}
+
+func TestItemCacheIsBounded(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo("a", "b", "c")
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute, MaxItems: 2}).(*ExperienceRepository)
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := repo.GetByID(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.lru.Len() != 2 {
+		t.Fatalf("cached %d items, want 2", repo.lru.Len())
+	}
+	_, _ = repo.GetByID(ctx, "a")
+	if inner.gets != 4 {
+		t.Fatalf("inner gets = %d, want 4 after evicting the oldest entry", inner.gets)
+	}
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo("a")
+	inner.release = make(chan struct{})
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute, MaxItems: 10})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if list, err := repo.List(ctx); err != nil || len(list) != 1 {
+				t.Errorf("List = %v, %v", list, err)
+			}
+		}()
+	}
+	// Let the goroutines pile up on the in-flight load.
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+	if inner.lists != 1 {
+		t.Fatalf("inner lists = %d, want 1", inner.lists)
+	}
+}
+
+func TestCancelledCallerDoesNotFailSharedLoad(t *testing.T) {
+	inner := newCountingRepo("a")
+	inner.release = make(chan struct{})
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute, MaxItems: 10})
+
+	impatient, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.List(impatient)
+		done <- err
+	}()
+	patient := make(chan []models.Experience, 1)
+	go func() {
+		list, _ := repo.List(context.Background())
+		patient <- list
+	}()
+
+	if err := <-done; err != context.DeadlineExceeded {
+		t.Fatalf("impatient List error = %v, want context.DeadlineExceeded", err)
+	}
+	close(inner.release)
+	if list := <-patient; len(list) != 1 {
+		t.Fatalf("patient List = %v, want the shared load result", list)
+	}
+}
+
+func TestBatchSupportFollowsInner(t *testing.T) {
+	if _, ok := NewExperienceRepository(newCountingRepo(), Options{TTL: time.Minute}).(repository.BatchExperienceRepository); ok {
+		t.Fatal("cache should not offer batches when the inner repository does not")
+	}
+
+	ctx := context.Background()
+	inner := &batchCountingRepo{countingRepo: newCountingRepo("a")}
+	repo := NewExperienceRepository(inner, Options{TTL: time.Minute})
+	batcher, ok := repo.(repository.BatchExperienceRepository)
+	if !ok {
+		t.Fatal("cache should offer batches when the inner repository does")
+	}
+	_, _ = repo.List(ctx)
+	if err := batcher.ApplyBatch(ctx, nil); err != nil || inner.batches != 1 {
+		t.Fatalf("ApplyBatch = %v, batches=%d", err, inner.batches)
+	}
+	_, _ = repo.List(ctx)
+	if inner.lists != 2 {
+		t.Fatalf("ApplyBatch did not invalidate: lists=%d", inner.lists)
+	}
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return NewExperienceRepository(memory.NewExperienceRepository(), Options{TTL: time.Minute, MaxItems: 10})
+	})
+}
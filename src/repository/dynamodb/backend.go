@@ -0,0 +1,65 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"backend-yonathan/src/config"
+	"backend-yonathan/src/repository"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// Injectable client constructor (swap in tests).
+var newClientFunc = config.NewDynamoDBClient
+
+// clients shares one DynamoDB client per region across the stores of a
+// configuration.
+var clients = struct {
+	mu       sync.Mutex
+	byRegion map[string]*dynamodb.Client
+}{byRegion: map[string]*dynamodb.Client{}}
+
+// clientFor returns the client of the DSN region (?region=us-east-1), or of
+// AWS_REGION when the DSN has none.
+func clientFor(ctx context.Context, dsn repository.DSN) (*dynamodb.Client, error) {
+	region := dsn.Params.Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+	if client, ok := clients.byRegion[region]; ok {
+		return client, nil
+	}
+	client, err := newClientFunc(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	clients.byRegion[region] = client
+	return client, nil
+}
+
+// The dynamodb backend provides users and experiences:
+// dynamodb://<table>?region=<region>. The table defaults to DYNAMO_DB_TABLE
+// (users) or DYNAMO_DB_EXPERIENCES_TABLE, the region to AWS_REGION.
+func init() {
+	repository.Register("dynamodb", repository.Backend{
+		Users: func(ctx context.Context, dsn repository.DSN) (repository.UserRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return &UserRepository{client: client, table: dsn.Location}, nil
+		},
+		Experiences: func(ctx context.Context, dsn repository.DSN) (repository.ExperienceRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return &ExperienceRepository{client: client, table: dsn.Location}, nil
+		},
+	})
+}
@@ -0,0 +1,339 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Injectable DynamoDB operation vars used by ExperienceRepository (swap in tests).
+var (
+	scanFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		return client.Scan(ctx, input)
+	}
+	deleteItemFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+		return client.DeleteItem(ctx, input)
+	}
+	transactWriteItemsFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+		return client.TransactWriteItems(ctx, input)
+	}
+)
+
+// Condition expressions. Items are keyed by the "ID" attribute and carry the
+// optimistic-concurrency counter in "Version".
+const (
+	conditionExists        = "attribute_exists(ID)"
+	conditionNotExists     = "attribute_not_exists(ID)"
+	conditionVersionEquals = "attribute_exists(ID) AND Version = :expected"
+)
+
+// ExperienceRepository is the DynamoDB implementation of repository.ExperienceRepository.
+// Items are stored with the Go field names of models.Experience as attributes.
+type ExperienceRepository struct {
+	client *dynamodb.Client
+	table  string
+}
+
+// NewExperienceRepository creates a new DynamoDB-backed ExperienceRepository.
+func NewExperienceRepository(client *dynamodb.Client) *ExperienceRepository {
+	return &ExperienceRepository{client: client}
+}
+
+// tableName returns the table from the DSN, or DYNAMO_DB_EXPERIENCES_TABLE.
+func (r *ExperienceRepository) tableName() string {
+	if r.table != "" {
+		return r.table
+	}
+	return constants.ExperiencesTableName()
+}
+
+func experienceKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func versionValue(version int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":expected": &types.AttributeValueMemberN{Value: fmt.Sprint(version)},
+	}
+}
+
+// conditionFailure maps a failed conditional write on id to ErrNotFound when
+// the item is missing and to ErrVersionConflict otherwise. The write must
+// request ALL_OLD values on condition check failure.
+func conditionFailure(err error, id string) error {
+	var failed *types.ConditionalCheckFailedException
+	if !errors.As(err, &failed) {
+		return err
+	}
+	if len(failed.Item) == 0 {
+		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	}
+	return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, id)
+}
+
+// List returns all experiences, ordered by creation date and ID.
+func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
+	experiences := make([]models.Experience, 0)
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName()), ConsistentRead: aws.Bool(true)}
+	for {
+		result, err := scanFunc(ctx, r.client, input)
+		if err != nil {
+			return nil, err
+		}
+		page := make([]models.Experience, 0, len(result.Items))
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &page); err != nil {
+			return nil, err
+		}
+		experiences = append(experiences, page...)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	repository.SortExperiences(experiences)
+	return experiences, nil
+}
+
+// GetByID returns an experience by ID.
+func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	var exp models.Experience
+	result, err := getItemFunc(ctx, r.client, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName()),
+		Key:            experienceKey(id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return exp, err
+	}
+	if result.Item == nil {
+		return exp, fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	}
+	err = attributevalue.UnmarshalMap(result.Item, &exp)
+	return exp, err
+}
+
+// Create stores a new experience. It fails with ErrVersionConflict if the ID
+// is already taken.
+func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
+	item, err := attributevalue.MarshalMap(exp)
+	if err != nil {
+		return err
+	}
+	_, err = putItemFunc(ctx, r.client, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName()),
+		Item:                item,
+		ConditionExpression: aws.String(conditionNotExists),
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return fmt.Errorf("%w: experience %s already exists", repository.ErrVersionConflict, exp.ID)
+	}
+	return err
+}
+
+// Update replaces an experience with a conditional put on its version.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	exp.Version = expectedVersion + 1
+	item, err := attributevalue.MarshalMap(exp)
+	if err != nil {
+		return err
+	}
+	_, err = putItemFunc(ctx, r.client, &dynamodb.PutItemInput{
+		TableName:                           aws.String(r.tableName()),
+		Item:                                item,
+		ConditionExpression:                 aws.String(conditionVersionEquals),
+		ExpressionAttributeValues:           versionValue(expectedVersion),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	return conditionFailure(err, exp.ID)
+}
+
+// Delete removes an experience with a conditional delete on its version.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	_, err := deleteItemFunc(ctx, r.client, &dynamodb.DeleteItemInput{
+		TableName:                           aws.String(r.tableName()),
+		Key:                                 experienceKey(id),
+		ConditionExpression:                 aws.String(conditionVersionEquals),
+		ExpressionAttributeValues:           versionValue(expectedVersion),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	return conditionFailure(err, id)
+}
+
+// transact runs items in a single TransactWriteItems call. When a condition
+// fails it returns the index of the first failing item.
+func (r *ExperienceRepository) transact(ctx context.Context, items []types.TransactWriteItem) (int, error) {
+	if len(items) == 0 {
+		return -1, nil
+	}
+	if len(items) > constants.DynamoDBMaxTransactItems {
+		return -1, fmt.Errorf("transaction has %d items; DynamoDB allows %d", len(items), constants.DynamoDBMaxTransactItems)
+	}
+	_, err := transactWriteItemsFunc(ctx, r.client, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for i, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return i, err
+			}
+		}
+	}
+	return -1, err
+}
+
+// transactChunks runs items in TransactWriteItems calls of at most
+// constants.DynamoDBMaxTransactItems. Each call is atomic, but a failing call
+// leaves the earlier ones applied. It returns the index of the first item
+// whose condition failed (or -1) and how many items were committed.
+func (r *ExperienceRepository) transactChunks(ctx context.Context, items []types.TransactWriteItem) (int, int, error) {
+	for start := 0; start < len(items); start += constants.DynamoDBMaxTransactItems {
+		end := min(start+constants.DynamoDBMaxTransactItems, len(items))
+		index, err := r.transact(ctx, items[start:end])
+		if err != nil {
+			if index >= 0 {
+				index += start
+			}
+			return index, start, err
+		}
+	}
+	return -1, len(items), nil
+}
+
+// UpdatePositions applies the position updates in one transaction, so a
+// missing ID leaves every item untouched. Above
+// constants.DynamoDBMaxTransactItems updates it first checks that every ID
+// exists and then writes one transaction per chunk: a concurrent delete can
+// still leave the earlier chunks applied.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	if len(updates) > constants.DynamoDBMaxTransactItems {
+		for _, u := range updates {
+			if _, err := r.GetByID(ctx, u.ID); err != nil {
+				return err
+			}
+		}
+	}
+	items := make([]types.TransactWriteItem, 0, len(updates))
+	for _, u := range updates {
+		expression := "SET #position = :position, Version = Version + :one"
+		values := map[string]types.AttributeValue{
+			":position": &types.AttributeValueMemberN{Value: fmt.Sprint(u.Position)},
+			":one":      &types.AttributeValueMemberN{Value: "1"},
+		}
+		if u.Pinned != nil {
+			expression += ", Pinned = :pinned"
+			values[":pinned"] = &types.AttributeValueMemberBOOL{Value: *u.Pinned}
+		}
+		items = append(items, types.TransactWriteItem{Update: &types.Update{
+			TableName:                 aws.String(r.tableName()),
+			Key:                       experienceKey(u.ID),
+			UpdateExpression:          aws.String(expression),
+			ConditionExpression:       aws.String(conditionExists),
+			ExpressionAttributeNames:  map[string]string{"#position": "Position"},
+			ExpressionAttributeValues: values,
+		}})
+	}
+
+	failed, _, err := r.transactChunks(ctx, items)
+	if failed >= 0 {
+		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, updates[failed].ID)
+	}
+	return err
+}
+
+// ApplyBatch reads the current version of every target, validates the writes
+// with repository.CheckBatch and commits the final state of each item in one
+// transaction conditioned on the versions read, so either all writes apply or
+// none does. DynamoDB cannot do this atomically for more than
+// constants.DynamoDBMaxTransactItems items: larger batches are committed one
+// transaction per chunk, and a concurrent change detected in a later chunk
+// leaves the earlier chunks applied; the error then says how many items were
+// written.
+func (r *ExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	ids := make([]string, 0, len(writes))
+	seen := map[string]bool{}
+	for _, w := range writes {
+		if id := w.Experience.ID; !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	versions := map[string]int64{}
+	for _, id := range ids {
+		current, err := r.GetByID(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		versions[id] = current.Version
+	}
+	if err := repository.CheckBatch(versions, writes); err != nil {
+		return err
+	}
+
+	// Collapse the writes into the final state of each item: DynamoDB does
+	// not allow two operations on the same item in one transaction.
+	final := map[string]*models.Experience{}
+	for _, w := range writes {
+		exp := w.Experience
+		switch w.Kind {
+		case repository.WriteCreate:
+			final[exp.ID] = &exp
+		case repository.WriteUpdate:
+			exp.Version = w.ExpectedVersion + 1
+			final[exp.ID] = &exp
+		case repository.WriteDelete:
+			final[exp.ID] = nil
+		}
+	}
+
+	items := make([]types.TransactWriteItem, 0, len(ids))
+	for _, id := range ids {
+		version, existed := versions[id]
+		exp := final[id]
+		switch {
+		case exp == nil && existed:
+			items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+				TableName:                 aws.String(r.tableName()),
+				Key:                       experienceKey(id),
+				ConditionExpression:       aws.String(conditionVersionEquals),
+				ExpressionAttributeValues: versionValue(version),
+			}})
+		case exp == nil:
+			continue
+		default:
+			item, err := attributevalue.MarshalMap(*exp)
+			if err != nil {
+				return err
+			}
+			put := &types.Put{TableName: aws.String(r.tableName()), Item: item, ConditionExpression: aws.String(conditionNotExists)}
+			if existed {
+				put.ConditionExpression = aws.String(conditionVersionEquals)
+				put.ExpressionAttributeValues = versionValue(version)
+			}
+			items = append(items, types.TransactWriteItem{Put: put})
+		}
+	}
+
+	failed, committed, err := r.transactChunks(ctx, items)
+	if err != nil && committed > 0 {
+		err = fmt.Errorf("%w (%d of %d items were already written)", err, committed, len(items))
+	}
+	if failed >= 0 {
+		return fmt.Errorf("%w: experience changed concurrently: %w", repository.ErrVersionConflict, err)
+	}
+	return err
+}
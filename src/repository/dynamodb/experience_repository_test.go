@@ -0,0 +1,368 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeTable emulates the DynamoDB operations used by ExperienceRepository,
+// evaluating the repository's condition expressions. Stored items are never
+// changed in place, so callers can read them without holding mu.
+type fakeTable struct {
+	mu        sync.Mutex
+	items     map[string]map[string]types.AttributeValue
+	pageSize  int
+	scans     int
+	transacts int
+}
+
+func idOf(key map[string]types.AttributeValue) string {
+	return key["ID"].(*types.AttributeValueMemberS).Value
+}
+
+func numberOf(value types.AttributeValue) int64 {
+	n, _ := strconv.ParseInt(value.(*types.AttributeValueMemberN).Value, 10, 64)
+	return n
+}
+
+func (f *fakeTable) conditionHolds(expression *string, values map[string]types.AttributeValue, id string) bool {
+	current, exists := f.items[id]
+	switch aws.ToString(expression) {
+	case "":
+		return true
+	case conditionNotExists:
+		return !exists
+	case conditionExists:
+		return exists
+	case conditionVersionEquals:
+		return exists && numberOf(current["Version"]) == numberOf(values[":expected"])
+	}
+	panic("unexpected condition " + aws.ToString(expression))
+}
+
+func (f *fakeTable) conditionFailed(id string, returnOld bool) error {
+	failed := &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	if returnOld {
+		failed.Item = f.items[id]
+	}
+	return failed
+}
+
+func (f *fakeTable) install(t *testing.T) {
+	originalPut, originalGet, originalScan := putItemFunc, getItemFunc, scanFunc
+	originalDelete, originalTransact := deleteItemFunc, transactWriteItemsFunc
+	t.Cleanup(func() {
+		putItemFunc, getItemFunc, scanFunc = originalPut, originalGet, originalScan
+		deleteItemFunc, transactWriteItemsFunc = originalDelete, originalTransact
+	})
+
+	putItemFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		id := idOf(input.Item)
+		if !f.conditionHolds(input.ConditionExpression, input.ExpressionAttributeValues, id) {
+			return nil, f.conditionFailed(id, input.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld)
+		}
+		f.items[id] = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	}
+	getItemFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return &dynamodb.GetItemOutput{Item: f.items[idOf(input.Key)]}, nil
+	}
+	deleteItemFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		id := idOf(input.Key)
+		if !f.conditionHolds(input.ConditionExpression, input.ExpressionAttributeValues, id) {
+			return nil, f.conditionFailed(id, input.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld)
+		}
+		delete(f.items, id)
+		return &dynamodb.DeleteItemOutput{}, nil
+	}
+	scanFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.scans++
+		ids := make([]string, 0, len(f.items))
+		for id := range f.items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		start := 0
+		if input.ExclusiveStartKey != nil {
+			start = sort.SearchStrings(ids, idOf(input.ExclusiveStartKey)) + 1
+		}
+		end := start + f.pageSize
+		output := &dynamodb.ScanOutput{}
+		if end < len(ids) {
+			output.LastEvaluatedKey = experienceKey(ids[end-1])
+		} else {
+			end = len(ids)
+		}
+		for _, id := range ids[start:end] {
+			output.Items = append(output.Items, f.items[id])
+		}
+		return output, nil
+	}
+	transactWriteItemsFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.transacts++
+		if len(input.TransactItems) > constants.DynamoDBMaxTransactItems {
+			return nil, errors.New("ValidationException: too many items in the transaction")
+		}
+		reasons := make([]types.CancellationReason, len(input.TransactItems))
+		failed := false
+		for i, item := range input.TransactItems {
+			var ok bool
+			switch {
+			case item.Put != nil:
+				ok = f.conditionHolds(item.Put.ConditionExpression, item.Put.ExpressionAttributeValues, idOf(item.Put.Item))
+			case item.Delete != nil:
+				ok = f.conditionHolds(item.Delete.ConditionExpression, item.Delete.ExpressionAttributeValues, idOf(item.Delete.Key))
+			case item.Update != nil:
+				ok = f.conditionHolds(item.Update.ConditionExpression, item.Update.ExpressionAttributeValues, idOf(item.Update.Key))
+			}
+			reasons[i].Code = aws.String("None")
+			if !ok {
+				reasons[i].Code = aws.String("ConditionalCheckFailed")
+				failed = true
+			}
+		}
+		if failed {
+			return nil, &types.TransactionCanceledException{CancellationReasons: reasons}
+		}
+		for _, item := range input.TransactItems {
+			switch {
+			case item.Put != nil:
+				f.items[idOf(item.Put.Item)] = item.Put.Item
+			case item.Delete != nil:
+				delete(f.items, idOf(item.Delete.Key))
+			case item.Update != nil:
+				stored := map[string]types.AttributeValue{}
+				for name, value := range f.items[idOf(item.Update.Key)] {
+					stored[name] = value
+				}
+				values := item.Update.ExpressionAttributeValues
+				stored["Position"] = values[":position"]
+				stored["Version"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(numberOf(stored["Version"])+1, 10)}
+				if pinned, ok := values[":pinned"]; ok {
+					stored["Pinned"] = pinned
+				}
+				f.items[idOf(item.Update.Key)] = stored
+			}
+		}
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+}
+
+func newFakeRepository(t *testing.T) (*ExperienceRepository, *fakeTable) {
+	t.Helper()
+	table := &fakeTable{items: map[string]map[string]types.AttributeValue{}, pageSize: 2}
+	table.install(t)
+	return &ExperienceRepository{table: "experiences"}, table
+}
+
+func seed(t *testing.T, repo *ExperienceRepository, ids ...string) {
+	t.Helper()
+	for i, id := range ids {
+		exp := models.Experience{ID: id, Title: "Item " + id, Tags: []string{"go"}, Version: 1, CreatedAt: "2024-01-0" + strconv.Itoa(i+1) + "T00:00:00Z"}
+		if err := repo.Create(context.Background(), exp); err != nil {
+			t.Fatalf("seed %s: %v", id, err)
+		}
+	}
+}
+
+func TestExperienceRepositoryCRUD(t *testing.T) {
+	repo, table := newFakeRepository(t)
+	ctx := context.Background()
+	seed(t, repo, "c", "a", "b")
+
+	items, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 3 || items[0].ID != "c" || items[2].ID != "b" || table.scans != 2 {
+		t.Fatalf("expected all pages in creation order, got %+v after %d scans", items, table.scans)
+	}
+
+	if err := repo.Create(ctx, models.Experience{ID: "a", Version: 1}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected duplicate create to conflict, got %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, "a")
+	if err != nil || got.Title != "Item a" || len(got.Tags) != 1 {
+		t.Fatalf("unexpected get: %+v, %v", got, err)
+	}
+	if _, err := repo.GetByID(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	got.Title = "Actualizado"
+	if err := repo.Update(ctx, got, 1); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, "a"); stored.Title != "Actualizado" || stored.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %+v", stored)
+	}
+	if err := repo.Update(ctx, got, 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected stale update to conflict, got %v", err)
+	}
+	if err := repo.Update(ctx, models.Experience{ID: "missing"}, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected update of missing item to be ErrNotFound, got %v", err)
+	}
+
+	if err := repo.Delete(ctx, "a", 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected stale delete to conflict, got %v", err)
+	}
+	if err := repo.Delete(ctx, "a", 2); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Delete(ctx, "a", 2); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
+
+func TestExperienceRepositoryUpdatePositionsIsAtomic(t *testing.T) {
+	repo, _ := newFakeRepository(t)
+	ctx := context.Background()
+	seed(t, repo, "a", "b")
+
+	pinned := true
+	err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "a", Position: 5}, {ID: "missing", Position: 1}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, "a"); stored.Position != 0 || stored.Version != 1 {
+		t.Fatalf("expected no partial write, got %+v", stored)
+	}
+
+	if err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "a", Position: 5, Pinned: &pinned}, {ID: "b", Position: 1}}); err != nil {
+		t.Fatalf("update positions: %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, "a"); stored.Position != 5 || !stored.Pinned || stored.Version != 2 {
+		t.Fatalf("unexpected positions: %+v", stored)
+	}
+}
+
+func TestExperienceRepositoryApplyBatch(t *testing.T) {
+	repo, _ := newFakeRepository(t)
+	ctx := context.Background()
+	seed(t, repo, "a", "b")
+
+	writes := []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: models.Experience{ID: "c", Title: "Nuevo", Version: 1}},
+		{Kind: repository.WriteUpdate, Experience: models.Experience{ID: "c", Title: "Nuevo editado"}, ExpectedVersion: 1},
+		{Kind: repository.WriteUpdate, Experience: models.Experience{ID: "a", Title: "A2"}, ExpectedVersion: 1},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "b"}, ExpectedVersion: 1},
+	}
+	if err := repo.ApplyBatch(ctx, writes); err != nil {
+		t.Fatalf("apply batch: %v", err)
+	}
+	items, _ := repo.List(ctx)
+	if len(items) != 2 {
+		t.Fatalf("expected two items, got %+v", items)
+	}
+	if stored, _ := repo.GetByID(ctx, "c"); stored.Title != "Nuevo editado" || stored.Version != 2 {
+		t.Fatalf("expected collapsed create+update, got %+v", stored)
+	}
+
+	stale := []repository.ExperienceWrite{
+		{Kind: repository.WriteUpdate, Experience: models.Experience{ID: "a", Title: "A3"}, ExpectedVersion: 2},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "c"}, ExpectedVersion: 1},
+	}
+	if err := repo.ApplyBatch(ctx, stale); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, "a"); stored.Title != "A2" {
+		t.Fatalf("expected no partial write, got %+v", stored)
+	}
+}
+
+// seedMany stores n experiences with IDs "e000", "e001"... and returns them.
+func seedMany(t *testing.T, repo *ExperienceRepository, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("e%03d", i)
+		if err := repo.Create(context.Background(), models.Experience{ID: ids[i], Title: "Item", Version: 1}); err != nil {
+			t.Fatalf("seed %s: %v", ids[i], err)
+		}
+	}
+	return ids
+}
+
+func TestExperienceRepositoryUpdatePositionsAboveTransactionLimit(t *testing.T) {
+	repo, table := newFakeRepository(t)
+	ctx := context.Background()
+	ids := seedMany(t, repo, constants.DynamoDBMaxTransactItems+1)
+
+	updates := make([]repository.PositionUpdate, len(ids))
+	for i, id := range ids {
+		updates[i] = repository.PositionUpdate{ID: id, Position: len(ids) - i}
+	}
+	missing := append(append([]repository.PositionUpdate{}, updates...), repository.PositionUpdate{ID: "missing"})
+	if err := repo.UpdatePositions(ctx, missing); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if table.transacts != 0 {
+		t.Fatalf("expected no transaction with a missing ID, got %d", table.transacts)
+	}
+
+	if err := repo.UpdatePositions(ctx, updates); err != nil {
+		t.Fatalf("update positions: %v", err)
+	}
+	if table.transacts != 2 {
+		t.Fatalf("expected 2 transactions, got %d", table.transacts)
+	}
+	for i, id := range ids {
+		if stored, _ := repo.GetByID(ctx, id); stored.Position != len(ids)-i || stored.Version != 2 {
+			t.Fatalf("unexpected %s after reorder: %+v", id, stored)
+		}
+	}
+}
+
+func TestExperienceRepositoryApplyBatchAboveTransactionLimit(t *testing.T) {
+	repo, table := newFakeRepository(t)
+	ctx := context.Background()
+	ids := seedMany(t, repo, constants.DynamoDBMaxTransactItems+1)
+
+	writes := make([]repository.ExperienceWrite, len(ids))
+	for i, id := range ids {
+		writes[i] = repository.ExperienceWrite{Kind: repository.WriteUpdate, Experience: models.Experience{ID: id, Title: "Editado"}, ExpectedVersion: 1}
+	}
+	if err := repo.ApplyBatch(ctx, writes); err != nil {
+		t.Fatalf("apply batch: %v", err)
+	}
+	if table.transacts != 2 {
+		t.Fatalf("expected 2 transactions, got %d", table.transacts)
+	}
+	for _, id := range ids {
+		if stored, _ := repo.GetByID(ctx, id); stored.Title != "Editado" || stored.Version != 2 {
+			t.Fatalf("unexpected %s after batch: %+v", id, stored)
+		}
+	}
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		repo, _ := newFakeRepository(t)
+		return repo
+	})
+}
@@ -2,7 +2,9 @@ package dynamodbrepo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	models "backend-yonathan/src/models"
 	"backend-yonathan/src/pkg/constants"
@@ -17,20 +19,21 @@ import (
 
 // Injectable DynamoDB operation vars (swap in tests).
 var (
-	putItemFunc = func(client *dynamodb.Client, input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
-		return client.PutItem(context.Background(), input)
+	putItemFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+		return client.PutItem(ctx, input)
 	}
-	getItemFunc = func(client *dynamodb.Client, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
-		return client.GetItem(context.Background(), input)
+	getItemFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+		return client.GetItem(ctx, input)
 	}
-	queryFunc = func(client *dynamodb.Client, input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
-		return client.Query(context.Background(), input)
+	queryFunc = func(ctx context.Context, client *dynamodb.Client, input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		return client.Query(ctx, input)
 	}
 )
 
 // UserRepository is the DynamoDB implementation of repository.UserRepository.
 type UserRepository struct {
 	client *dynamodb.Client
+	table  string
 }
 
 // NewUserRepository creates a new DynamoDB-backed UserRepository.
@@ -38,34 +41,164 @@ func NewUserRepository(client *dynamodb.Client) *UserRepository {
 	return &UserRepository{client: client}
 }
 
+// tableName returns the table from the DSN, or DYNAMO_DB_TABLE.
+func (r *UserRepository) tableName() string {
+	if r.table != "" {
+		return r.table
+	}
+	return constants.TableName()
+}
+
+// emailLockPrefix keys the item that reserves an email in the users table,
+// e.g. UserId "EMAIL#ana@example.com". Lock items have no email attribute,
+// so they never appear in the email index.
+const emailLockPrefix = "EMAIL#"
+
+func userItem(user models.User) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"UserId":    &types.AttributeValueMemberS{Value: user.UserId},
+		"email":     &types.AttributeValueMemberS{Value: user.Email},
+		"password":  &types.AttributeValueMemberS{Value: user.Password},
+		"username":  &types.AttributeValueMemberS{Value: user.UserName},
+		"encrypted": &types.AttributeValueMemberS{Value: user.Encrypted},
+	}
+}
+
+// CreateUser writes the user and an email lock item in one transaction,
+// both conditioned on not existing, so two registrations of the same email
+// cannot both succeed. Users created before lock items existed are caught by
+// a lookup on the email index first.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	conflict := fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	if _, err := r.GetUserByEmail(ctx, user.Email); err == nil {
+		return models.User{}, conflict
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return models.User{}, err
+	}
+	if user.UserId == "" {
+		user.UserId = uuid.New().String()
+	}
+
+	notExists := aws.String("attribute_not_exists(UserId)")
+	_, err := transactWriteItemsFunc(ctx, r.client, &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName: aws.String(r.tableName()),
+			Item: map[string]types.AttributeValue{
+				"UserId":  &types.AttributeValueMemberS{Value: emailLockPrefix + user.Email},
+				"ownerId": &types.AttributeValueMemberS{Value: user.UserId},
+			},
+			ConditionExpression: notExists,
+		}},
+		{Put: &types.Put{
+			TableName:           aws.String(r.tableName()),
+			Item:                userItem(user),
+			ConditionExpression: notExists,
+		}},
+	}})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return models.User{}, conflict
+			}
+		}
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
 // SaveUser persists a user to DynamoDB. Generates a UserId if empty.
+// The user, the lock item of its email and, when the email changed, the
+// release of the previous lock are written in one transaction. Taking the
+// email of another user returns an error wrapping ErrConflict.
 func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
 	if user.UserId == "" {
 		user.UserId = uuid.New().String()
 	}
-	input := &dynamodb.PutItemInput{
-		TableName: aws.String(constants.TableName()),
-		Item: map[string]types.AttributeValue{
-			"UserId":   &types.AttributeValueMemberS{Value: user.UserId},
-			"email":    &types.AttributeValueMemberS{Value: user.Email},
-			"password": &types.AttributeValueMemberS{Value: user.Password},
-			"username": &types.AttributeValueMemberS{Value: user.UserName},
-		},
+	conflict := fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	if owner, err := r.GetUserByEmail(ctx, user.Email); err == nil && owner.UserId != user.UserId {
+		return conflict
+	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	previous, err := r.GetUserByID(ctx, user.UserId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	ownedBy := aws.String("attribute_not_exists(UserId) OR ownerId = :owner")
+	owner := map[string]types.AttributeValue{":owner": &types.AttributeValueMemberS{Value: user.UserId}}
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName: aws.String(r.tableName()),
+			Item: map[string]types.AttributeValue{
+				"UserId":  &types.AttributeValueMemberS{Value: emailLockPrefix + user.Email},
+				"ownerId": &types.AttributeValueMemberS{Value: user.UserId},
+			},
+			ConditionExpression:       ownedBy,
+			ExpressionAttributeValues: owner,
+		}},
+		{Put: &types.Put{TableName: aws.String(r.tableName()), Item: userItem(user)}},
+	}
+	if previous.Email != "" && previous.Email != user.Email {
+		items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(r.tableName()),
+			Key:                       map[string]types.AttributeValue{"UserId": &types.AttributeValueMemberS{Value: emailLockPrefix + previous.Email}},
+			ConditionExpression:       ownedBy,
+			ExpressionAttributeValues: owner,
+		}})
+	}
+	_, err = transactWriteItemsFunc(ctx, r.client, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return conflict
+			}
+		}
 	}
-	_, err := putItemFunc(r.client, input)
 	return err
 }
 
+// ListUsers scans the users table, skipping email lock items.
+func (r *UserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	users := make([]models.User, 0)
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName()), ConsistentRead: aws.Bool(true)}
+	for {
+		result, err := scanFunc(ctx, r.client, input)
+		if err != nil {
+			return nil, err
+		}
+		page := make([]models.User, 0, len(result.Items))
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &page); err != nil {
+			return nil, err
+		}
+		for _, user := range page {
+			if !strings.HasPrefix(user.UserId, emailLockPrefix) {
+				users = append(users, user)
+			}
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	repository.SortUsers(users)
+	return users, nil
+}
+
 // GetUserByID fetches a user by primary key.
 func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
 	var user models.User
 	input := &dynamodb.GetItemInput{
-		TableName: aws.String(constants.TableName()),
+		TableName: aws.String(r.tableName()),
 		Key: map[string]types.AttributeValue{
 			"UserId": &types.AttributeValueMemberS{Value: id},
 		},
 	}
-	result, err := getItemFunc(r.client, input)
+	result, err := getItemFunc(ctx, r.client, input)
 	if err != nil {
 		return user, err
 	}
@@ -80,7 +213,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.Use
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
 	var user models.User
 	input := &dynamodb.QueryInput{
-		TableName: aws.String(constants.TableName()),
+		TableName: aws.String(r.tableName()),
 		IndexName: aws.String(constants.DynamoDBEmailIndex),
 		KeyConditions: map[string]types.Condition{
 			"email": {
@@ -91,7 +224,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (mode
 			},
 		},
 	}
-	result, err := queryFunc(r.client, input)
+	result, err := queryFunc(ctx, r.client, input)
 	if err != nil {
 		return user, err
 	}
@@ -0,0 +1,175 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// installFakeUsersTable emulates the users table keyed by UserId, with the
+// email index and the conditions used by CreateUser and SaveUser.
+func installFakeUsersTable(t *testing.T) map[string]map[string]types.AttributeValue {
+	items := map[string]map[string]types.AttributeValue{}
+	var mu sync.Mutex
+	originalGet, originalQuery, originalScan, originalTransact := getItemFunc, queryFunc, scanFunc, transactWriteItemsFunc
+	t.Cleanup(func() {
+		getItemFunc, queryFunc, scanFunc, transactWriteItemsFunc = originalGet, originalQuery, originalScan, originalTransact
+	})
+
+	keyOf := func(item map[string]types.AttributeValue) string {
+		return item["UserId"].(*types.AttributeValueMemberS).Value
+	}
+	getItemFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return &dynamodb.GetItemOutput{Item: items[keyOf(input.Key)]}, nil
+	}
+	queryFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		email := input.KeyConditions["email"].AttributeValueList[0].(*types.AttributeValueMemberS).Value
+		output := &dynamodb.QueryOutput{}
+		for _, item := range items {
+			if value, ok := item["email"].(*types.AttributeValueMemberS); ok && value.Value == email {
+				output.Items = append(output.Items, item)
+			}
+		}
+		return output, nil
+	}
+	scanFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		output := &dynamodb.ScanOutput{}
+		for _, item := range items {
+			output.Items = append(output.Items, item)
+		}
+		return output, nil
+	}
+	// holds reports whether the condition of a write holds for the stored
+	// item with the given key.
+	holds := func(condition *string, values map[string]types.AttributeValue, key string) bool {
+		stored, exists := items[key]
+		switch aws.ToString(condition) {
+		case "":
+			return true
+		case "attribute_not_exists(UserId)":
+			return !exists
+		case "attribute_not_exists(UserId) OR ownerId = :owner":
+			if !exists {
+				return true
+			}
+			owner, _ := stored["ownerId"].(*types.AttributeValueMemberS)
+			return owner != nil && owner.Value == values[":owner"].(*types.AttributeValueMemberS).Value
+		}
+		t.Fatalf("unexpected condition %q", aws.ToString(condition))
+		return false
+	}
+	transactWriteItemsFunc = func(_ context.Context, _ *dynamodb.Client, input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reasons := make([]types.CancellationReason, len(input.TransactItems))
+		failed := false
+		for i, item := range input.TransactItems {
+			reasons[i].Code = aws.String("None")
+			ok := false
+			if item.Put != nil {
+				ok = holds(item.Put.ConditionExpression, item.Put.ExpressionAttributeValues, keyOf(item.Put.Item))
+			} else {
+				ok = holds(item.Delete.ConditionExpression, item.Delete.ExpressionAttributeValues, keyOf(item.Delete.Key))
+			}
+			if !ok {
+				reasons[i].Code = aws.String("ConditionalCheckFailed")
+				failed = true
+			}
+		}
+		if failed {
+			return nil, &types.TransactionCanceledException{CancellationReasons: reasons}
+		}
+		for _, item := range input.TransactItems {
+			if item.Put != nil {
+				items[keyOf(item.Put.Item)] = item.Put.Item
+			} else {
+				delete(items, keyOf(item.Delete.Key))
+			}
+		}
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+	return items
+}
+
+func TestCreateUserReservesEmail(t *testing.T) {
+	items := installFakeUsersTable(t)
+	repo := &UserRepository{table: "users"}
+
+	user, err := repo.CreateUser(context.Background(), models.User{Email: "ana@example.com", UserName: "ana"})
+	if err != nil || user.UserId == "" {
+		t.Fatalf("CreateUser = %+v, %v", user, err)
+	}
+	if _, ok := items[emailLockPrefix+"ana@example.com"]; !ok {
+		t.Fatal("email lock item not written")
+	}
+
+	// A lock without a visible user (e.g. a registration racing this one)
+	// is enough to reject the email.
+	items[emailLockPrefix+"luis@example.com"] = map[string]types.AttributeValue{
+		"UserId": &types.AttributeValueMemberS{Value: emailLockPrefix + "luis@example.com"},
+	}
+	for _, email := range []string{"ana@example.com", "luis@example.com"} {
+		if _, err := repo.CreateUser(context.Background(), models.User{Email: email}); !errors.Is(err, repository.ErrConflict) {
+			t.Errorf("CreateUser(%s) error = %v, want ErrConflict", email, err)
+		}
+	}
+}
+
+func TestListUsersSkipsEmailLocks(t *testing.T) {
+	installFakeUsersTable(t)
+
+	ctx := context.Background()
+	repo := &UserRepository{table: "users"}
+	for _, email := range []string{"b@example.com", "a@example.com"} {
+		if _, err := repo.CreateUser(ctx, models.User{UserId: email[:1], Email: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users, err := repo.ListUsers(ctx)
+	if err != nil || len(users) != 2 || users[0].UserId != "a" || users[1].UserId != "b" {
+		t.Fatalf("ListUsers = %+v, %v", users, err)
+	}
+}
+
+func TestSaveUserMovesEmailLock(t *testing.T) {
+	items := installFakeUsersTable(t)
+	ctx := context.Background()
+	repo := &UserRepository{table: "users"}
+
+	user, err := repo.CreateUser(ctx, models.User{Email: "ana@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Email = "ana@example.org"
+	if err := repo.SaveUser(ctx, user); err != nil {
+		t.Fatalf("SaveUser: %v", err)
+	}
+	if _, ok := items[emailLockPrefix+"ana@example.com"]; ok {
+		t.Error("lock of the previous email was not released")
+	}
+	if _, ok := items[emailLockPrefix+"ana@example.org"]; !ok {
+		t.Error("lock of the new email was not written")
+	}
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		installFakeUsersTable(t)
+		return &UserRepository{table: "users"}
+	})
+}
@@ -0,0 +1,104 @@
+package encryptedrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/fieldcrypto"
+	"backend-yonathan/src/repository"
+)
+
+// ErrFailedUsers is returned by Rotate when some users could not be
+// re-encrypted; the others were.
+var ErrFailedUsers = errors.New("some users could not be re-encrypted")
+
+// Store is a raw users store that Rotate can enumerate and rewrite.
+type Store interface {
+	repository.UserRepository
+	repository.UserLister
+}
+
+// RotateReport counts the users seen by Rotate.
+type RotateReport struct {
+	Total     int
+	Encrypted int // stored in plaintext, now encrypted
+	Resealed  int // sealed with a retired key or other fields, now resealed
+	Unchanged int
+	Failed    int
+}
+
+// Rotate rewrites the users of the raw store so that all of them are sealed
+// with the current key and the given fields: users stored before encryption
+// was enabled are encrypted and users sealed with a retired key are
+// decrypted and sealed again. Retired keys can be removed from the key file
+// once it reports no failures. With dryRun nothing is written. Failures are
+// printed to progress, when set, and reported as ErrFailedUsers.
+func Rotate(ctx context.Context, store Store, cipher *fieldcrypto.Cipher, fields []string, dryRun bool, progress io.Writer) (RotateReport, error) {
+	var report RotateReport
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		return report, err
+	}
+	encrypted := newUserRepository(store, cipher, fields)
+	for _, stored := range users {
+		report.Total++
+		needed, err := encrypted.needsReseal(ctx, stored)
+		if err == nil && needed {
+			err = encrypted.reseal(ctx, stored, dryRun)
+		}
+		switch {
+		case err != nil:
+			report.Failed++
+			if progress != nil {
+				fmt.Fprintf(progress, "usuario %s: %v\n", stored.UserId, err)
+			}
+		case !needed:
+			report.Unchanged++
+		case stored.Encrypted == "":
+			report.Encrypted++
+		default:
+			report.Resealed++
+		}
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%w: %d of %d", ErrFailedUsers, report.Failed, report.Total)
+	}
+	return report, nil
+}
+
+// needsReseal reports whether a stored user is not sealed with the current
+// key and the configured fields.
+func (r *UserRepository) needsReseal(ctx context.Context, stored models.User) (bool, error) {
+	if stored.Encrypted == "" {
+		return r.email || r.name, nil
+	}
+	keyID, err := fieldcrypto.KeyID(stored.Encrypted)
+	if err != nil {
+		return false, fmt.Errorf("user %s: %w", stored.UserId, err)
+	}
+	if keyID != r.cipher.CurrentKeyID() {
+		return true, nil
+	}
+	fields, err := r.openFields(ctx, stored)
+	if err != nil {
+		return false, err
+	}
+	return (fields.Email != nil) != r.email || (fields.UserName != nil) != r.name, nil
+}
+
+// reseal decrypts a stored user with any key and stores it sealed with the
+// current key.
+func (r *UserRepository) reseal(ctx context.Context, stored models.User, dryRun bool) error {
+	user, err := r.open(ctx, stored)
+	if err != nil {
+		return err
+	}
+	resealed, err := r.seal(ctx, user)
+	if err != nil || dryRun {
+		return err
+	}
+	return r.inner.SaveUser(ctx, resealed)
+}
@@ -0,0 +1,254 @@
+// Package encryptedrepo provides a UserRepository decorator that encrypts
+// sensitive user fields before they reach the wrapped backend and decrypts
+// them on the way back.
+package encryptedrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/fieldcrypto"
+	"backend-yonathan/src/repository"
+
+	"github.com/google/uuid"
+)
+
+// Encryptable user fields.
+const (
+	FieldEmail    = "email"
+	FieldUserName = "username"
+)
+
+// ParseFields parses a comma-separated list of encryptable fields, such as
+// USER_ENCRYPTED_FIELDS.
+func ParseFields(list string) ([]string, error) {
+	fields := []string{}
+	for _, field := range strings.Split(list, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		switch field {
+		case "":
+		case FieldEmail, FieldUserName:
+			fields = append(fields, field)
+		default:
+			return nil, fmt.Errorf("unknown encrypted user field %q (use %s or %s)", field, FieldEmail, FieldUserName)
+		}
+	}
+	return fields, nil
+}
+
+// sealedFields is the plaintext of User.Encrypted.
+type sealedFields struct {
+	Email    *string `json:"email,omitempty"`
+	UserName *string `json:"username,omitempty"`
+}
+
+// UserRepository stores the selected fields of every user sealed in
+// User.Encrypted; the stored Email is the blind index of the email, so
+// lookups by email and the email uniqueness of the backend keep working.
+// Users stored before encryption was enabled are read as they are, and
+// Rotate encrypts them.
+type UserRepository struct {
+	inner  repository.UserRepository
+	cipher *fieldcrypto.Cipher
+	email  bool
+	name   bool
+}
+
+// listingUserRepository adds ListUsers when the wrapped repository can list
+// users.
+type listingUserRepository struct {
+	*UserRepository
+	lister repository.UserLister
+}
+
+// NewUserRepository wraps inner so that fields (FieldEmail, FieldUserName)
+// are encrypted with cipher. The result implements repository.UserLister
+// exactly when inner does.
+func NewUserRepository(inner repository.UserRepository, cipher *fieldcrypto.Cipher, fields []string) repository.UserRepository {
+	encrypted := newUserRepository(inner, cipher, fields)
+	if lister, ok := inner.(repository.UserLister); ok {
+		return &listingUserRepository{UserRepository: encrypted, lister: lister}
+	}
+	return encrypted
+}
+
+func newUserRepository(inner repository.UserRepository, cipher *fieldcrypto.Cipher, fields []string) *UserRepository {
+	encrypted := &UserRepository{inner: inner, cipher: cipher}
+	for _, field := range fields {
+		switch field {
+		case FieldEmail:
+			encrypted.email = true
+		case FieldUserName:
+			encrypted.name = true
+		}
+	}
+	return encrypted
+}
+
+// seal returns user as stored: the selected fields sealed in Encrypted
+// (bound to the UserId) and replaced by a blind index or left empty.
+func (r *UserRepository) seal(ctx context.Context, user models.User) (models.User, error) {
+	user.Encrypted = ""
+	if !r.email && !r.name {
+		return user, nil
+	}
+	var fields sealedFields
+	if r.email {
+		email := user.Email
+		fields.Email = &email
+		user.Email = r.cipher.BlindIndex(email)
+	}
+	if r.name {
+		name := user.UserName
+		fields.UserName = &name
+		user.UserName = ""
+	}
+	plaintext, err := json.Marshal(fields)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.Encrypted, err = r.cipher.Seal(ctx, plaintext, []byte(user.UserId)); err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// open returns a stored user with its sealed fields restored. Users without
+// sealed fields are returned unchanged.
+func (r *UserRepository) open(ctx context.Context, stored models.User) (models.User, error) {
+	if stored.Encrypted == "" {
+		return stored, nil
+	}
+	fields, err := r.openFields(ctx, stored)
+	if err != nil {
+		return models.User{}, err
+	}
+	user := stored
+	user.Encrypted = ""
+	if fields.Email != nil {
+		user.Email = *fields.Email
+	}
+	if fields.UserName != nil {
+		user.UserName = *fields.UserName
+	}
+	return user, nil
+}
+
+// openFields decrypts the sealed fields of a stored user.
+func (r *UserRepository) openFields(ctx context.Context, stored models.User) (sealedFields, error) {
+	var fields sealedFields
+	plaintext, err := r.cipher.Open(ctx, stored.Encrypted, []byte(stored.UserId))
+	if err != nil {
+		return fields, fmt.Errorf("user %s: %w", stored.UserId, err)
+	}
+	if err := json.Unmarshal(plaintext, &fields); err != nil {
+		return fields, fmt.Errorf("user %s: %w", stored.UserId, err)
+	}
+	return fields, nil
+}
+
+// checkLegacyEmail rejects an email still stored in plaintext by another
+// user, which the backend cannot match against a blind index.
+func (r *UserRepository) checkLegacyEmail(ctx context.Context, user models.User) error {
+	if !r.email {
+		return nil
+	}
+	owner, err := r.inner.GetUserByEmail(ctx, user.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if owner.UserId != user.UserId {
+		return fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	}
+	return nil
+}
+
+// CreateUser stores a new user with its fields sealed. The UserId is
+// generated here when empty, since the sealed fields are bound to it.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	if err := r.checkLegacyEmail(ctx, user); err != nil {
+		return models.User{}, err
+	}
+	stored, err := r.seal(ctx, user)
+	if err != nil {
+		return models.User{}, err
+	}
+	if _, err := r.inner.CreateUser(ctx, stored); err != nil {
+		return models.User{}, err
+	}
+	user.Encrypted = ""
+	return user, nil
+}
+
+// SaveUser inserts or replaces a user with its fields sealed.
+func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	if err := r.checkLegacyEmail(ctx, user); err != nil {
+		return err
+	}
+	stored, err := r.seal(ctx, user)
+	if err != nil {
+		return err
+	}
+	return r.inner.SaveUser(ctx, stored)
+}
+
+// GetUserByID returns a user with its fields decrypted.
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
+	stored, err := r.inner.GetUserByID(ctx, id)
+	if err != nil {
+		return models.User{}, err
+	}
+	return r.open(ctx, stored)
+}
+
+// GetUserByEmail looks a user up by the blind index of email, then by the
+// plaintext email for users stored before encryption was enabled.
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	if !r.email {
+		stored, err := r.inner.GetUserByEmail(ctx, email)
+		if err != nil {
+			return models.User{}, err
+		}
+		return r.open(ctx, stored)
+	}
+	stored, err := r.inner.GetUserByEmail(ctx, r.cipher.BlindIndex(email))
+	if errors.Is(err, repository.ErrNotFound) {
+		stored, err = r.inner.GetUserByEmail(ctx, email)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Do not leak the blind index in the error.
+			return models.User{}, fmt.Errorf("%w: email %s", repository.ErrNotFound, email)
+		}
+		return models.User{}, err
+	}
+	return r.open(ctx, stored)
+}
+
+// ListUsers returns every user with its fields decrypted.
+func (r *listingUserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	stored, err := r.lister.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]models.User, len(stored))
+	for i, user := range stored {
+		if users[i], err = r.open(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
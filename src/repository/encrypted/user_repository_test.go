@@ -0,0 +1,224 @@
+package encryptedrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/fieldcrypto"
+	"backend-yonathan/src/repository"
+	memory "backend-yonathan/src/repository/memory"
+	"backend-yonathan/src/repository/repotest"
+)
+
+// testKeys is a KeyProvider that wraps data keys by XOR, which is enough
+// to tell keys apart.
+type testKeys struct {
+	current string
+	keys    map[string]byte
+}
+
+func (k *testKeys) CurrentKeyID() string { return k.current }
+
+func (k *testKeys) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	return k.xor(keyID, dataKey)
+}
+
+func (k *testKeys) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	return k.xor(keyID, wrapped)
+}
+
+func (k *testKeys) xor(keyID string, in []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fieldcrypto.ErrUnknownKey
+	}
+	out := make([]byte, len(in))
+	for i, b := range in {
+		out[i] = b ^ key
+	}
+	return out, nil
+}
+
+func newTestCipher(keys *testKeys) *fieldcrypto.Cipher {
+	return fieldcrypto.NewCipher(keys, bytes.Repeat([]byte{7}, 32))
+}
+
+func testUser(id, email string) models.User {
+	return models.User{UserId: id, Email: email, Password: "$2a$10$hash", UserName: "Ana"}
+}
+
+func TestStoresOnlySealedFields(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewUserRepository()
+	cipher := newTestCipher(&testKeys{current: "k1", keys: map[string]byte{"k1": 1}})
+	repo := NewUserRepository(inner, cipher, []string{FieldEmail, FieldUserName})
+
+	user := testUser("u-1", "ana@example.com")
+	if created, err := repo.CreateUser(ctx, user); err != nil || created != user {
+		t.Fatalf("CreateUser = %+v, %v", created, err)
+	}
+
+	stored, err := inner.GetUserByID(ctx, "u-1")
+	if err != nil {
+		t.Fatalf("inner GetUserByID: %v", err)
+	}
+	if stored.Email != cipher.BlindIndex("ana@example.com") || stored.UserName != "" || stored.Encrypted == "" {
+		t.Fatalf("stored %+v", stored)
+	}
+	if strings.Contains(stored.Encrypted, "ana") {
+		t.Fatalf("stored ciphertext contains plaintext: %q", stored.Encrypted)
+	}
+
+	for name, get := range map[string]func() (models.User, error){
+		"GetUserByID":    func() (models.User, error) { return repo.GetUserByID(ctx, "u-1") },
+		"GetUserByEmail": func() (models.User, error) { return repo.GetUserByEmail(ctx, "ana@example.com") },
+	} {
+		if got, err := get(); err != nil || got != user {
+			t.Fatalf("%s = %+v, %v; want %+v", name, got, err, user)
+		}
+	}
+	users, err := repo.(repository.UserLister).ListUsers(ctx)
+	if err != nil || len(users) != 1 || users[0] != user {
+		t.Fatalf("ListUsers = %+v, %v", users, err)
+	}
+}
+
+func TestEmailUniquenessAndChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(memory.NewUserRepository(), newTestCipher(&testKeys{current: "k1", keys: map[string]byte{"k1": 1}}), []string{FieldEmail})
+
+	created, err := repo.CreateUser(ctx, testUser("", "ana@example.com"))
+	if err != nil || created.UserId == "" {
+		t.Fatalf("CreateUser = %+v, %v", created, err)
+	}
+	if _, err := repo.CreateUser(ctx, testUser("u-2", "ana@example.com")); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("CreateUser with taken email: %v, want ErrConflict", err)
+	}
+
+	created.Email = "eva@example.com"
+	if err := repo.SaveUser(ctx, created); err != nil {
+		t.Fatalf("SaveUser: %v", err)
+	}
+	if _, err := repo.GetUserByEmail(ctx, "ana@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetUserByEmail(old email): %v, want ErrNotFound", err)
+	}
+	if got, err := repo.GetUserByEmail(ctx, "eva@example.com"); err != nil || got != created {
+		t.Fatalf("GetUserByEmail(new email) = %+v, %v", got, err)
+	}
+}
+
+func TestLegacyPlaintextUsers(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewUserRepository()
+	legacy := testUser("u-1", "ana@example.com")
+	if _, err := inner.CreateUser(ctx, legacy); err != nil {
+		t.Fatalf("inner CreateUser: %v", err)
+	}
+	repo := NewUserRepository(inner, newTestCipher(&testKeys{current: "k1", keys: map[string]byte{"k1": 1}}), []string{FieldEmail})
+
+	if got, err := repo.GetUserByEmail(ctx, "ana@example.com"); err != nil || got != legacy {
+		t.Fatalf("GetUserByEmail = %+v, %v; want %+v", got, err, legacy)
+	}
+	if _, err := repo.CreateUser(ctx, testUser("u-2", "ana@example.com")); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("CreateUser with legacy email: %v, want ErrConflict", err)
+	}
+	// Saving the legacy user encrypts it.
+	if err := repo.SaveUser(ctx, legacy); err != nil {
+		t.Fatalf("SaveUser: %v", err)
+	}
+	if stored, _ := inner.GetUserByID(ctx, "u-1"); stored.Encrypted == "" {
+		t.Fatalf("legacy user still in plaintext: %+v", stored)
+	}
+	if got, err := repo.GetUserByEmail(ctx, "ana@example.com"); err != nil || got != legacy {
+		t.Fatalf("GetUserByEmail after SaveUser = %+v, %v", got, err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewUserRepository()
+	keys := &testKeys{current: "k1", keys: map[string]byte{"k1": 1}}
+	old := NewUserRepository(inner, newTestCipher(keys), []string{FieldEmail})
+	for _, user := range []models.User{testUser("u-1", "ana@example.com"), testUser("u-2", "eva@example.com")} {
+		if _, err := old.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	legacy := testUser("u-3", "leo@example.com")
+	if _, err := inner.CreateUser(ctx, legacy); err != nil {
+		t.Fatalf("inner CreateUser: %v", err)
+	}
+
+	keys.current, keys.keys["k2"] = "k2", 2
+	cipher := newTestCipher(keys)
+	if report, err := Rotate(ctx, inner, cipher, []string{FieldEmail}, true, nil); err != nil || report != (RotateReport{Total: 3, Encrypted: 1, Resealed: 2}) {
+		t.Fatalf("Rotate dry-run = %+v, %v", report, err)
+	}
+	if stored, _ := inner.GetUserByID(ctx, "u-3"); stored.Encrypted != "" {
+		t.Fatal("dry-run wrote users")
+	}
+	if report, err := Rotate(ctx, inner, cipher, []string{FieldEmail}, false, nil); err != nil || report != (RotateReport{Total: 3, Encrypted: 1, Resealed: 2}) {
+		t.Fatalf("Rotate = %+v, %v", report, err)
+	}
+	if report, err := Rotate(ctx, inner, cipher, []string{FieldEmail}, false, nil); err != nil || report != (RotateReport{Total: 3, Unchanged: 3}) {
+		t.Fatalf("second Rotate = %+v, %v", report, err)
+	}
+
+	// Every user opens without the retired key.
+	delete(keys.keys, "k1")
+	repo := NewUserRepository(inner, cipher, []string{FieldEmail})
+	for _, email := range []string{"ana@example.com", "eva@example.com", "leo@example.com"} {
+		if got, err := repo.GetUserByEmail(ctx, email); err != nil || got.Email != email {
+			t.Fatalf("GetUserByEmail(%s) = %+v, %v", email, got, err)
+		}
+	}
+
+	// Sealing one more field reseals everyone with the current key.
+	if report, err := Rotate(ctx, inner, cipher, []string{FieldEmail, FieldUserName}, false, nil); err != nil || report != (RotateReport{Total: 3, Resealed: 3}) {
+		t.Fatalf("Rotate with more fields = %+v, %v", report, err)
+	}
+}
+
+func TestRotateReportsUsersItCannotOpen(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewUserRepository()
+	keys := &testKeys{current: "k1", keys: map[string]byte{"k1": 1}}
+	if _, err := NewUserRepository(inner, newTestCipher(keys), []string{FieldEmail}).CreateUser(ctx, testUser("u-1", "ana@example.com")); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	keys.current, keys.keys = "k2", map[string]byte{"k2": 2}
+
+	var progress bytes.Buffer
+	report, err := Rotate(ctx, inner, newTestCipher(keys), []string{FieldEmail}, false, &progress)
+	if !errors.Is(err, ErrFailedUsers) || report.Failed != 1 || !strings.Contains(progress.String(), "u-1") {
+		t.Fatalf("Rotate = %+v, %v (%q)", report, err, progress.String())
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	if fields, err := ParseFields(" Email, username ,"); err != nil || strings.Join(fields, ",") != "email,username" {
+		t.Fatalf("ParseFields = %v, %v", fields, err)
+	}
+	if _, err := ParseFields("email,password"); err == nil {
+		t.Fatal("ParseFields accepted password")
+	}
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	for name, fields := range map[string][]string{
+		"NoFields":  nil,
+		"Email":     {FieldEmail},
+		"AllFields": {FieldEmail, FieldUserName},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+				cipher := newTestCipher(&testKeys{current: "k1", keys: map[string]byte{"k1": 1}})
+				return NewUserRepository(memory.NewUserRepository(), cipher, fields)
+			}, repotest.OwnsEncrypted())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package firestorerepo
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"backend-yonathan/src/config"
+	"backend-yonathan/src/repository"
+	"cloud.google.com/go/firestore"
+)
+
+// Injectable client constructor (swap in tests).
+var newClientFunc = config.NewFirestoreClient
+
+// clients shares one Firestore client per project across the stores of a
+// configuration.
+var clients = struct {
+	mu        sync.Mutex
+	byProject map[string]*firestore.Client
+}{byProject: map[string]*firestore.Client{}}
+
+// clientFor returns the client of the DSN project (firestore://my-project),
+// or of GCP_PROJECT_ID for firestore:.
+func clientFor(ctx context.Context, dsn repository.DSN) (*firestore.Client, error) {
+	project := dsn.Location
+	if project == "" {
+		project = os.Getenv("GCP_PROJECT_ID")
+	}
+
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+	if client, ok := clients.byProject[project]; ok {
+		return client, nil
+	}
+	client, err := newClientFunc(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	clients.byProject[project] = client
+	return client, nil
+}
+
+func init() {
+	repository.Register("firestore", repository.Backend{
+		Users: func(ctx context.Context, dsn repository.DSN) (repository.UserRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return NewUserRepository(client), nil
+		},
+		Experiences: func(ctx context.Context, dsn repository.DSN) (repository.ExperienceRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return NewExperienceRepository(client), nil
+		},
+		Skills: func(ctx context.Context, dsn repository.DSN) (repository.SkillRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return NewSkillRepository(client), nil
+		},
+		TagAliases: func(ctx context.Context, dsn repository.DSN) (repository.TagAliasRepository, error) {
+			client, err := clientFor(ctx, dsn)
+			if err != nil {
+				return nil, err
+			}
+			return NewTagAliasRepository(client), nil
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package firestorerepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+
+	"cloud.google.com/go/firestore"
+)
+
+// projects numbers the emulator projects of this test run; each subtest gets
+// its own project, so it starts from an empty database.
+var projects atomic.Int64
+
+// emulatorClient returns a client for a fresh project on the Firestore
+// emulator, skipping the test when FIRESTORE_EMULATOR_HOST is not set.
+func emulatorClient(t *testing.T) *firestore.Client {
+	t.Helper()
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST not set; skipping Firestore emulator tests")
+	}
+	project := fmt.Sprintf("repotest-%d-%d", os.Getpid(), projects.Add(1))
+	client, err := firestore.NewClient(context.Background(), project)
+	if err != nil {
+		t.Fatalf("firestore.NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository(emulatorClient(t))
+	})
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return NewExperienceRepository(emulatorClient(t))
+	})
+}
+
+func TestSkillRepositoryConformance(t *testing.T) {
+	repotest.RunSkillRepositoryTests(t, func(t *testing.T) repository.SkillRepository {
+		return NewSkillRepository(emulatorClient(t))
+	})
+}
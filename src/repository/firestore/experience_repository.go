@@ -29,7 +29,7 @@ func (r *ExperienceRepository) col() *firestore.CollectionRef {
 	return r.client.Collection(experiencesCollection)
 }
 
-// List returns all experiences.
+// List returns all experiences, ordered by creation date and ID.
 func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
 	iter := r.col().Documents(ctx)
 	defer iter.Stop()
@@ -50,6 +50,7 @@ func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, e
 		}
 		experiences = append(experiences, exp)
 	}
+	repository.SortExperiences(experiences)
 	return experiences, nil
 }
 
@@ -76,34 +77,201 @@ func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience
 	return err
 }
 
-// Update replaces an existing experience.
-func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience) error {
-	docRef := r.col().Doc(exp.ID)
-	doc, err := docRef.Get(ctx)
+// checkVersion reads an experience inside a transaction and verifies that its
+// stored version matches expectedVersion.
+func checkVersion(tx *firestore.Transaction, docRef *firestore.DocumentRef, id string, expectedVersion int64) error {
+	doc, err := tx.Get(docRef)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			return fmt.Errorf("%w: experience %s", repository.ErrNotFound, exp.ID)
+			return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
 		}
 		return err
 	}
-	_ = doc
+	var current models.Experience
+	if err := doc.DataTo(&current); err != nil {
+		return err
+	}
+	if current.Version != expectedVersion {
+		return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, id)
+	}
+	return nil
+}
 
-	_, err = docRef.Set(ctx, exp)
-	return err
+// Update replaces an existing experience inside a transaction, provided its
+// stored version still matches expectedVersion.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	docRef := r.col().Doc(exp.ID)
+	exp.Version = expectedVersion + 1
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := checkVersion(tx, docRef, exp.ID, expectedVersion); err != nil {
+			return err
+		}
+		return tx.Set(docRef, exp)
+	})
 }
 
-// Delete removes an experience by ID.
-func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
+// Delete removes an experience inside a transaction, provided its stored
+// version still matches expectedVersion.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
 	docRef := r.col().Doc(id)
-	doc, err := docRef.Get(ctx)
-	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := checkVersion(tx, docRef, id, expectedVersion); err != nil {
+			return err
 		}
-		return err
+		return tx.Delete(docRef)
+	})
+}
+
+// UpdatePositions sets position and pin state of several experiences inside a
+// transaction, so either every document is updated or none is.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	refs := make([]*firestore.DocumentRef, len(updates))
+	for i, u := range updates {
+		refs[i] = r.col().Doc(u.ID)
 	}
-	_ = doc
 
-	_, err = docRef.Delete(ctx)
-	return err
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.GetAll(refs)
+		if err != nil {
+			return err
+		}
+		for i, doc := range docs {
+			if !doc.Exists() {
+				return fmt.Errorf("%w: experience %s", repository.ErrNotFound, updates[i].ID)
+			}
+		}
+		for i, u := range updates {
+			changes := []firestore.Update{
+				{Path: "Position", Value: u.Position},
+				{Path: "Version", Value: firestore.Increment(1)},
+			}
+			if u.Pinned != nil {
+				changes = append(changes, firestore.Update{Path: "Pinned", Value: *u.Pinned})
+			}
+			if err := tx.Update(refs[i], changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ApplyBatch validates every write against the stored versions and commits
+// them in a single transaction. Writes to the same document are collapsed so
+// each document is written at most once.
+func (r *ExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	ids := make([]string, 0, len(writes))
+	refs := make([]*firestore.DocumentRef, 0, len(writes))
+	seen := map[string]bool{}
+	for _, w := range writes {
+		if id := w.Experience.ID; !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			refs = append(refs, r.col().Doc(id))
+		}
+	}
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.GetAll(refs)
+		if err != nil {
+			return err
+		}
+		versions := map[string]int64{}
+		for i, doc := range docs {
+			if !doc.Exists() {
+				continue
+			}
+			var current models.Experience
+			if err := doc.DataTo(&current); err != nil {
+				return err
+			}
+			versions[ids[i]] = current.Version
+		}
+		if err := repository.CheckBatch(versions, writes); err != nil {
+			return err
+		}
+
+		final := map[string]*models.Experience{}
+		for _, w := range writes {
+			exp := w.Experience
+			switch w.Kind {
+			case repository.WriteCreate:
+				final[exp.ID] = &exp
+			case repository.WriteUpdate:
+				exp.Version = w.ExpectedVersion + 1
+				final[exp.ID] = &exp
+			case repository.WriteDelete:
+				final[exp.ID] = nil
+			}
+		}
+		for i, id := range ids {
+			_, existed := versions[id]
+			exp := final[id]
+			switch {
+			case exp == nil && existed:
+				err = tx.Delete(refs[i])
+			case exp == nil:
+				continue
+			case existed:
+				err = tx.Set(refs[i], *exp)
+			default:
+				err = tx.Create(refs[i], *exp)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WatchExperiences listens to the experiences collection with a snapshot
+// listener and reports every change made after the initial snapshot, by
+// this or any other instance. Previous is filled from the content seen by
+// the listener.
+func (r *ExperienceRepository) WatchExperiences(ctx context.Context, fn func(repository.ExperienceChange)) error {
+	it := r.col().Snapshots(ctx)
+	defer it.Stop()
+
+	known := map[string]models.Experience{}
+	initial := true
+	for {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		for _, change := range snap.Changes {
+			var exp models.Experience
+			if err := change.Doc.DataTo(&exp); err != nil {
+				return err
+			}
+			if exp.ID == "" {
+				exp.ID = change.Doc.Ref.ID
+			}
+			var previous *models.Experience
+			if old, ok := known[exp.ID]; ok {
+				previous = &old
+			}
+
+			event := repository.ExperienceChange{Experience: exp, Previous: previous}
+			switch change.Kind {
+			case firestore.DocumentAdded:
+				known[exp.ID] = exp
+				event.Kind = repository.ChangeCreated
+			case firestore.DocumentModified:
+				known[exp.ID] = exp
+				event.Kind = repository.ChangeUpdated
+			case firestore.DocumentRemoved:
+				delete(known, exp.ID)
+				event.Kind = repository.ChangeDeleted
+			}
+			if !initial {
+				fn(event)
+			}
+		}
+		initial = false
+	}
 }
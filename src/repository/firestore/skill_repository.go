@@ -0,0 +1,110 @@
+package firestorerepo
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const skillsCollection = "skills"
+
+// SkillRepository is the Firestore implementation of repository.SkillRepository.
+type SkillRepository struct {
+	client *firestore.Client
+}
+
+// NewSkillRepository creates a new Firestore-backed SkillRepository.
+func NewSkillRepository(client *firestore.Client) *SkillRepository {
+	return &SkillRepository{client: client}
+}
+
+func (r *SkillRepository) col() *firestore.CollectionRef {
+	return r.client.Collection(skillsCollection)
+}
+
+// List returns all skills.
+func (r *SkillRepository) List(ctx context.Context) ([]models.Skill, error) {
+	iter := r.col().Documents(ctx)
+	defer iter.Stop()
+
+	skills := make([]models.Skill, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var skill models.Skill
+		if err := doc.DataTo(&skill); err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
+
+// GetByID returns a skill by document ID.
+func (r *SkillRepository) GetByID(ctx context.Context, id string) (models.Skill, error) {
+	doc, err := r.col().Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return models.Skill{}, fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+		}
+		return models.Skill{}, err
+	}
+
+	var skill models.Skill
+	if err := doc.DataTo(&skill); err != nil {
+		return models.Skill{}, err
+	}
+	return skill, nil
+}
+
+// Create persists a new skill using its ID as the document key, failing
+// with ErrConflict if the document exists.
+func (r *SkillRepository) Create(ctx context.Context, skill models.Skill) error {
+	_, err := r.col().Doc(skill.ID).Create(ctx, skill)
+	if status.Code(err) == codes.AlreadyExists {
+		return fmt.Errorf("%w: skill %s already exists", repository.ErrConflict, skill.ID)
+	}
+	return err
+}
+
+// Update replaces an existing skill. Update with an Exists precondition
+// would require field paths, so the document is checked first.
+func (r *SkillRepository) Update(ctx context.Context, skill models.Skill) error {
+	docRef := r.col().Doc(skill.ID)
+	if _, err := docRef.Get(ctx); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: skill %s", repository.ErrNotFound, skill.ID)
+		}
+		return err
+	}
+
+	_, err := docRef.Set(ctx, skill)
+	return err
+}
+
+// Delete removes a skill by ID.
+func (r *SkillRepository) Delete(ctx context.Context, id string) error {
+	docRef := r.col().Doc(id)
+	if _, err := docRef.Get(ctx); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+		}
+		return err
+	}
+
+	_, err := docRef.Delete(ctx)
+	return err
+}
@@ -0,0 +1,81 @@
+package firestorerepo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"cloud.google.com/go/firestore"
+	"backend-yonathan/src/repository"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const tagAliasesCollection = "tagAliases"
+
+// tagAliasDoc is the stored shape of an alias. The document ID is the
+// path-escaped alias, since tags may contain "/".
+type tagAliasDoc struct {
+	Alias     string `firestore:"alias"`
+	Canonical string `firestore:"canonical"`
+}
+
+// TagAliasRepository is the Firestore implementation of repository.TagAliasRepository.
+type TagAliasRepository struct {
+	client *firestore.Client
+}
+
+// NewTagAliasRepository creates a new Firestore-backed TagAliasRepository.
+func NewTagAliasRepository(client *firestore.Client) *TagAliasRepository {
+	return &TagAliasRepository{client: client}
+}
+
+func (r *TagAliasRepository) doc(alias string) *firestore.DocumentRef {
+	return r.client.Collection(tagAliasesCollection).Doc(url.PathEscape(alias))
+}
+
+// ListAliases returns all aliases.
+func (r *TagAliasRepository) ListAliases(ctx context.Context) (map[string]string, error) {
+	iter := r.client.Collection(tagAliasesCollection).Documents(ctx)
+	defer iter.Stop()
+
+	aliases := map[string]string{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var item tagAliasDoc
+		if err := doc.DataTo(&item); err != nil {
+			return nil, err
+		}
+		aliases[item.Alias] = item.Canonical
+	}
+	return aliases, nil
+}
+
+// SaveAlias creates or replaces an alias.
+func (r *TagAliasRepository) SaveAlias(ctx context.Context, alias, canonical string) error {
+	_, err := r.doc(alias).Set(ctx, tagAliasDoc{Alias: alias, Canonical: canonical})
+	return err
+}
+
+// DeleteAlias removes an alias.
+func (r *TagAliasRepository) DeleteAlias(ctx context.Context, alias string) error {
+	docRef := r.doc(alias)
+	if _, err := docRef.Get(ctx); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: tag alias %s", repository.ErrNotFound, alias)
+		}
+		return err
+	}
+
+	_, err := docRef.Delete(ctx)
+	return err
+}
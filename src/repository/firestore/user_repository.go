@@ -16,6 +16,10 @@ import (
 
 const usersCollection = "users"
 
+// userEmailsCollection holds one document per registered email, keyed by the
+// email, reserving it for the user in its userId field.
+const userEmailsCollection = "user_emails"
+
 // UserRepository is the Firestore implementation of repository.UserRepository.
 type UserRepository struct {
 	client *firestore.Client
@@ -30,19 +34,136 @@ func (r *UserRepository) col() *firestore.CollectionRef {
 	return r.client.Collection(usersCollection)
 }
 
+func userData(user models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"userId":    user.UserId,
+		"email":     user.Email,
+		"password":  user.Password,
+		"username":  user.UserName,
+		"encrypted": user.Encrypted,
+	}
+}
+
+// CreateUser creates the user and its email reservation in one transaction.
+// Both are created with Create, which fails if the document exists, and the
+// transaction also queries users by email to catch accounts created before
+// reservations existed.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	conflict := fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		existing, err := tx.Documents(r.col().Where("email", "==", user.Email).Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return conflict
+		}
+		if err := tx.Create(r.client.Collection(userEmailsCollection).Doc(user.Email), map[string]interface{}{"userId": user.UserId}); err != nil {
+			return err
+		}
+		return tx.Create(r.col().Doc(user.UserId), userData(user))
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return models.User{}, conflict
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
 // SaveUser persists a user to Firestore. Generates a UserId if empty.
+// In the same transaction it reserves the user's email and, when the email
+// changed, releases the previous reservation. Taking the email of another
+// user returns an error wrapping ErrConflict.
 func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
 	if user.UserId == "" {
 		user.UserId = uuid.NewString()
 	}
+	conflict := fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	emails := r.client.Collection(userEmailsCollection)
 
-	_, err := r.col().Doc(user.UserId).Set(ctx, map[string]interface{}{
-		"userId":   user.UserId,
-		"email":    user.Email,
-		"password": user.Password,
-		"username": user.UserName,
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		previous := ""
+		current, err := tx.Get(r.col().Doc(user.UserId))
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var stored models.User
+			if err := current.DataTo(&stored); err != nil {
+				return err
+			}
+			previous = stored.Email
+		}
+
+		owners, err := tx.Documents(r.col().Where("email", "==", user.Email).Limit(2)).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			if owner.Ref.ID != user.UserId {
+				return conflict
+			}
+		}
+		reservation, err := tx.Get(emails.Doc(user.Email))
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if owner, _ := reservation.Data()["userId"].(string); owner != user.UserId {
+				return conflict
+			}
+		}
+		var released *firestore.DocumentSnapshot
+		if previous != "" && previous != user.Email {
+			released, err = tx.Get(emails.Doc(previous))
+			if err != nil && status.Code(err) != codes.NotFound {
+				return err
+			}
+		}
+
+		if err := tx.Set(emails.Doc(user.Email), map[string]interface{}{"userId": user.UserId}); err != nil {
+			return err
+		}
+		if released != nil && released.Exists() {
+			if owner, _ := released.Data()["userId"].(string); owner == user.UserId {
+				if err := tx.Delete(released.Ref); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Set(r.col().Doc(user.UserId), userData(user))
 	})
-	return err
+}
+
+// ListUsers returns all users ordered by ID.
+func (r *UserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	iter := r.col().Documents(ctx)
+	defer iter.Stop()
+
+	users := make([]models.User, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var user models.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	repository.SortUsers(users)
+	return users, nil
 }
 
 // GetUserByID fetches a user by document ID.
@@ -10,18 +10,91 @@ import (
 // ErrNotFound is returned when a requested resource does not exist.
 var ErrNotFound = errors.New("not found")
 
+// ErrConflict is returned when a write would violate a uniqueness
+// constraint, such as a second user with the same email.
+var ErrConflict = errors.New("conflict")
+
+// ErrVersionConflict is returned when a conditional write finds a stored
+// version different from the one the caller expected.
+var ErrVersionConflict = errors.New("version conflict")
+
 // UserRepository defines the data access contract for user persistence.
 type UserRepository interface {
+	// CreateUser stores a new user, generating its UserId when empty, and
+	// returns it. Checking the email and writing the user is atomic: an email
+	// that is already registered returns an error wrapping ErrConflict.
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	// SaveUser inserts or replaces the user with user.UserId, generating it
+	// when empty. Taking the email of another user returns an error wrapping
+	// ErrConflict; the previous email of the user becomes free.
 	SaveUser(ctx context.Context, user models.User) error
+	// GetUserByID and GetUserByEmail return an error wrapping ErrNotFound
+	// when no user matches.
 	GetUserByID(ctx context.Context, id string) (models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
 }
 
+// UserLister is implemented by user repositories that can enumerate every
+// user, which copying users between backends requires.
+type UserLister interface {
+	// ListUsers returns every user ordered by UserId.
+	ListUsers(ctx context.Context) ([]models.User, error)
+}
+
+// PositionUpdate sets the display position of one experience. A nil Pinned
+// keeps the current pin state.
+type PositionUpdate struct {
+	ID       string
+	Position int
+	Pinned   *bool
+}
+
 // ExperienceRepository defines the data access contract for experience/skill persistence.
 type ExperienceRepository interface {
+	// List returns every experience ordered by CreatedAt, then ID (see
+	// SortExperiences).
 	List(ctx context.Context) ([]models.Experience, error)
+	// GetByID returns an error wrapping ErrNotFound for an unknown ID.
 	GetByID(ctx context.Context, id string) (models.Experience, error)
 	Create(ctx context.Context, exp models.Experience) error
-	Update(ctx context.Context, exp models.Experience) error
+	// Update replaces an experience only if its stored Version equals
+	// expectedVersion, storing it with Version expectedVersion+1. Otherwise it
+	// returns an error wrapping ErrVersionConflict, or ErrNotFound for an
+	// unknown ID.
+	Update(ctx context.Context, exp models.Experience, expectedVersion int64) error
+	// Delete removes an experience only if its stored Version equals
+	// expectedVersion; otherwise it returns an error wrapping ErrVersionConflict,
+	// or ErrNotFound for an unknown ID.
+	Delete(ctx context.Context, id string, expectedVersion int64) error
+	// UpdatePositions applies all updates or none: if any ID does not exist
+	// it returns an error wrapping ErrNotFound and nothing is written. Each
+	// updated experience gets its Version incremented.
+	UpdatePositions(ctx context.Context, updates []PositionUpdate) error
+}
+
+// SkillRepository defines the data access contract for structured skill persistence.
+type SkillRepository interface {
+	List(ctx context.Context) ([]models.Skill, error)
+	GetByID(ctx context.Context, id string) (models.Skill, error)
+	// Create stores a new skill; an ID already stored returns an error
+	// wrapping ErrConflict.
+	Create(ctx context.Context, skill models.Skill) error
+	Update(ctx context.Context, skill models.Skill) error
 	Delete(ctx context.Context, id string) error
 }
+
+// TagAliasRepository defines the data access contract for tag aliases.
+// Aliases map a tag (e.g. "golang") to its canonical form (e.g. "go").
+type TagAliasRepository interface {
+	ListAliases(ctx context.Context) (map[string]string, error)
+	SaveAlias(ctx context.Context, alias, canonical string) error
+	DeleteAlias(ctx context.Context, alias string) error
+}
+
+// Repositories groups the repositories the API is wired with.
+type Repositories struct {
+	Users       UserRepository
+	Experiences ExperienceRepository
+	Skills      SkillRepository
+	TagAliases  TagAliasRepository
+}
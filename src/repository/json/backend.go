@@ -0,0 +1,40 @@
+package jsonrepo
+
+import (
+	"context"
+	"os"
+
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+)
+
+// The file backend stores each repository as a JSON file in a directory:
+// file:///var/data, file:./data, or file: for PORTFOLIO_DATA_DIR.
+func init() {
+	repository.Register("file", repository.Backend{
+		Users: func(_ context.Context, dsn repository.DSN) (repository.UserRepository, error) {
+			return newUserRepository(dsn.Location), nil
+		},
+		Experiences: func(_ context.Context, dsn repository.DSN) (repository.ExperienceRepository, error) {
+			return newExperienceRepository(dsn.Location), nil
+		},
+		Skills: func(_ context.Context, dsn repository.DSN) (repository.SkillRepository, error) {
+			return newSkillRepository(dsn.Location), nil
+		},
+		TagAliases: func(_ context.Context, dsn repository.DSN) (repository.TagAliasRepository, error) {
+			return newTagAliasRepository(dsn.Location), nil
+		},
+	})
+}
+
+// resolveDataDir returns dir, or PORTFOLIO_DATA_DIR / the default data
+// directory when dir is empty.
+func resolveDataDir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+	if dir = os.Getenv(constants.DataDirEnvVar); dir != "" {
+		return dir
+	}
+	return constants.DefaultDataDir
+}
@@ -0,0 +1,26 @@
+package jsonrepo
+
+import (
+	"testing"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+)
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return newUserRepository(t.TempDir())
+	})
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return newExperienceRepository(t.TempDir())
+	})
+}
+
+func TestSkillRepositoryConformance(t *testing.T) {
+	repotest.RunSkillRepositoryTests(t, func(t *testing.T) repository.SkillRepository {
+		return newSkillRepository(t.TempDir())
+	})
+}
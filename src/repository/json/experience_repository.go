@@ -2,9 +2,7 @@ package jsonrepo
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 
@@ -13,86 +11,67 @@ import (
 	"backend-yonathan/src/repository"
 )
 
-// Injectable file I/O vars (swap in tests).
-var (
-	readFileFunc = func(path string) ([]byte, error) {
-		return os.ReadFile(path)
-	}
-	writeFileFunc = func(path string, data []byte, perm os.FileMode) error {
-		return os.WriteFile(path, data, perm)
-	}
-	mkdirAllFunc = func(path string, perm os.FileMode) error {
-		return os.MkdirAll(path, perm)
-	}
-)
-
 // ExperienceRepository is the JSON-file implementation of repository.ExperienceRepository.
 type ExperienceRepository struct {
-	mu sync.RWMutex
+	mu      sync.RWMutex
+	dataDir string
+	file    *fileStore[[]models.Experience]
 }
 
 // NewExperienceRepository creates a new JSON-file-backed ExperienceRepository.
 func NewExperienceRepository() *ExperienceRepository {
-	return &ExperienceRepository{}
+	return newExperienceRepository("")
+}
+
+func newExperienceRepository(dataDir string) *ExperienceRepository {
+	return &ExperienceRepository{dataDir: dataDir, file: newFileStore(func(experiences []models.Experience) []string {
+		ids := make([]string, len(experiences))
+		for i, exp := range experiences {
+			ids[i] = exp.ID
+		}
+		return ids
+	})}
 }
 
 func (r *ExperienceRepository) filePath() string {
-	dataDir := os.Getenv(constants.DataDirEnvVar)
-	if dataDir == "" {
-		dataDir = constants.DefaultDataDir
-	}
-	return filepath.Join(dataDir, constants.ExperiencesFilename)
+	return filepath.Join(resolveDataDir(r.dataDir), constants.ExperiencesFilename)
 }
 
-func (r *ExperienceRepository) load() ([]models.Experience, error) {
-	data, err := readFileFunc(r.filePath())
+// load returns a copy of the stored experiences that the caller may modify.
+func (r *ExperienceRepository) load(ctx context.Context) ([]models.Experience, error) {
+	experiences, _, err := r.file.snapshot(ctx, r.filePath())
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []models.Experience{}, nil
-		}
-		return nil, err
-	}
-	var experiences []models.Experience
-	if len(data) == 0 {
-		return []models.Experience{}, nil
-	}
-	if err := json.Unmarshal(data, &experiences); err != nil {
 		return nil, err
 	}
-	return experiences, nil
+	return append(make([]models.Experience, 0, len(experiences)), experiences...), nil
 }
 
-func (r *ExperienceRepository) save(experiences []models.Experience) error {
-	fp := r.filePath()
-	if err := mkdirAllFunc(filepath.Dir(fp), constants.DirPermission); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(experiences, "", "  ")
-	if err != nil {
-		return err
-	}
-	return writeFileFunc(fp, data, constants.FilePermission)
+func (r *ExperienceRepository) save(ctx context.Context, experiences []models.Experience) error {
+	return r.file.write(ctx, r.filePath(), experiences)
 }
 
-// List returns all experiences.
+// List returns all experiences, ordered by creation date and ID.
 func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.load()
+	experiences, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repository.SortExperiences(experiences)
+	return experiences, nil
 }
 
 // GetByID returns an experience by ID.
 func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	experiences, err := r.load()
+	experiences, index, err := r.file.snapshot(ctx, r.filePath())
 	if err != nil {
 		return models.Experience{}, err
 	}
-	for _, exp := range experiences {
-		if exp.ID == id {
-			return exp, nil
-		}
+	if i, ok := index[id]; ok {
+		return experiences[i], nil
 	}
 	return models.Experience{}, fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
 }
@@ -101,36 +80,42 @@ func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.E
 func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	experiences, err := r.load()
+	experiences, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
 	experiences = append(experiences, exp)
-	return r.save(experiences)
+	return r.save(ctx, experiences)
 }
 
-// Update replaces an existing experience by ID and persists.
-func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience) error {
+// Update replaces an existing experience by ID and persists, provided its
+// stored version still matches expectedVersion.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	experiences, err := r.load()
+	experiences, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
 	for i, item := range experiences {
 		if item.ID == exp.ID {
+			if item.Version != expectedVersion {
+				return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, exp.ID)
+			}
+			exp.Version = expectedVersion + 1
 			experiences[i] = exp
-			return r.save(experiences)
+			return r.save(ctx, experiences)
 		}
 	}
 	return fmt.Errorf("%w: experience %s", repository.ErrNotFound, exp.ID)
 }
 
-// Delete removes an experience by ID and persists.
-func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
+// Delete removes an experience by ID and persists, provided its stored
+// version still matches expectedVersion.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	experiences, err := r.load()
+	experiences, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
@@ -138,6 +123,9 @@ func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
 	found := false
 	for _, item := range experiences {
 		if item.ID == id {
+			if item.Version != expectedVersion {
+				return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, id)
+			}
 			found = true
 			continue
 		}
@@ -146,5 +134,76 @@ func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
 	if !found {
 		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
 	}
-	return r.save(filtered)
+	return r.save(ctx, filtered)
+}
+
+// UpdatePositions sets position and pin state of several experiences and
+// persists them with a single file write.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	experiences, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(experiences))
+	for i, item := range experiences {
+		index[item.ID] = i
+	}
+	for _, u := range updates {
+		if _, ok := index[u.ID]; !ok {
+			return fmt.Errorf("%w: experience %s", repository.ErrNotFound, u.ID)
+		}
+	}
+	for _, u := range updates {
+		item := &experiences[index[u.ID]]
+		item.Position = u.Position
+		if u.Pinned != nil {
+			item.Pinned = *u.Pinned
+		}
+		item.Version++
+	}
+	return r.save(ctx, experiences)
+}
+
+// ApplyBatch validates every write against the stored versions and persists
+// the result with a single file rewrite, so either all writes succeed or the
+// file is left untouched.
+func (r *ExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	experiences, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	versions := make(map[string]int64, len(experiences))
+	for _, item := range experiences {
+		versions[item.ID] = item.Version
+	}
+	if err := repository.CheckBatch(versions, writes); err != nil {
+		return err
+	}
+	for _, w := range writes {
+		exp := w.Experience
+		switch w.Kind {
+		case repository.WriteCreate:
+			experiences = append(experiences, exp)
+		case repository.WriteUpdate:
+			exp.Version = w.ExpectedVersion + 1
+			for i := range experiences {
+				if experiences[i].ID == exp.ID {
+					experiences[i] = exp
+					break
+				}
+			}
+		case repository.WriteDelete:
+			for i := range experiences {
+				if experiences[i].ID == exp.ID {
+					experiences = append(experiences[:i], experiences[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return r.save(ctx, experiences)
 }
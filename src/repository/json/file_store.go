@@ -0,0 +1,272 @@
+package jsonrepo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"backend-yonathan/src/pkg/constants"
+)
+
+// Injectable file I/O vars (swap in tests).
+var (
+	readFileFunc = func(path string) ([]byte, error) {
+		return os.ReadFile(path)
+	}
+	writeFileFunc = writeFileAtomic
+	mkdirAllFunc  = func(path string, perm os.FileMode) error {
+		return os.MkdirAll(path, perm)
+	}
+	statFunc = func(path string) (os.FileInfo, error) {
+		return os.Stat(path)
+	}
+	renameFunc = func(oldPath, newPath string) error {
+		return os.Rename(oldPath, newPath)
+	}
+	syncFileFunc = func(f *os.File) error {
+		return f.Sync()
+	}
+	lockFileFunc = lockFile
+)
+
+// writeFileAtomic writes data to a temporary file in the target directory,
+// fsyncs it and renames it over path, so readers and crashes only ever see
+// the old or the new content.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := syncFileFunc(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := renameFunc(tmpPath, path); err != nil {
+		return err
+	}
+	committed = true
+
+	// Persist the rename itself. Not every platform can fsync a directory,
+	// so failures here are ignored.
+	if d, err := os.Open(dir); err == nil {
+		_ = syncFileFunc(d)
+		_ = d.Close()
+	}
+	return nil
+}
+
+// backupPath returns the path of the n-th most recent backup of path.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+// fileStore caches the decoded content of one JSON file. Reads are served
+// from memory until the file's modification time or size changes; a changed
+// file whose content hash is unchanged is not decoded again. Writes are
+// atomic and rotate the previous content into numbered backups, which are
+// used to recover when the file no longer parses.
+//
+// Values returned by snapshot are shared with the cache and must not be
+// modified; callers clone before changing them.
+type fileStore[T any] struct {
+	mu sync.Mutex
+	// keys, when set, lists the key of each element of a value in order and
+	// is used to build the lookup index returned by snapshot.
+	keys func(T) []string
+
+	path    string
+	loaded  bool
+	value   T
+	index   map[string]int
+	raw     []byte
+	sum     [sha256.Size]byte
+	modTime time.Time
+	size    int64
+}
+
+func newFileStore[T any](keys func(T) []string) *fileStore[T] {
+	return &fileStore[T]{keys: keys}
+}
+
+// snapshot returns the current value of the file at path and its index. It
+// fails without reading when ctx is already done.
+func (s *fileStore[T]) snapshot(ctx context.Context, path string) (T, map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, nil, err
+	}
+	if err := s.refresh(path); err != nil {
+		var zero T
+		return zero, nil, err
+	}
+	return s.value, s.index, nil
+}
+
+// invalidate makes the next snapshot re-read the file even if its
+// modification time and size look unchanged. Writers holding the file lock
+// use it so that an edit by another process is never missed.
+func (s *fileStore[T]) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modTime, s.size = time.Time{}, -1
+}
+
+// write persists value to path. The store keeps value as its cached state,
+// so the caller must not modify it afterwards. Once ctx is done nothing is
+// written; a write that has started always completes.
+func (s *fileStore[T]) write(ctx context.Context, path string, value T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := mkdirAllFunc(filepath.Dir(path), constants.DirPermission); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.path == path && s.loaded && len(s.raw) > 0 {
+		if err := s.rotateBackups(path); err != nil {
+			return fmt.Errorf("backup %s: %w", path, err)
+		}
+	}
+	if err := writeFileFunc(path, data, constants.FilePermission); err != nil {
+		return err
+	}
+	s.remember(path, value, data)
+	return nil
+}
+
+// rotateBackups shifts <path>.bak.N-1 … .bak.1 up by one and stores the
+// last content read or written as <path>.bak.1.
+func (s *fileStore[T]) rotateBackups(path string) error {
+	count := constants.JSONBackupCount()
+	if count == 0 {
+		return nil
+	}
+	for n := count - 1; n >= 1; n-- {
+		if err := renameFunc(backupPath(path, n), backupPath(path, n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return writeFileFunc(backupPath(path, 1), s.raw, constants.FilePermission)
+}
+
+// remember makes value, encoded as data, the cached content of path.
+func (s *fileStore[T]) remember(path string, value T, data []byte) {
+	s.path = path
+	s.loaded = true
+	s.value = value
+	s.raw = data
+	s.sum = sha256.Sum256(data)
+	s.index = nil
+	if s.keys != nil {
+		keys := s.keys(value)
+		s.index = make(map[string]int, len(keys))
+		for i, key := range keys {
+			s.index[key] = i
+		}
+	}
+	s.modTime, s.size = time.Time{}, -1
+	if info, err := statFunc(path); err == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
+	}
+}
+
+// refresh reloads the cache when the file at path changed since it was last
+// read or written.
+func (s *fileStore[T]) refresh(path string) error {
+	info, err := statFunc(path)
+	if errors.Is(err, os.ErrNotExist) {
+		var zero T
+		s.remember(path, zero, nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.loaded && s.path == path && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	data, err := readFileFunc(path)
+	if err != nil {
+		return err
+	}
+	if s.loaded && s.path == path && sha256.Sum256(data) == s.sum {
+		s.modTime, s.size = info.ModTime(), info.Size()
+		return nil
+	}
+	value, err := decode[T](data)
+	if err != nil {
+		return s.recover(path, err)
+	}
+	s.remember(path, value, data)
+	return nil
+}
+
+func decode[T any](data []byte) (T, error) {
+	var value T
+	if len(bytes.TrimSpace(data)) == 0 {
+		return value, nil
+	}
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// recover replaces a file that failed to parse with its most recent valid
+// backup. The corrupt file is kept as <path>.corrupt for inspection.
+func (s *fileStore[T]) recover(path string, parseErr error) error {
+	for n := 1; n <= constants.JSONBackupCount(); n++ {
+		backup := backupPath(path, n)
+		data, err := readFileFunc(backup)
+		if err != nil {
+			continue
+		}
+		value, err := decode[T](data)
+		if err != nil {
+			continue
+		}
+		if err := renameFunc(path, path+".corrupt"); err != nil {
+			return fmt.Errorf("%s: %w (keeping corrupt file failed: %v)", path, parseErr, err)
+		}
+		if err := writeFileFunc(path, data, constants.FilePermission); err != nil {
+			return fmt.Errorf("%s: %w (restoring %s failed: %v)", path, parseErr, backup, err)
+		}
+		log.Printf("jsonrepo: %s is corrupt (%v); restored from %s", path, parseErr, backup)
+		s.remember(path, value, data)
+		return nil
+	}
+	return fmt.Errorf("%s: %w", path, parseErr)
+}
@@ -0,0 +1,176 @@
+package jsonrepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+)
+
+func createExperience(t *testing.T, repo *ExperienceRepository, id string) {
+	t.Helper()
+	if err := repo.Create(context.Background(), models.Experience{ID: id, Title: "Titulo " + id, Version: 1}); err != nil {
+		t.Fatalf("Create %s: %v", id, err)
+	}
+}
+
+func TestFileStoreServesReadsFromCache(t *testing.T) {
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	createExperience(t, repo, "a")
+
+	original := readFileFunc
+	defer func() { readFileFunc = original }()
+	reads := 0
+	readFileFunc = func(path string) ([]byte, error) {
+		reads++
+		return original(path)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.GetByID(context.Background(), "a"); err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if _, err := repo.List(context.Background()); err != nil {
+			t.Fatalf("List: %v", err)
+		}
+	}
+	if reads != 0 {
+		t.Errorf("file read %d times, want 0 after the repository wrote it", reads)
+	}
+}
+
+func TestFileStoreDetectsExternalEdits(t *testing.T) {
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	createExperience(t, repo, "a")
+
+	path := filepath.Join(dir, constants.ExperiencesFilename)
+	if err := os.WriteFile(path, []byte(`[{"id":"b","title":"Editado a mano","version":1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make the change visible even on filesystems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetByID(context.Background(), "b")
+	if err != nil || got.Title != "Editado a mano" {
+		t.Fatalf("GetByID after external edit = %+v, %v", got, err)
+	}
+}
+
+func TestFileStoreRotatesBackups(t *testing.T) {
+	t.Setenv("PORTFOLIO_JSON_BACKUPS", "2")
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		createExperience(t, repo, id)
+	}
+
+	path := filepath.Join(dir, constants.ExperiencesFilename)
+	for n, want := range map[int]int{1: 3, 2: 2} {
+		data, err := os.ReadFile(backupPath(path, n))
+		if err != nil {
+			t.Fatalf("backup %d: %v", n, err)
+		}
+		experiences, err := decode[[]models.Experience](data)
+		if err != nil || len(experiences) != want {
+			t.Errorf("backup %d has %d experiences (%v), want %d", n, len(experiences), err, want)
+		}
+	}
+	if _, err := os.Stat(backupPath(path, 3)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backup 3 should not exist with PORTFOLIO_JSON_BACKUPS=2: %v", err)
+	}
+}
+
+func TestFileStoreRecoversFromBackup(t *testing.T) {
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	createExperience(t, repo, "a")
+	createExperience(t, repo, "b")
+
+	// A fresh repository simulates a restart after the file was truncated.
+	path := filepath.Join(dir, constants.ExperiencesFilename)
+	if err := os.WriteFile(path, []byte(`[{"id":"a","ti`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	restarted := newExperienceRepository(dir)
+	list, err := restarted.List(context.Background())
+	if err != nil || len(list) != 1 || list[0].ID != "a" {
+		t.Fatalf("List after recovery = %+v, %v", list, err)
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("corrupt file not kept: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if _, decodeErr := decode[[]models.Experience](data); err != nil || decodeErr != nil {
+		t.Errorf("data file not restored: %v, %v", err, decodeErr)
+	}
+}
+
+func TestFileStoreFailsWithoutValidBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, constants.ExperiencesFilename)
+	if err := os.WriteFile(path, []byte(`{not json`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newExperienceRepository(dir).List(context.Background()); err == nil {
+		t.Fatal("expected a parse error without backups")
+	}
+}
+
+func TestFileStoreFailedWriteKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	createExperience(t, repo, "a")
+
+	original := renameFunc
+	defer func() { renameFunc = original }()
+	renameFunc = func(oldPath, newPath string) error {
+		if filepath.Base(newPath) == constants.ExperiencesFilename {
+			return errors.New("disk full")
+		}
+		return original(oldPath, newPath)
+	}
+	if err := repo.Create(context.Background(), models.Experience{ID: "b", Version: 1}); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	renameFunc = original
+
+	list, err := newExperienceRepository(dir).List(context.Background())
+	if err != nil || len(list) != 1 || list[0].ID != "a" {
+		t.Fatalf("file after failed write = %+v, %v", list, err)
+	}
+	if _, err := repo.GetByID(context.Background(), "b"); err == nil {
+		t.Error("failed write should not reach the cache")
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" && filepath.Ext(entry.Name()) != ".1" {
+			t.Errorf("leftover file %s", entry.Name())
+		}
+	}
+}
+
+func TestFileStoreSkipsWritesAfterCancel(t *testing.T) {
+	dir := t.TempDir()
+	repo := newExperienceRepository(dir)
+	createExperience(t, repo, "a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := repo.Create(ctx, models.Experience{ID: "b", Version: 1}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Create with cancelled context = %v, want context.Canceled", err)
+	}
+	list, err := repo.List(context.Background())
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List = %v, %v; want only the first experience", list, err)
+	}
+}
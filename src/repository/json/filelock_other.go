@@ -0,0 +1,9 @@
+//go:build !unix
+
+package jsonrepo
+
+// lockFile is a no-op where flock is unavailable; writes are still
+// serialized within the process by the repository mutex.
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
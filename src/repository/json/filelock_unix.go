@@ -0,0 +1,28 @@
+//go:build unix
+
+package jsonrepo
+
+import (
+	"os"
+	"syscall"
+
+	"backend-yonathan/src/pkg/constants"
+)
+
+// lockFile takes an exclusive advisory lock on <path>.lock, shared with
+// other processes using the same data directory. The kernel releases it if
+// the process dies.
+func lockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, constants.FilePermission)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,130 @@
+package jsonrepo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+)
+
+// SkillRepository is the JSON-file implementation of repository.SkillRepository.
+type SkillRepository struct {
+	mu      sync.RWMutex
+	dataDir string
+	file    *fileStore[[]models.Skill]
+}
+
+// NewSkillRepository creates a new JSON-file-backed SkillRepository.
+func NewSkillRepository() *SkillRepository {
+	return newSkillRepository("")
+}
+
+func newSkillRepository(dataDir string) *SkillRepository {
+	return &SkillRepository{dataDir: dataDir, file: newFileStore(func(skills []models.Skill) []string {
+		ids := make([]string, len(skills))
+		for i, item := range skills {
+			ids[i] = item.ID
+		}
+		return ids
+	})}
+}
+
+func (r *SkillRepository) filePath() string {
+	return filepath.Join(resolveDataDir(r.dataDir), constants.SkillsFilename)
+}
+
+// load returns a copy of the stored skills that the caller may modify.
+func (r *SkillRepository) load(ctx context.Context) ([]models.Skill, error) {
+	skills, _, err := r.file.snapshot(ctx, r.filePath())
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]models.Skill, 0, len(skills)), skills...), nil
+}
+
+func (r *SkillRepository) save(ctx context.Context, skills []models.Skill) error {
+	return r.file.write(ctx, r.filePath(), skills)
+}
+
+// List returns all skills.
+func (r *SkillRepository) List(ctx context.Context) ([]models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.load(ctx)
+}
+
+// GetByID returns a skill by ID.
+func (r *SkillRepository) GetByID(ctx context.Context, id string) (models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	skills, index, err := r.file.snapshot(ctx, r.filePath())
+	if err != nil {
+		return models.Skill{}, err
+	}
+	if i, ok := index[id]; ok {
+		return skills[i], nil
+	}
+	return models.Skill{}, fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+}
+
+// Create appends a new skill and persists, failing with ErrConflict if the
+// ID is taken.
+func (r *SkillRepository) Create(ctx context.Context, skill models.Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	skills, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range skills {
+		if item.ID == skill.ID {
+			return fmt.Errorf("%w: skill %s already exists", repository.ErrConflict, skill.ID)
+		}
+	}
+	skills = append(skills, skill)
+	return r.save(ctx, skills)
+}
+
+// Update replaces an existing skill by ID and persists.
+func (r *SkillRepository) Update(ctx context.Context, skill models.Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	skills, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	for i, item := range skills {
+		if item.ID == skill.ID {
+			skills[i] = skill
+			return r.save(ctx, skills)
+		}
+	}
+	return fmt.Errorf("%w: skill %s", repository.ErrNotFound, skill.ID)
+}
+
+// Delete removes a skill by ID and persists.
+func (r *SkillRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	skills, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	filtered := make([]models.Skill, 0, len(skills))
+	found := false
+	for _, item := range skills {
+		if item.ID == id {
+			found = true
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	if !found {
+		return fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+	}
+	return r.save(ctx, filtered)
+}
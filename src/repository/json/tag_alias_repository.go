@@ -0,0 +1,83 @@
+package jsonrepo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"backend-yonathan/src/pkg/constants"
+	"backend-yonathan/src/repository"
+)
+
+// TagAliasRepository is the JSON-file implementation of repository.TagAliasRepository.
+// Aliases are stored as a single object: {"alias": "canonical"}.
+type TagAliasRepository struct {
+	mu      sync.RWMutex
+	dataDir string
+	file    *fileStore[map[string]string]
+}
+
+// NewTagAliasRepository creates a new JSON-file-backed TagAliasRepository.
+func NewTagAliasRepository() *TagAliasRepository {
+	return newTagAliasRepository("")
+}
+
+func newTagAliasRepository(dataDir string) *TagAliasRepository {
+	return &TagAliasRepository{dataDir: dataDir, file: newFileStore[map[string]string](nil)}
+}
+
+func (r *TagAliasRepository) filePath() string {
+	return filepath.Join(resolveDataDir(r.dataDir), constants.TagAliasesFilename)
+}
+
+// load returns a copy of the stored aliases that the caller may modify.
+func (r *TagAliasRepository) load(ctx context.Context) (map[string]string, error) {
+	stored, _, err := r.file.snapshot(ctx, r.filePath())
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string, len(stored))
+	for alias, canonical := range stored {
+		aliases[alias] = canonical
+	}
+	return aliases, nil
+}
+
+func (r *TagAliasRepository) save(ctx context.Context, aliases map[string]string) error {
+	return r.file.write(ctx, r.filePath(), aliases)
+}
+
+// ListAliases returns all aliases.
+func (r *TagAliasRepository) ListAliases(ctx context.Context) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.load(ctx)
+}
+
+// SaveAlias creates or replaces an alias and persists.
+func (r *TagAliasRepository) SaveAlias(ctx context.Context, alias, canonical string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aliases, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	aliases[alias] = canonical
+	return r.save(ctx, aliases)
+}
+
+// DeleteAlias removes an alias and persists.
+func (r *TagAliasRepository) DeleteAlias(ctx context.Context, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aliases, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[alias]; !ok {
+		return fmt.Errorf("%w: tag alias %s", repository.ErrNotFound, alias)
+	}
+	delete(aliases, alias)
+	return r.save(ctx, aliases)
+}
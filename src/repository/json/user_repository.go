@@ -2,9 +2,7 @@ package jsonrepo
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 
@@ -17,86 +15,142 @@ import (
 
 // UserRepository is the JSON-file implementation of repository.UserRepository.
 type UserRepository struct {
-	mu sync.RWMutex
+	mu      sync.RWMutex
+	dataDir string
+	file    *fileStore[[]models.User]
 }
 
 // NewUserRepository creates a new JSON-file-backed UserRepository.
 func NewUserRepository() *UserRepository {
-	return &UserRepository{}
+	return newUserRepository("")
+}
+
+func newUserRepository(dataDir string) *UserRepository {
+	return &UserRepository{dataDir: dataDir, file: newFileStore(func(users []models.User) []string {
+		ids := make([]string, len(users))
+		for i, item := range users {
+			ids[i] = item.UserId
+		}
+		return ids
+	})}
 }
 
 func (r *UserRepository) filePath() string {
-	dataDir := os.Getenv(constants.DataDirEnvVar)
-	if dataDir == "" {
-		dataDir = constants.DefaultDataDir
-	}
-	return filepath.Join(dataDir, constants.UsersFilename)
+	return filepath.Join(resolveDataDir(r.dataDir), constants.UsersFilename)
 }
 
-func (r *UserRepository) load() ([]models.User, error) {
-	data, err := readFileFunc(r.filePath())
+// load returns a copy of the stored users that the caller may modify.
+func (r *UserRepository) load(ctx context.Context) ([]models.User, error) {
+	users, _, err := r.file.snapshot(ctx, r.filePath())
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []models.User{}, nil
-		}
-		return nil, err
-	}
-	var users []models.User
-	if len(data) == 0 {
-		return []models.User{}, nil
-	}
-	if err := json.Unmarshal(data, &users); err != nil {
 		return nil, err
 	}
-	return users, nil
+	return append(make([]models.User, 0, len(users)), users...), nil
+}
+
+func (r *UserRepository) save(ctx context.Context, users []models.User) error {
+	return r.file.write(ctx, r.filePath(), users)
 }
 
-func (r *UserRepository) save(users []models.User) error {
+// CreateUser stores a new user unless its email is already registered. The
+// check and the write run under the users file lock, so concurrent
+// registrations, even from other processes, cannot both succeed.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	fp := r.filePath()
 	if err := mkdirAllFunc(filepath.Dir(fp), constants.DirPermission); err != nil {
-		return err
+		return models.User{}, err
 	}
-	data, err := json.MarshalIndent(users, "", "  ")
+	unlock, err := lockFileFunc(fp)
 	if err != nil {
-		return err
+		return models.User{}, err
+	}
+	defer unlock()
+
+	r.file.invalidate()
+	users, err := r.load(ctx)
+	if err != nil {
+		return models.User{}, err
 	}
-	return writeFileFunc(fp, data, constants.FilePermission)
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	for _, u := range users {
+		if u.Email == user.Email {
+			return models.User{}, fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+		}
+		if u.UserId == user.UserId {
+			return models.User{}, fmt.Errorf("%w: user %s already exists", repository.ErrConflict, user.UserId)
+		}
+	}
+	if err := r.save(ctx, append(users, user)); err != nil {
+		return models.User{}, err
+	}
+	return user, nil
 }
 
 // SaveUser persists a user. If UserId is empty a new UUID is generated.
-// If a user with the same ID already exists it is replaced.
+// If a user with the same ID already exists it is replaced. Taking the email
+// of another user returns an error wrapping ErrConflict.
 func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if user.UserId == "" {
 		user.UserId = uuid.NewString()
 	}
-	users, err := r.load()
+	fp := r.filePath()
+	if err := mkdirAllFunc(filepath.Dir(fp), constants.DirPermission); err != nil {
+		return err
+	}
+	unlock, err := lockFileFunc(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.file.invalidate()
+	users, err := r.load(ctx)
 	if err != nil {
 		return err
 	}
+	for _, u := range users {
+		if u.Email == user.Email && u.UserId != user.UserId {
+			return fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+		}
+	}
 	for i, u := range users {
 		if u.UserId == user.UserId {
 			users[i] = user
-			return r.save(users)
+			return r.save(ctx, users)
 		}
 	}
 	users = append(users, user)
-	return r.save(users)
+	return r.save(ctx, users)
+}
+
+// ListUsers returns all users ordered by ID.
+func (r *UserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repository.SortUsers(users)
+	return users, nil
 }
 
 // GetUserByID returns the user with the given ID.
 func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	users, err := r.load()
+	users, index, err := r.file.snapshot(ctx, r.filePath())
 	if err != nil {
 		return models.User{}, err
 	}
-	for _, u := range users {
-		if u.UserId == id {
-			return u, nil
-		}
+	if i, ok := index[id]; ok {
+		return users[i], nil
 	}
 	return models.User{}, fmt.Errorf("%w: user %s", repository.ErrNotFound, id)
 }
@@ -105,7 +159,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.Use
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	users, err := r.load()
+	users, _, err := r.file.snapshot(ctx, r.filePath())
 	if err != nil {
 		return models.User{}, err
 	}
@@ -0,0 +1,65 @@
+package jsonrepo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+func TestCreateUserRejectsDuplicateEmailConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	// Two repositories over the same directory behave like two processes.
+	repos := []*UserRepository{newUserRepository(dir), newUserRepository(dir)}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(repo *UserRepository) {
+			defer wg.Done()
+			_, err := repo.CreateUser(context.Background(), models.User{Email: "ana@example.com", UserName: "ana"})
+			errs <- err
+		}(repos[i%2])
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, repository.ErrConflict):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("created %d users, want 1", created)
+	}
+	if _, err := newUserRepository(dir).GetUserByEmail(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+}
+
+func TestSaveUserRejectsEmailOfAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	repo := newUserRepository(t.TempDir())
+	first, err := repo.CreateUser(ctx, models.User{Email: "ana@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.UserId == "" {
+		t.Fatal("CreateUser should return the generated UserId")
+	}
+	if err := repo.SaveUser(ctx, models.User{UserId: "other", Email: "ana@example.com"}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("SaveUser error = %v, want ErrConflict", err)
+	}
+	first.UserName = "ana maria"
+	if err := repo.SaveUser(ctx, first); err != nil {
+		t.Fatalf("SaveUser of the owner: %v", err)
+	}
+}
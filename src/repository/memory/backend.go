@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+
+	"backend-yonathan/src/repository"
+)
+
+// The memory backend (memory:) keeps data in process memory only; it is
+// meant for local development and tests. A location names a seed file whose
+// users and experiences are loaded when the store opens, e.g.
+// memory:./seed.json.
+func init() {
+	repository.Register("memory", repository.Backend{
+		Users: func(ctx context.Context, dsn repository.DSN) (repository.UserRepository, error) {
+			repo := NewUserRepository()
+			if dsn.Location == "" {
+				return repo, nil
+			}
+			seed, err := loadSeed(dsn.Location)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range seed.Users {
+				if err := repo.SaveUser(ctx, user); err != nil {
+					return nil, err
+				}
+			}
+			return repo, nil
+		},
+		Experiences: func(ctx context.Context, dsn repository.DSN) (repository.ExperienceRepository, error) {
+			repo := NewExperienceRepository()
+			if dsn.Location == "" {
+				return repo, nil
+			}
+			seed, err := loadSeed(dsn.Location)
+			if err != nil {
+				return nil, err
+			}
+			for _, exp := range seed.Experiences {
+				if err := repo.Create(ctx, exp); err != nil {
+					return nil, err
+				}
+			}
+			return repo, nil
+		},
+		Skills: func(context.Context, repository.DSN) (repository.SkillRepository, error) {
+			return NewSkillRepository(), nil
+		},
+		TagAliases: func(context.Context, repository.DSN) (repository.TagAliasRepository, error) {
+			return NewTagAliasRepository(), nil
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"testing"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+)
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository()
+	})
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return NewExperienceRepository()
+	})
+}
+
+func TestSkillRepositoryConformance(t *testing.T) {
+	repotest.RunSkillRepositoryTests(t, func(t *testing.T) repository.SkillRepository {
+		return NewSkillRepository()
+	})
+}
@@ -23,7 +23,7 @@ func NewExperienceRepository() *ExperienceRepository {
 	}
 }
 
-// List returns all experiences in insertion order.
+// List returns all experiences, ordered by creation date and ID.
 func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -33,6 +33,7 @@ func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, e
 			result = append(result, exp)
 		}
 	}
+	repository.SortExperiences(result)
 	return result, nil
 }
 
@@ -56,24 +57,33 @@ func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience
 	return nil
 }
 
-// Update replaces an existing experience in memory.
-func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience) error {
+// Update replaces an existing experience in memory if its version matches expectedVersion.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.experiences[exp.ID]; !ok {
+	current, ok := r.experiences[exp.ID]
+	if !ok {
 		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, exp.ID)
 	}
+	if current.Version != expectedVersion {
+		return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, exp.ID)
+	}
+	exp.Version = expectedVersion + 1
 	r.experiences[exp.ID] = exp
 	return nil
 }
 
-// Delete removes an experience from memory.
-func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
+// Delete removes an experience from memory if its version matches expectedVersion.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.experiences[id]; !ok {
+	current, ok := r.experiences[id]
+	if !ok {
 		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
 	}
+	if current.Version != expectedVersion {
+		return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, id)
+	}
 	delete(r.experiences, id)
 	newOrder := make([]string, 0, len(r.order)-1)
 	for _, oid := range r.order {
@@ -84,3 +94,59 @@ func (r *ExperienceRepository) Delete(ctx context.Context, id string) error {
 	r.order = newOrder
 	return nil
 }
+
+// UpdatePositions sets position and pin state of several experiences under a single lock.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range updates {
+		if _, ok := r.experiences[u.ID]; !ok {
+			return fmt.Errorf("%w: experience %s", repository.ErrNotFound, u.ID)
+		}
+	}
+	for _, u := range updates {
+		exp := r.experiences[u.ID]
+		exp.Position = u.Position
+		if u.Pinned != nil {
+			exp.Pinned = *u.Pinned
+		}
+		exp.Version++
+		r.experiences[u.ID] = exp
+	}
+	return nil
+}
+
+// ApplyBatch validates every write against the stored versions and applies
+// them under a single lock, so either all writes succeed or none is applied.
+func (r *ExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := make(map[string]int64, len(r.experiences))
+	for id, exp := range r.experiences {
+		versions[id] = exp.Version
+	}
+	if err := repository.CheckBatch(versions, writes); err != nil {
+		return err
+	}
+	for _, w := range writes {
+		exp := w.Experience
+		switch w.Kind {
+		case repository.WriteCreate:
+			r.experiences[exp.ID] = exp
+			r.order = append(r.order, exp.ID)
+		case repository.WriteUpdate:
+			exp.Version = w.ExpectedVersion + 1
+			r.experiences[exp.ID] = exp
+		case repository.WriteDelete:
+			delete(r.experiences, exp.ID)
+			newOrder := make([]string, 0, len(r.order))
+			for _, oid := range r.order {
+				if oid != exp.ID {
+					newOrder = append(newOrder, oid)
+				}
+			}
+			r.order = newOrder
+		}
+	}
+	return nil
+}
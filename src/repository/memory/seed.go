@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	models "backend-yonathan/src/models"
+)
+
+// seedFile is the content of a memory seed file.
+type seedFile struct {
+	Users       []models.User       `json:"users"`
+	Experiences []models.Experience `json:"experiences"`
+}
+
+// loadSeed reads the seed file at path.
+func loadSeed(path string) (seedFile, error) {
+	var seed seedFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return seed, fmt.Errorf("memory seed: %w", err)
+	}
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return seed, fmt.Errorf("memory seed %s: %w", path, err)
+	}
+	return seed, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+// SkillRepository is an in-memory implementation of repository.SkillRepository for tests.
+type SkillRepository struct {
+	mu     sync.RWMutex
+	skills map[string]models.Skill
+	order  []string // preserves insertion order for List
+}
+
+// NewSkillRepository creates an empty in-memory SkillRepository.
+func NewSkillRepository() *SkillRepository {
+	return &SkillRepository{
+		skills: make(map[string]models.Skill),
+	}
+}
+
+// List returns all skills in insertion order.
+func (r *SkillRepository) List(ctx context.Context) ([]models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]models.Skill, 0, len(r.order))
+	for _, id := range r.order {
+		if skill, ok := r.skills[id]; ok {
+			result = append(result, skill)
+		}
+	}
+	return result, nil
+}
+
+// GetByID returns a skill by ID.
+func (r *SkillRepository) GetByID(ctx context.Context, id string) (models.Skill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	skill, ok := r.skills[id]
+	if !ok {
+		return models.Skill{}, fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+	}
+	return skill, nil
+}
+
+// Create stores a new skill in memory, failing with ErrConflict if the ID
+// is taken.
+func (r *SkillRepository) Create(ctx context.Context, skill models.Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.skills[skill.ID]; ok {
+		return fmt.Errorf("%w: skill %s already exists", repository.ErrConflict, skill.ID)
+	}
+	r.skills[skill.ID] = skill
+	r.order = append(r.order, skill.ID)
+	return nil
+}
+
+// Update replaces an existing skill in memory.
+func (r *SkillRepository) Update(ctx context.Context, skill models.Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.skills[skill.ID]; !ok {
+		return fmt.Errorf("%w: skill %s", repository.ErrNotFound, skill.ID)
+	}
+	r.skills[skill.ID] = skill
+	return nil
+}
+
+// Delete removes a skill from memory.
+func (r *SkillRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.skills[id]; !ok {
+		return fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+	}
+	delete(r.skills, id)
+	newOrder := make([]string, 0, len(r.order)-1)
+	for _, oid := range r.order {
+		if oid != id {
+			newOrder = append(newOrder, oid)
+		}
+	}
+	r.order = newOrder
+	return nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"backend-yonathan/src/repository"
+)
+
+// TagAliasRepository is an in-memory implementation of repository.TagAliasRepository for tests.
+type TagAliasRepository struct {
+	mu      sync.RWMutex
+	aliases map[string]string
+}
+
+// NewTagAliasRepository creates an empty in-memory TagAliasRepository.
+func NewTagAliasRepository() *TagAliasRepository {
+	return &TagAliasRepository{aliases: make(map[string]string)}
+}
+
+// ListAliases returns a copy of all aliases.
+func (r *TagAliasRepository) ListAliases(ctx context.Context) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]string, len(r.aliases))
+	for alias, canonical := range r.aliases {
+		result[alias] = canonical
+	}
+	return result, nil
+}
+
+// SaveAlias creates or replaces an alias.
+func (r *TagAliasRepository) SaveAlias(ctx context.Context, alias, canonical string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[alias] = canonical
+	return nil
+}
+
+// DeleteAlias removes an alias.
+func (r *TagAliasRepository) DeleteAlias(ctx context.Context, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.aliases[alias]; !ok {
+		return fmt.Errorf("%w: tag alias %s", repository.ErrNotFound, alias)
+	}
+	delete(r.aliases, alias)
+	return nil
+}
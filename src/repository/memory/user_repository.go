@@ -26,18 +26,55 @@ func NewUserRepository() *UserRepository {
 	}
 }
 
-// SaveUser stores the user in memory. Generates a UserId if empty.
+// CreateUser stores a new user unless its email is already registered.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, taken := r.byEmail[user.Email]; taken {
+		return models.User{}, fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	}
+	if user.UserId == "" {
+		user.UserId = uuid.New().String()
+	}
+	if _, exists := r.users[user.UserId]; exists {
+		return models.User{}, fmt.Errorf("%w: user %s already exists", repository.ErrConflict, user.UserId)
+	}
+	r.users[user.UserId] = user
+	r.byEmail[user.Email] = user.UserId
+	return user, nil
+}
+
+// SaveUser stores the user in memory. Generates a UserId if empty. Taking
+// the email of another user returns an error wrapping ErrConflict.
 func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if user.UserId == "" {
 		user.UserId = uuid.New().String()
 	}
+	if owner, taken := r.byEmail[user.Email]; taken && owner != user.UserId {
+		return fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	}
+	if previous, ok := r.users[user.UserId]; ok && previous.Email != user.Email {
+		delete(r.byEmail, previous.Email)
+	}
 	r.users[user.UserId] = user
 	r.byEmail[user.Email] = user.UserId
 	return nil
 }
 
+// ListUsers returns all users ordered by ID.
+func (r *UserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]models.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	repository.SortUsers(users)
+	return users, nil
+}
+
 // GetUserByID fetches a user by ID.
 func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
 	r.mu.RLock()
@@ -0,0 +1,289 @@
+// Package migrate copies users and experiences between storage backends.
+// Writes are upserts by ID, so an interrupted migration can be run again;
+// a verification pass then compares both sides by count and content hash.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+// Stores is one side of a migration.
+type Stores struct {
+	Users       repository.UserRepository
+	Experiences repository.ExperienceRepository
+}
+
+// Options controls a migration.
+type Options struct {
+	// DryRun classifies every item without writing to the target.
+	DryRun bool
+	// Progress receives one line per ProgressEvery items and per store; nil
+	// disables progress output.
+	Progress      io.Writer
+	ProgressEvery int
+}
+
+// Counts summarises the migration of one store.
+type Counts struct {
+	Source    int `json:"source"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+// Report is the result of Run.
+type Report struct {
+	Users       Counts `json:"users"`
+	Experiences Counts `json:"experiences"`
+}
+
+// Verification compares one store on both sides. Missing and Different list
+// source IDs that are absent or differ in the target; Extra lists target IDs
+// that are not in the source, which does not make the verification fail.
+type Verification struct {
+	Store       repository.Store `json:"store"`
+	SourceCount int              `json:"sourceCount"`
+	TargetCount int              `json:"targetCount"`
+	Missing     []string         `json:"missing"`
+	Different   []string         `json:"different"`
+	Extra       []string         `json:"extra"`
+}
+
+// OK reports whether every source item is present and equal in the target.
+func (v Verification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Different) == 0
+}
+
+// ErrFailedItems is returned by Run when some items could not be written.
+var ErrFailedItems = errors.New("some items failed to migrate")
+
+// listUsers lists the users of repo, which must implement
+// repository.UserLister.
+func listUsers(ctx context.Context, repo repository.UserRepository) ([]models.User, error) {
+	lister, ok := repo.(repository.UserLister)
+	if !ok {
+		return nil, fmt.Errorf("users store %T cannot list users", repo)
+	}
+	return lister.ListUsers(ctx)
+}
+
+// hashUser returns the content hash of a user.
+func hashUser(user models.User) string {
+	return hashJSON(user)
+}
+
+// hashExperience returns the content hash of an experience. Version is left
+// out, since updating an existing target item assigns the next version of
+// the target; nil and empty lists hash alike because backends differ in
+// which one they return.
+func hashExperience(exp models.Experience) string {
+	exp.Version = 0
+	if exp.ImageURLs == nil {
+		exp.ImageURLs = []string{}
+	}
+	if exp.Tags == nil {
+		exp.Tags = []string{}
+	}
+	if exp.RelatedIDs == nil {
+		exp.RelatedIDs = []string{}
+	}
+	return hashJSON(exp)
+}
+
+func hashJSON(value interface{}) string {
+	data, _ := json.Marshal(value)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// progress prints the position of a store's migration every opts.ProgressEvery
+// items and at the end.
+func (opts Options) progress(store repository.Store, done, total int, counts Counts) {
+	if opts.Progress == nil {
+		return
+	}
+	every := opts.ProgressEvery
+	if every <= 0 {
+		every = 1
+	}
+	if done%every != 0 && done != total {
+		return
+	}
+	fmt.Fprintf(opts.Progress, "%s: %d/%d (creados %d, actualizados %d, sin cambios %d, fallidos %d)\n",
+		store, done, total, counts.Created, counts.Updated, counts.Unchanged, counts.Failed)
+}
+
+// failure prints an item that could not be migrated.
+func (opts Options) failure(store repository.Store, id string, err error) {
+	if opts.Progress != nil {
+		fmt.Fprintf(opts.Progress, "%s: %s: %v\n", store, id, err)
+	}
+}
+
+// Run copies every user and experience of source into target. Items missing
+// in the target are created, items whose content differs are overwritten and
+// equal items are left alone. Items that fail are reported and skipped; Run
+// then returns an error wrapping ErrFailedItems along with the report.
+func Run(ctx context.Context, source, target Stores, opts Options) (Report, error) {
+	var report Report
+
+	users, err := listUsers(ctx, source.Users)
+	if err != nil {
+		return report, fmt.Errorf("list source users: %w", err)
+	}
+	report.Users.Source = len(users)
+	for i, user := range users {
+		if err := migrateUser(ctx, target.Users, user, opts.DryRun, &report.Users); err != nil {
+			report.Users.Failed++
+			opts.failure(repository.StoreUsers, user.UserId, err)
+		}
+		opts.progress(repository.StoreUsers, i+1, len(users), report.Users)
+	}
+
+	experiences, err := source.Experiences.List(ctx)
+	if err != nil {
+		return report, fmt.Errorf("list source experiences: %w", err)
+	}
+	report.Experiences.Source = len(experiences)
+	for i, exp := range experiences {
+		if err := migrateExperience(ctx, target.Experiences, exp, opts.DryRun, &report.Experiences); err != nil {
+			report.Experiences.Failed++
+			opts.failure(repository.StoreExperiences, exp.ID, err)
+		}
+		opts.progress(repository.StoreExperiences, i+1, len(experiences), report.Experiences)
+	}
+
+	if failed := report.Users.Failed + report.Experiences.Failed; failed > 0 {
+		return report, fmt.Errorf("%w: %d", ErrFailedItems, failed)
+	}
+	return report, nil
+}
+
+// migrateUser upserts user into target by ID. New users go through
+// CreateUser so that backends reserve their email.
+func migrateUser(ctx context.Context, target repository.UserRepository, user models.User, dryRun bool, counts *Counts) error {
+	existing, err := target.GetUserByID(ctx, user.UserId)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		if !dryRun {
+			if _, err := target.CreateUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		counts.Created++
+	case err != nil:
+		return err
+	case hashUser(existing) == hashUser(user):
+		counts.Unchanged++
+	default:
+		if !dryRun {
+			if err := target.SaveUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		counts.Updated++
+	}
+	return nil
+}
+
+// migrateExperience upserts exp into target by ID. Created items keep their
+// version; overwritten items get the next version of the target.
+func migrateExperience(ctx context.Context, target repository.ExperienceRepository, exp models.Experience, dryRun bool, counts *Counts) error {
+	existing, err := target.GetByID(ctx, exp.ID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		if !dryRun {
+			if err := target.Create(ctx, exp); err != nil {
+				return err
+			}
+		}
+		counts.Created++
+	case err != nil:
+		return err
+	case hashExperience(existing) == hashExperience(exp):
+		counts.Unchanged++
+	default:
+		if !dryRun {
+			if err := target.Update(ctx, exp, existing.Version); err != nil {
+				return err
+			}
+		}
+		counts.Updated++
+	}
+	return nil
+}
+
+// Verify lists both sides again and compares them by ID and content hash.
+func Verify(ctx context.Context, source, target Stores) ([]Verification, error) {
+	sourceUsers, err := listUsers(ctx, source.Users)
+	if err != nil {
+		return nil, fmt.Errorf("list source users: %w", err)
+	}
+	targetUsers, err := listUsers(ctx, target.Users)
+	if err != nil {
+		return nil, fmt.Errorf("list target users: %w", err)
+	}
+	sourceExperiences, err := source.Experiences.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list source experiences: %w", err)
+	}
+	targetExperiences, err := target.Experiences.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list target experiences: %w", err)
+	}
+
+	return []Verification{
+		compare(repository.StoreUsers, userHashes(sourceUsers), userHashes(targetUsers)),
+		compare(repository.StoreExperiences, experienceHashes(sourceExperiences), experienceHashes(targetExperiences)),
+	}, nil
+}
+
+func userHashes(users []models.User) map[string]string {
+	hashes := make(map[string]string, len(users))
+	for _, user := range users {
+		hashes[user.UserId] = hashUser(user)
+	}
+	return hashes
+}
+
+func experienceHashes(experiences []models.Experience) map[string]string {
+	hashes := make(map[string]string, len(experiences))
+	for _, exp := range experiences {
+		hashes[exp.ID] = hashExperience(exp)
+	}
+	return hashes
+}
+
+// compare builds the verification of one store from ID → hash maps.
+func compare(store repository.Store, source, target map[string]string) Verification {
+	v := Verification{Store: store, SourceCount: len(source), TargetCount: len(target), Missing: []string{}, Different: []string{}, Extra: []string{}}
+	for id, hash := range source {
+		targetHash, ok := target[id]
+		switch {
+		case !ok:
+			v.Missing = append(v.Missing, id)
+		case targetHash != hash:
+			v.Different = append(v.Different, id)
+		}
+	}
+	for id := range target {
+		if _, ok := source[id]; !ok {
+			v.Extra = append(v.Extra, id)
+		}
+	}
+	sort.Strings(v.Missing)
+	sort.Strings(v.Different)
+	sort.Strings(v.Extra)
+	return v
+}
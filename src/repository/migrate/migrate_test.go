@@ -0,0 +1,170 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+)
+
+const seed = `{
+  "users": [{"userId": "u1", "email": "ana@example.com", "password": "hash", "username": "ana"}],
+  "experiences": [
+    {"id": "e1", "title": "Uno", "tags": ["go"], "visibility": "public", "version": 3},
+    {"id": "e2", "title": "Dos", "visibility": "private", "version": 1}
+  ]
+}`
+
+// seedStores opens the memory backend with a seed file, as the migrate
+// command does for memory: DSNs.
+func seedStores(t *testing.T) Stores {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "seed.json")
+	if err := os.WriteFile(path, []byte(seed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	users, err := repository.OpenUsers(ctx, "memory:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	experiences, err := repository.OpenExperiences(ctx, "memory:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Stores{Users: users, Experiences: experiences}
+}
+
+func emptyStores() Stores {
+	return Stores{Users: memory.NewUserRepository(), Experiences: memory.NewExperienceRepository()}
+}
+
+func TestRunCopiesAndIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	source, target := seedStores(t), emptyStores()
+
+	var out bytes.Buffer
+	report, err := Run(ctx, source, target, Options{Progress: &out, ProgressEvery: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Users.Created != 1 || report.Experiences.Created != 2 {
+		t.Fatalf("first run = %+v", report)
+	}
+	if !strings.Contains(out.String(), "experiences: 2/2") {
+		t.Fatalf("missing progress output:\n%s", out.String())
+	}
+	if exp, err := target.Experiences.GetByID(ctx, "e1"); err != nil || exp.Version != 3 {
+		t.Fatalf("created experience = %+v, %v; want version kept", exp, err)
+	}
+
+	report, err = Run(ctx, source, target, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Users.Unchanged != 1 || report.Experiences.Unchanged != 2 || report.Experiences.Created != 0 {
+		t.Fatalf("second run = %+v", report)
+	}
+
+	verifications, err := Verify(ctx, source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range verifications {
+		if !v.OK() || v.SourceCount != v.TargetCount {
+			t.Fatalf("verification = %+v", v)
+		}
+	}
+}
+
+func TestRunOverwritesChangedItems(t *testing.T) {
+	ctx := context.Background()
+	source, target := seedStores(t), emptyStores()
+	_ = target.Experiences.Create(ctx, models.Experience{ID: "e1", Title: "Viejo", Version: 7})
+
+	report, err := Run(ctx, source, target, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Experiences.Updated != 1 || report.Experiences.Created != 1 {
+		t.Fatalf("report = %+v", report.Experiences)
+	}
+	exp, _ := target.Experiences.GetByID(ctx, "e1")
+	if exp.Title != "Uno" || exp.Version != 8 {
+		t.Fatalf("updated experience = %+v", exp)
+	}
+}
+
+func TestDryRunDoesNotWrite(t *testing.T) {
+	ctx := context.Background()
+	source, target := seedStores(t), emptyStores()
+
+	report, err := Run(ctx, source, target, Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Users.Created != 1 || report.Experiences.Created != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if list, _ := target.Experiences.List(ctx); len(list) != 0 {
+		t.Fatalf("dry run wrote %d experiences", len(list))
+	}
+}
+
+func TestRunReportsFailedItems(t *testing.T) {
+	ctx := context.Background()
+	source, target := seedStores(t), emptyStores()
+	// Another user already owns the email in the target.
+	_, _ = target.Users.CreateUser(ctx, models.User{UserId: "other", Email: "ana@example.com"})
+
+	var out bytes.Buffer
+	report, err := Run(ctx, source, target, Options{Progress: &out})
+	if !errors.Is(err, ErrFailedItems) {
+		t.Fatalf("err = %v, want ErrFailedItems", err)
+	}
+	if report.Users.Failed != 1 || report.Experiences.Created != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if !strings.Contains(out.String(), "users: u1:") {
+		t.Fatalf("failure not printed:\n%s", out.String())
+	}
+}
+
+func TestVerifyFindsDifferences(t *testing.T) {
+	ctx := context.Background()
+	source, target := seedStores(t), emptyStores()
+	_ = target.Experiences.Create(ctx, models.Experience{ID: "e1", Title: "Otro"})
+	_ = target.Experiences.Create(ctx, models.Experience{ID: "e9", Title: "Extra"})
+
+	verifications, err := Verify(ctx, source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, experiences := verifications[0], verifications[1]
+	if users.OK() || len(users.Missing) != 1 {
+		t.Fatalf("users verification = %+v", users)
+	}
+	if experiences.OK() || experiences.SourceCount != 2 || experiences.TargetCount != 2 ||
+		len(experiences.Missing) != 1 || experiences.Different[0] != "e1" || experiences.Extra[0] != "e9" {
+		t.Fatalf("experiences verification = %+v", experiences)
+	}
+}
+
+func TestHashIgnoresVersionAndNilLists(t *testing.T) {
+	a := models.Experience{ID: "e1", Title: "Uno", Version: 1}
+	b := models.Experience{ID: "e1", Title: "Uno", Version: 5, Tags: []string{}, ImageURLs: []string{}}
+	if hashExperience(a) != hashExperience(b) {
+		t.Fatal("hashes differ for equal content")
+	}
+	b.Title = "Dos"
+	if hashExperience(a) == hashExperience(b) {
+		t.Fatal("hashes match for different content")
+	}
+}
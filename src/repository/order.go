@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"sort"
+
+	models "backend-yonathan/src/models"
+)
+
+// SortExperiences orders experiences the way List returns them: by
+// CreatedAt, then by ID for experiences created at the same instant.
+func SortExperiences(experiences []models.Experience) {
+	sort.Slice(experiences, func(i, j int) bool {
+		if experiences[i].CreatedAt != experiences[j].CreatedAt {
+			return experiences[i].CreatedAt < experiences[j].CreatedAt
+		}
+		return experiences[i].ID < experiences[j].ID
+	})
+}
+
+// SortUsers orders users the way ListUsers returns them: by UserId.
+func SortUsers(users []models.User) {
+	sort.Slice(users, func(i, j int) bool { return users[i].UserId < users[j].UserId })
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store names a repository of Repositories, used in configuration and errors.
+type Store string
+
+const (
+	StoreUsers       Store = "users"
+	StoreExperiences Store = "experiences"
+	StoreSkills      Store = "skills"
+	StoreTagAliases  Store = "tag_aliases"
+)
+
+// EnvVar returns the environment variable holding the DSN of the store,
+// e.g. EXPERIENCES_STORE.
+func (s Store) EnvVar() string {
+	return strings.ToUpper(string(s)) + "_STORE"
+}
+
+// DSN is a parsed store location such as firestore://my-project,
+// file:///var/data or dynamodb://users?region=us-east-1.
+type DSN struct {
+	Scheme string
+	// Location is the host and path after the scheme ("my-project",
+	// "/var/data"). Opaque forms like file:./data are supported.
+	Location string
+	Params   url.Values
+	raw      string
+}
+
+// String returns the DSN as configured.
+func (d DSN) String() string {
+	return d.raw
+}
+
+// ParseDSN parses a store DSN. The scheme is required.
+func ParseDSN(raw string) (DSN, error) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return DSN{}, fmt.Errorf("invalid DSN %q: %w", raw, err)
+	}
+	if parsed.Scheme == "" {
+		return DSN{}, fmt.Errorf("invalid DSN %q: missing scheme (e.g. file://, firestore://)", raw)
+	}
+	location := parsed.Opaque
+	if location == "" {
+		location = parsed.Host + parsed.Path
+	}
+	return DSN{
+		Scheme:   strings.ToLower(parsed.Scheme),
+		Location: location,
+		Params:   parsed.Query(),
+		raw:      raw,
+	}, nil
+}
+
+// Backend opens the repositories a storage backend provides. Nil openers
+// mark stores the backend does not support.
+type Backend struct {
+	Users       func(ctx context.Context, dsn DSN) (UserRepository, error)
+	Experiences func(ctx context.Context, dsn DSN) (ExperienceRepository, error)
+	Skills      func(ctx context.Context, dsn DSN) (SkillRepository, error)
+	TagAliases  func(ctx context.Context, dsn DSN) (TagAliasRepository, error)
+}
+
+var backends = struct {
+	mu       sync.RWMutex
+	byScheme map[string]Backend
+}{byScheme: map[string]Backend{}}
+
+// Register makes a backend available under a DSN scheme. Backend packages
+// call it from init; registering a scheme twice panics.
+func Register(scheme string, backend Backend) {
+	scheme = strings.ToLower(scheme)
+	backends.mu.Lock()
+	defer backends.mu.Unlock()
+	if _, exists := backends.byScheme[scheme]; exists {
+		panic("repository: backend registered twice for scheme " + scheme)
+	}
+	backends.byScheme[scheme] = backend
+}
+
+// RegisteredSchemes returns the registered DSN schemes in alphabetical order.
+func RegisteredSchemes() []string {
+	backends.mu.RLock()
+	defer backends.mu.RUnlock()
+	schemes := make([]string, 0, len(backends.byScheme))
+	for scheme := range backends.byScheme {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+func lookupBackend(scheme string) (Backend, bool) {
+	backends.mu.RLock()
+	defer backends.mu.RUnlock()
+	backend, ok := backends.byScheme[scheme]
+	return backend, ok
+}
+
+// Config holds the DSN of each store.
+type Config struct {
+	Users       string
+	Experiences string
+	Skills      string
+	TagAliases  string
+}
+
+// providerDefaults maps the legacy DB_PROVIDER values to per-store DSNs.
+// Empty locations fall back to each backend's environment defaults.
+var providerDefaults = map[string]Config{
+	"json":      {Users: "file:", Experiences: "file:", Skills: "file:", TagAliases: "file:"},
+	"firestore": {Users: "firestore:", Experiences: "firestore:", Skills: "firestore:", TagAliases: "firestore:"},
+	// DynamoDB and the SQL backends store users and experiences only. Skills
+	// and aliases have no default: local files are lost on every deploy of a
+	// serverless instance, so SKILLS_STORE and TAG_ALIASES_STORE must be set.
+	"dynamodb": {Users: "dynamodb:", Experiences: "dynamodb:"},
+	"postgres": {Users: "postgres:", Experiences: "postgres:"},
+	"sqlite":   {Users: "sqlite:", Experiences: "sqlite:"},
+}
+
+// providerFromEnv returns the defaults of DB_PROVIDER; an unset
+// DB_PROVIDER has none.
+func providerFromEnv() (Config, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("DB_PROVIDER")))
+	defaults, known := providerDefaults[provider]
+	if provider != "" && !known {
+		return Config{}, fmt.Errorf("DB_PROVIDER %q no reconocido. Valores validos: dynamodb, firestore, json, postgres, sqlite", provider)
+	}
+	return defaults, nil
+}
+
+// DSN returns the DSN of store in c.
+func (c Config) DSN(store Store) string {
+	switch store {
+	case StoreUsers:
+		return c.Users
+	case StoreExperiences:
+		return c.Experiences
+	case StoreSkills:
+		return c.Skills
+	default:
+		return c.TagAliases
+	}
+}
+
+// StoreFromEnv returns the DSN of one store: its *_STORE variable, or the
+// default of DB_PROVIDER when unset. Tools that open only some stores use
+// it instead of ConfigFromEnv.
+func StoreFromEnv(store Store) (string, error) {
+	defaults, err := providerFromEnv()
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(os.Getenv(store.EnvVar()))
+	if value == "" {
+		value = defaults.DSN(store)
+	}
+	if value == "" {
+		return "", fmt.Errorf("sin almacenamiento configurado para %s: define DB_PROVIDER o %s", store.EnvVar(), store.EnvVar())
+	}
+	return value, nil
+}
+
+// ConfigFromEnv reads USERS_STORE, EXPERIENCES_STORE, SKILLS_STORE and
+// TAG_ALIASES_STORE. Stores left unset use the defaults of DB_PROVIDER; it
+// is an error when a store has neither.
+func ConfigFromEnv() (Config, error) {
+	defaults, err := providerFromEnv()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{}
+	fields := []struct {
+		store  Store
+		target *string
+	}{
+		{StoreUsers, &cfg.Users},
+		{StoreExperiences, &cfg.Experiences},
+		{StoreSkills, &cfg.Skills},
+		{StoreTagAliases, &cfg.TagAliases},
+	}
+	missing := []string{}
+	for _, field := range fields {
+		value := strings.TrimSpace(os.Getenv(field.store.EnvVar()))
+		if value == "" {
+			value = defaults.DSN(field.store)
+		}
+		if value == "" {
+			missing = append(missing, field.store.EnvVar())
+		}
+		*field.target = value
+	}
+	if len(missing) > 0 {
+		return Config{}, fmt.Errorf("sin almacenamiento configurado para %s: define DB_PROVIDER o las variables *_STORE", strings.Join(missing, ", "))
+	}
+	return cfg, nil
+}
+
+// resolve parses the DSN of store and finds its backend.
+func resolve(store Store, raw string) (DSN, Backend, error) {
+	dsn, err := ParseDSN(raw)
+	if err != nil {
+		return DSN{}, Backend{}, fmt.Errorf("%s: %w", store.EnvVar(), err)
+	}
+	backend, ok := lookupBackend(dsn.Scheme)
+	if !ok {
+		return DSN{}, Backend{}, fmt.Errorf("%s: unknown backend %q (registered: %s)", store.EnvVar(), dsn.Scheme, strings.Join(RegisteredSchemes(), ", "))
+	}
+	return dsn, backend, nil
+}
+
+// Open builds the repositories described by cfg. Every store is validated
+// before any backend is contacted, so configuration mistakes are reported
+// together and without side effects.
+func Open(ctx context.Context, cfg Config) (Repositories, error) {
+	type resolved struct {
+		dsn     DSN
+		backend Backend
+	}
+	stores := []struct {
+		store Store
+		raw   string
+	}{
+		{StoreUsers, cfg.Users},
+		{StoreExperiences, cfg.Experiences},
+		{StoreSkills, cfg.Skills},
+		{StoreTagAliases, cfg.TagAliases},
+	}
+
+	results := make(map[Store]resolved, len(stores))
+	problems := []string{}
+	for _, entry := range stores {
+		dsn, backend, err := resolve(entry.store, entry.raw)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		supported := map[Store]bool{
+			StoreUsers:       backend.Users != nil,
+			StoreExperiences: backend.Experiences != nil,
+			StoreSkills:      backend.Skills != nil,
+			StoreTagAliases:  backend.TagAliases != nil,
+		}
+		if !supported[entry.store] {
+			problems = append(problems, fmt.Sprintf("%s: backend %q does not provide the %s store", entry.store.EnvVar(), dsn.Scheme, entry.store))
+			continue
+		}
+		results[entry.store] = resolved{dsn, backend}
+	}
+	if len(problems) > 0 {
+		return Repositories{}, fmt.Errorf("invalid repository configuration: %s", strings.Join(problems, "; "))
+	}
+
+	var repos Repositories
+	var err error
+	users := results[StoreUsers]
+	if repos.Users, err = users.backend.Users(ctx, users.dsn); err != nil {
+		return Repositories{}, fmt.Errorf("%s: %w", StoreUsers.EnvVar(), err)
+	}
+	experiences := results[StoreExperiences]
+	if repos.Experiences, err = experiences.backend.Experiences(ctx, experiences.dsn); err != nil {
+		return Repositories{}, fmt.Errorf("%s: %w", StoreExperiences.EnvVar(), err)
+	}
+	skills := results[StoreSkills]
+	if repos.Skills, err = skills.backend.Skills(ctx, skills.dsn); err != nil {
+		return Repositories{}, fmt.Errorf("%s: %w", StoreSkills.EnvVar(), err)
+	}
+	tagAliases := results[StoreTagAliases]
+	if repos.TagAliases, err = tagAliases.backend.TagAliases(ctx, tagAliases.dsn); err != nil {
+		return Repositories{}, fmt.Errorf("%s: %w", StoreTagAliases.EnvVar(), err)
+	}
+	return repos, nil
+}
+
+// OpenUsers opens only the users store at the DSN raw.
+func OpenUsers(ctx context.Context, raw string) (UserRepository, error) {
+	dsn, backend, err := resolve(StoreUsers, raw)
+	if err != nil {
+		return nil, err
+	}
+	if backend.Users == nil {
+		return nil, fmt.Errorf("%s: backend %q does not provide the %s store", StoreUsers.EnvVar(), dsn.Scheme, StoreUsers)
+	}
+	return backend.Users(ctx, dsn)
+}
+
+// OpenExperiences opens only the experiences store at the DSN raw.
+func OpenExperiences(ctx context.Context, raw string) (ExperienceRepository, error) {
+	dsn, backend, err := resolve(StoreExperiences, raw)
+	if err != nil {
+		return nil, err
+	}
+	if backend.Experiences == nil {
+		return nil, fmt.Errorf("%s: backend %q does not provide the %s store", StoreExperiences.EnvVar(), dsn.Scheme, StoreExperiences)
+	}
+	return backend.Experiences(ctx, dsn)
+}
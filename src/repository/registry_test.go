@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	models "backend-yonathan/src/models"
+)
+
+type fakeUsers struct{ location string }
+
+func (fakeUsers) CreateUser(_ context.Context, user models.User) (models.User, error) {
+	return user, nil
+}
+func (fakeUsers) SaveUser(context.Context, models.User) error { return nil }
+func (fakeUsers) GetUserByID(context.Context, string) (models.User, error) {
+	return models.User{}, ErrNotFound
+}
+func (fakeUsers) GetUserByEmail(context.Context, string) (models.User, error) {
+	return models.User{}, ErrNotFound
+}
+
+func init() {
+	Register("fakeusers", Backend{
+		Users: func(_ context.Context, dsn DSN) (UserRepository, error) {
+			if dsn.Location == "broken" {
+				return nil, errors.New("connection refused")
+			}
+			return fakeUsers{location: dsn.Location}, nil
+		},
+	})
+}
+
+func TestParseDSN(t *testing.T) {
+	cases := []struct {
+		raw, scheme, location string
+	}{
+		{"firestore://my-project", "firestore", "my-project"},
+		{"file:///var/data", "file", "/var/data"},
+		{"file:./data", "file", "./data"},
+		{"FILE:", "file", ""},
+		{"dynamodb://users?region=eu-west-1", "dynamodb", "users"},
+	}
+	for _, tc := range cases {
+		dsn, err := ParseDSN(tc.raw)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.raw, err)
+		}
+		if dsn.Scheme != tc.scheme || dsn.Location != tc.location {
+			t.Fatalf("%s: got scheme=%q location=%q", tc.raw, dsn.Scheme, dsn.Location)
+		}
+	}
+	if dsn, _ := ParseDSN("dynamodb://users?region=eu-west-1"); dsn.Params.Get("region") != "eu-west-1" {
+		t.Fatalf("expected region param, got %v", dsn.Params)
+	}
+	if _, err := ParseDSN("/var/data"); err == nil {
+		t.Fatal("expected missing scheme error")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("DB_PROVIDER", "dynamodb")
+	t.Setenv("USERS_STORE", "")
+	t.Setenv("EXPERIENCES_STORE", "firestore://portfolio")
+	t.Setenv("SKILLS_STORE", "")
+	t.Setenv("TAG_ALIASES_STORE", "")
+
+	// DynamoDB does not store skills or aliases, and they get no default.
+	if _, err := ConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "SKILLS_STORE, TAG_ALIASES_STORE") {
+		t.Fatalf("expected skills and aliases to require a store, got %v", err)
+	}
+	if dsn, err := StoreFromEnv(StoreUsers); err != nil || dsn != "dynamodb:" {
+		t.Fatalf("StoreFromEnv(users) = %q, %v; want the DB_PROVIDER default", dsn, err)
+	}
+
+	t.Setenv("SKILLS_STORE", "firestore://portfolio")
+	t.Setenv("TAG_ALIASES_STORE", "memory:")
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Config{Users: "dynamodb:", Experiences: "firestore://portfolio", Skills: "firestore://portfolio", TagAliases: "memory:"}
+	if cfg != want {
+		t.Fatalf("got %+v, want %+v", cfg, want)
+	}
+
+	t.Setenv("DB_PROVIDER", "")
+	if _, err := ConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "USERS_STORE") {
+		t.Fatalf("expected missing stores error, got %v", err)
+	}
+	t.Setenv("DB_PROVIDER", "mongo")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected unknown provider error")
+	}
+}
+
+func TestOpenReportsAllConfigurationErrors(t *testing.T) {
+	_, err := Open(context.Background(), Config{
+		Users:       "fakeusers://ok",
+		Experiences: "fakeusers://ok",
+		Skills:      "nosuch://x",
+		TagAliases:  "not a dsn",
+	})
+	if err == nil {
+		t.Fatal("expected configuration error")
+	}
+	for _, want := range []string{"EXPERIENCES_STORE", "does not provide", "SKILLS_STORE", "unknown backend", "TAG_ALIASES_STORE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestOpenWrapsBackendErrors(t *testing.T) {
+	Register("fakecontent", Backend{
+		Experiences: func(context.Context, DSN) (ExperienceRepository, error) { return nil, nil },
+		Skills:      func(context.Context, DSN) (SkillRepository, error) { return nil, nil },
+		TagAliases:  func(context.Context, DSN) (TagAliasRepository, error) { return nil, nil },
+	})
+	cfg := Config{Users: "fakeusers://broken", Experiences: "fakecontent:", Skills: "fakecontent:", TagAliases: "fakecontent:"}
+	if _, err := Open(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "USERS_STORE: connection refused") {
+		t.Fatalf("expected wrapped backend error, got %v", err)
+	}
+
+	cfg.Users = "fakeusers://primary"
+	repos, err := Open(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users, ok := repos.Users.(fakeUsers); !ok || users.location != "primary" {
+		t.Fatalf("expected DSN location to reach the backend, got %#v", repos.Users)
+	}
+}
@@ -0,0 +1,574 @@
+// Package repotest is a conformance suite for repository implementations.
+// Each backend runs it from its own tests so that all of them honour the
+// same contract: errors wrapping the repository sentinels, conditional
+// writes under concurrency, list ordering and round-trip fidelity.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+// Concurrency is the number of goroutines racing in the concurrency tests.
+const Concurrency = 8
+
+// NewUserRepository returns an empty user repository for one subtest.
+type NewUserRepository func(t *testing.T) repository.UserRepository
+
+// NewExperienceRepository returns an empty experience repository for one
+// subtest.
+type NewExperienceRepository func(t *testing.T) repository.ExperienceRepository
+
+// NewSkillRepository returns an empty skill repository for one subtest.
+type NewSkillRepository func(t *testing.T) repository.SkillRepository
+
+// UserOption adjusts the user repository contract for one repository.
+type UserOption func(*userSuite)
+
+type userSuite struct {
+	ownsEncrypted bool
+}
+
+// OwnsEncrypted is for repositories that manage User.Encrypted themselves,
+// such as the encryption decorator: callers get the field back empty
+// instead of as they stored it.
+func OwnsEncrypted() UserOption {
+	return func(s *userSuite) { s.ownsEncrypted = true }
+}
+
+// RunUserRepositoryTests runs the user repository contract against fresh
+// repositories from newRepo. ListUsers is checked when the repository
+// implements repository.UserLister.
+func RunUserRepositoryTests(t *testing.T, newRepo NewUserRepository, opts ...UserOption) {
+	var suite userSuite
+	for _, opt := range opts {
+		opt(&suite)
+	}
+	t.Run("RoundTrip", func(t *testing.T) { testUserRoundTrip(t, newRepo(t), suite) })
+	t.Run("NotFound", func(t *testing.T) { testUserNotFound(t, newRepo(t)) })
+	t.Run("CreateGeneratesID", func(t *testing.T) { testUserCreateGeneratesID(t, newRepo(t)) })
+	t.Run("CreateRejectsTakenEmail", func(t *testing.T) { testUserCreateRejectsTakenEmail(t, newRepo(t)) })
+	t.Run("SaveUpserts", func(t *testing.T) { testUserSaveUpserts(t, newRepo(t)) })
+	t.Run("SaveChangesEmail", func(t *testing.T) { testUserSaveChangesEmail(t, newRepo(t)) })
+	t.Run("SaveRejectsTakenEmail", func(t *testing.T) { testUserSaveRejectsTakenEmail(t, newRepo(t)) })
+	t.Run("ConcurrentCreate", func(t *testing.T) { testUserConcurrentCreate(t, newRepo(t)) })
+	t.Run("ListOrder", func(t *testing.T) { testUserListOrder(t, newRepo(t)) })
+}
+
+// ExperienceOption adjusts the experience repository contract for one
+// repository.
+type ExperienceOption func(*experienceSuite)
+
+type experienceSuite struct {
+	tenant string
+}
+
+// StampsTenant is for repositories that record the calling tenant in
+// Experience.TenantID, such as the tenant decorator: the suite calls them
+// without a tenant in ctx, so experiences come back owned by tenant.
+func StampsTenant(tenant string) ExperienceOption {
+	return func(s *experienceSuite) { s.tenant = tenant }
+}
+
+// RunExperienceRepositoryTests runs the experience repository contract
+// against fresh repositories from newRepo. ApplyBatch is checked when the
+// repository implements repository.BatchExperienceRepository.
+func RunExperienceRepositoryTests(t *testing.T, newRepo NewExperienceRepository, opts ...ExperienceOption) {
+	var suite experienceSuite
+	for _, opt := range opts {
+		opt(&suite)
+	}
+	t.Run("RoundTrip", func(t *testing.T) { testExperienceRoundTrip(t, newRepo(t), suite) })
+	t.Run("NotFound", func(t *testing.T) { testExperienceNotFound(t, newRepo(t)) })
+	t.Run("ListOrder", func(t *testing.T) { testExperienceListOrder(t, newRepo(t)) })
+	t.Run("UpdateChecksVersion", func(t *testing.T) { testExperienceUpdateChecksVersion(t, newRepo(t), suite) })
+	t.Run("DeleteChecksVersion", func(t *testing.T) { testExperienceDeleteChecksVersion(t, newRepo(t)) })
+	t.Run("UpdatePositionsIsAtomic", func(t *testing.T) { testExperienceUpdatePositions(t, newRepo(t)) })
+	t.Run("ConcurrentUpdate", func(t *testing.T) { testExperienceConcurrentUpdate(t, newRepo(t)) })
+	t.Run("ApplyBatch", func(t *testing.T) { testExperienceApplyBatch(t, newRepo(t), suite) })
+}
+
+// RunSkillRepositoryTests runs the skill repository contract against fresh
+// repositories from newRepo.
+func RunSkillRepositoryTests(t *testing.T, newRepo NewSkillRepository) {
+	t.Run("NotFound", func(t *testing.T) { testSkillNotFound(t, newRepo(t)) })
+	t.Run("CreateRejectsTakenID", func(t *testing.T) { testSkillCreateRejectsTakenID(t, newRepo(t)) })
+}
+
+// --- Users ---
+
+func testUser(id, email string) models.User {
+	return models.User{UserId: id, Email: email, Password: "$2a$10$hash/" + id, UserName: "Usuario " + id}
+}
+
+func mustCreateUser(t *testing.T, repo repository.UserRepository, user models.User) models.User {
+	t.Helper()
+	created, err := repo.CreateUser(context.Background(), user)
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", user.Email, err)
+	}
+	return created
+}
+
+func testUserRoundTrip(t *testing.T, repo repository.UserRepository, suite userSuite) {
+	ctx := context.Background()
+	user := models.User{UserId: "u-1", Email: "ñandú@example.com", Password: "$2a$10$abc/def.ghi", UserName: "Ñandú \"el rápido\" 🐦", Encrypted: "v1:k1:d3JhcHBlZA:c2VhbGVk"}
+	want := user
+	if suite.ownsEncrypted {
+		want.Encrypted = ""
+	}
+	if created := mustCreateUser(t, repo, user); created != want {
+		t.Fatalf("CreateUser returned %+v, want %+v", created, want)
+	}
+	if got, err := repo.GetUserByID(ctx, user.UserId); err != nil || got != want {
+		t.Fatalf("GetUserByID = %+v, %v; want %+v", got, err, want)
+	}
+	if got, err := repo.GetUserByEmail(ctx, user.Email); err != nil || got != want {
+		t.Fatalf("GetUserByEmail = %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func testUserNotFound(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+	mustCreateUser(t, repo, testUser("u-1", "ana@example.com"))
+	if _, err := repo.GetUserByID(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetUserByID(missing) = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.GetUserByEmail(ctx, "missing@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetUserByEmail(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func testUserCreateGeneratesID(t *testing.T, repo repository.UserRepository) {
+	created := mustCreateUser(t, repo, testUser("", "ana@example.com"))
+	if created.UserId == "" {
+		t.Fatal("CreateUser did not generate a UserId")
+	}
+	if got, err := repo.GetUserByID(context.Background(), created.UserId); err != nil || got.Email != "ana@example.com" {
+		t.Fatalf("GetUserByID(generated) = %+v, %v", got, err)
+	}
+}
+
+func testUserCreateRejectsTakenEmail(t *testing.T, repo repository.UserRepository) {
+	mustCreateUser(t, repo, testUser("u-1", "ana@example.com"))
+	_, err := repo.CreateUser(context.Background(), testUser("u-2", "ana@example.com"))
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("CreateUser with a taken email = %v, want ErrConflict", err)
+	}
+	if _, err := repo.GetUserByID(context.Background(), "u-2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("rejected user was stored: %v", err)
+	}
+}
+
+func testUserSaveUpserts(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+	user := testUser("u-1", "ana@example.com")
+	if err := repo.SaveUser(ctx, user); err != nil {
+		t.Fatalf("SaveUser(new): %v", err)
+	}
+	if got, err := repo.GetUserByEmail(ctx, user.Email); err != nil || got != user {
+		t.Fatalf("GetUserByEmail after insert = %+v, %v; want %+v", got, err, user)
+	}
+	user.UserName, user.Password = "Ana María", "$2a$10$other"
+	if err := repo.SaveUser(ctx, user); err != nil {
+		t.Fatalf("SaveUser(existing): %v", err)
+	}
+	if got, err := repo.GetUserByID(ctx, user.UserId); err != nil || got != user {
+		t.Fatalf("GetUserByID after update = %+v, %v; want %+v", got, err, user)
+	}
+	if _, err := repo.CreateUser(ctx, testUser("u-2", user.Email)); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("CreateUser with an email stored by SaveUser = %v, want ErrConflict", err)
+	}
+}
+
+func testUserSaveChangesEmail(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+	user := mustCreateUser(t, repo, testUser("u-1", "ana@example.com"))
+	user.Email = "ana@example.org"
+	if err := repo.SaveUser(ctx, user); err != nil {
+		t.Fatalf("SaveUser with a new email: %v", err)
+	}
+	if got, err := repo.GetUserByEmail(ctx, "ana@example.org"); err != nil || got != user {
+		t.Fatalf("GetUserByEmail(new) = %+v, %v; want %+v", got, err, user)
+	}
+	if _, err := repo.GetUserByEmail(ctx, "ana@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetUserByEmail(old) = %v, want ErrNotFound", err)
+	}
+	// The old email is free again.
+	mustCreateUser(t, repo, testUser("u-2", "ana@example.com"))
+}
+
+func testUserSaveRejectsTakenEmail(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+	mustCreateUser(t, repo, testUser("u-1", "ana@example.com"))
+	bob := mustCreateUser(t, repo, testUser("u-2", "bob@example.com"))
+	bob.Email = "ana@example.com"
+	if err := repo.SaveUser(ctx, bob); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("SaveUser with another user's email = %v, want ErrConflict", err)
+	}
+	if got, err := repo.GetUserByEmail(ctx, "ana@example.com"); err != nil || got.UserId != "u-1" {
+		t.Fatalf("GetUserByEmail after rejected save = %+v, %v; want u-1", got, err)
+	}
+	if got, err := repo.GetUserByID(ctx, "u-2"); err != nil || got.Email != "bob@example.com" {
+		t.Fatalf("rejected save changed the user: %+v, %v", got, err)
+	}
+}
+
+func testUserConcurrentCreate(t *testing.T, repo repository.UserRepository) {
+	errs := make([]error, Concurrency)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.CreateUser(context.Background(), testUser(fmt.Sprintf("u-%d", i), "ana@example.com"))
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, repository.ErrConflict):
+			t.Errorf("CreateUser %d = %v, want nil or ErrConflict", i, err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d concurrent registrations of one email succeeded, want 1", created)
+	}
+}
+
+func testUserListOrder(t *testing.T, repo repository.UserRepository) {
+	lister, ok := repo.(repository.UserLister)
+	if !ok {
+		t.Skip("repository does not implement repository.UserLister")
+	}
+	for _, id := range []string{"u-3", "u-1", "u-2"} {
+		mustCreateUser(t, repo, testUser(id, id+"@example.com"))
+	}
+	users, err := lister.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if ids := userIDs(users); !reflect.DeepEqual(ids, []string{"u-1", "u-2", "u-3"}) {
+		t.Fatalf("ListUsers IDs = %v, want them ordered by ID", ids)
+	}
+}
+
+func userIDs(users []models.User) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.UserId
+	}
+	return ids
+}
+
+// --- Experiences ---
+
+func testExperience(id, createdAt string) models.Experience {
+	return models.Experience{
+		ID:         id,
+		Title:      "Experiencia " + id,
+		Summary:    "Resumen",
+		Body:       "<p>Cuerpo</p>",
+		Tags:       []string{"go"},
+		Visibility: "public",
+		Version:    1,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
+func mustCreateExperience(t *testing.T, repo repository.ExperienceRepository, exp models.Experience) {
+	t.Helper()
+	if err := repo.Create(context.Background(), exp); err != nil {
+		t.Fatalf("Create(%s): %v", exp.ID, err)
+	}
+}
+
+func mustGetExperience(t *testing.T, repo repository.ExperienceRepository, id string) models.Experience {
+	t.Helper()
+	exp, err := repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID(%s): %v", id, err)
+	}
+	return exp
+}
+
+// normalizeExperience makes nil and empty lists compare equal; backends
+// differ in which one they return for an empty list.
+func normalizeExperience(exp models.Experience) models.Experience {
+	for _, list := range []*[]string{&exp.ImageURLs, &exp.Tags, &exp.RelatedIDs} {
+		if len(*list) == 0 {
+			*list = nil
+		}
+	}
+	return exp
+}
+
+func (s experienceSuite) assertExperience(t *testing.T, label string, got, want models.Experience) {
+	t.Helper()
+	if s.tenant != "" {
+		want.TenantID = s.tenant
+	}
+	if !reflect.DeepEqual(normalizeExperience(got), normalizeExperience(want)) {
+		t.Fatalf("%s = %+v, want %+v", label, got, want)
+	}
+}
+
+func testExperienceRoundTrip(t *testing.T, repo repository.ExperienceRepository, suite experienceSuite) {
+	full := models.Experience{
+		ID:         "e-1",
+		TenantID:   "ana",
+		Title:      "Título con acentos y \"comillas\"",
+		Summary:    "Línea 1\nLínea 2 🚀",
+		Body:       `<p>Hola <a href="https://example.com?a=1&b=2">enlace</a></p>`,
+		ImageURLs:  []string{"https://example.com/a.png", "https://example.com/b.jpg"},
+		Tags:       []string{"go", "skill", "c++"},
+		RelatedIDs: []string{"e-2"},
+		Visibility: "private",
+		Position:   7,
+		Pinned:     true,
+		Version:    3,
+		CreatedAt:  "2024-01-02T03:04:05Z",
+		UpdatedAt:  "2024-05-06T07:08:09Z",
+	}
+	empty := models.Experience{ID: "e-2", Title: "Vacía", Version: 1}
+	mustCreateExperience(t, repo, full)
+	mustCreateExperience(t, repo, empty)
+
+	suite.assertExperience(t, "GetByID(full)", mustGetExperience(t, repo, full.ID), full)
+	suite.assertExperience(t, "GetByID(empty)", mustGetExperience(t, repo, empty.ID), empty)
+	list, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("List returned %d experiences, want 2", len(list))
+	}
+	for _, exp := range list {
+		want := full
+		if exp.ID == empty.ID {
+			want = empty
+		}
+		suite.assertExperience(t, "List item "+exp.ID, exp, want)
+	}
+}
+
+func testExperienceNotFound(t *testing.T, repo repository.ExperienceRepository) {
+	ctx := context.Background()
+	mustCreateExperience(t, repo, testExperience("e-1", "2024-01-01T00:00:00Z"))
+	missing := testExperience("missing", "2024-01-01T00:00:00Z")
+	if _, err := repo.GetByID(ctx, missing.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Update(ctx, missing, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, missing.ID, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Delete(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: missing.ID, Position: 1}}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("UpdatePositions(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func testExperienceListOrder(t *testing.T, repo repository.ExperienceRepository) {
+	for _, exp := range []models.Experience{
+		testExperience("e-c", "2024-03-01T00:00:00Z"),
+		testExperience("e-b", "2024-01-01T00:00:00Z"),
+		testExperience("e-a", "2024-03-01T00:00:00Z"),
+		testExperience("e-d", "2023-12-31T00:00:00Z"),
+	} {
+		mustCreateExperience(t, repo, exp)
+	}
+	list, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	ids := make([]string, len(list))
+	for i, exp := range list {
+		ids[i] = exp.ID
+	}
+	if want := []string{"e-d", "e-b", "e-a", "e-c"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("List IDs = %v, want %v (by CreatedAt, then ID)", ids, want)
+	}
+}
+
+func testExperienceUpdateChecksVersion(t *testing.T, repo repository.ExperienceRepository, suite experienceSuite) {
+	ctx := context.Background()
+	exp := testExperience("e-1", "2024-01-01T00:00:00Z")
+	mustCreateExperience(t, repo, exp)
+
+	exp.Title = "Editada"
+	exp.Version = 99 // ignored: the stored version becomes expectedVersion+1
+	if err := repo.Update(ctx, exp, 1); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	exp.Version = 2
+	suite.assertExperience(t, "GetByID after Update", mustGetExperience(t, repo, exp.ID), exp)
+
+	stale := exp
+	stale.Title = "Obsoleta"
+	if err := repo.Update(ctx, stale, 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("Update with a stale version = %v, want ErrVersionConflict", err)
+	}
+	suite.assertExperience(t, "GetByID after rejected Update", mustGetExperience(t, repo, exp.ID), exp)
+}
+
+func testExperienceDeleteChecksVersion(t *testing.T, repo repository.ExperienceRepository) {
+	ctx := context.Background()
+	mustCreateExperience(t, repo, testExperience("e-1", "2024-01-01T00:00:00Z"))
+	if err := repo.Delete(ctx, "e-1", 2); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("Delete with a stale version = %v, want ErrVersionConflict", err)
+	}
+	mustGetExperience(t, repo, "e-1")
+	if err := repo.Delete(ctx, "e-1", 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "e-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID after Delete = %v, want ErrNotFound", err)
+	}
+	if list, err := repo.List(ctx); err != nil || len(list) != 0 {
+		t.Fatalf("List after Delete = %v, %v; want empty", list, err)
+	}
+}
+
+func testExperienceUpdatePositions(t *testing.T, repo repository.ExperienceRepository) {
+	ctx := context.Background()
+	mustCreateExperience(t, repo, testExperience("e-1", "2024-01-01T00:00:00Z"))
+	mustCreateExperience(t, repo, testExperience("e-2", "2024-01-02T00:00:00Z"))
+
+	err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "e-1", Position: 5}, {ID: "missing", Position: 6}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("UpdatePositions with a missing ID = %v, want ErrNotFound", err)
+	}
+	if exp := mustGetExperience(t, repo, "e-1"); exp.Position != 0 || exp.Version != 1 {
+		t.Fatalf("rejected UpdatePositions wrote %+v", exp)
+	}
+
+	pinned := true
+	if err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "e-1", Position: 2, Pinned: &pinned}, {ID: "e-2", Position: 1}}); err != nil {
+		t.Fatalf("UpdatePositions: %v", err)
+	}
+	if exp := mustGetExperience(t, repo, "e-1"); exp.Position != 2 || !exp.Pinned || exp.Version != 2 {
+		t.Fatalf("e-1 after UpdatePositions = %+v", exp)
+	}
+	if exp := mustGetExperience(t, repo, "e-2"); exp.Position != 1 || exp.Pinned || exp.Version != 2 {
+		t.Fatalf("e-2 after UpdatePositions = %+v", exp)
+	}
+}
+
+func testExperienceConcurrentUpdate(t *testing.T, repo repository.ExperienceRepository) {
+	mustCreateExperience(t, repo, testExperience("e-1", "2024-01-01T00:00:00Z"))
+
+	errs := make([]error, Concurrency)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			exp := testExperience("e-1", "2024-01-01T00:00:00Z")
+			exp.Title = fmt.Sprintf("Editor %d", i)
+			errs[i] = repo.Update(context.Background(), exp, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		switch {
+		case err == nil && winner >= 0:
+			t.Fatalf("updates %d and %d both succeeded with the same expected version", winner, i)
+		case err == nil:
+			winner = i
+		case !errors.Is(err, repository.ErrVersionConflict):
+			t.Errorf("Update %d = %v, want nil or ErrVersionConflict", i, err)
+		}
+	}
+	if winner < 0 {
+		t.Fatal("no concurrent update succeeded")
+	}
+	if exp := mustGetExperience(t, repo, "e-1"); exp.Title != fmt.Sprintf("Editor %d", winner) || exp.Version != 2 {
+		t.Fatalf("stored experience = %+v, want the winning update with version 2", exp)
+	}
+}
+
+func testExperienceApplyBatch(t *testing.T, repo repository.ExperienceRepository, suite experienceSuite) {
+	batcher, ok := repo.(repository.BatchExperienceRepository)
+	if !ok {
+		t.Skip("repository does not implement repository.BatchExperienceRepository")
+	}
+	ctx := context.Background()
+	mustCreateExperience(t, repo, testExperience("e-1", "2024-01-01T00:00:00Z"))
+	mustCreateExperience(t, repo, testExperience("e-2", "2024-01-02T00:00:00Z"))
+
+	updated := testExperience("e-1", "2024-01-01T00:00:00Z")
+	updated.Title = "Editada"
+	err := batcher.ApplyBatch(ctx, []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: testExperience("e-3", "2024-01-03T00:00:00Z")},
+		{Kind: repository.WriteUpdate, Experience: updated, ExpectedVersion: 1},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "e-2"}, ExpectedVersion: 7},
+	})
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("ApplyBatch with a stale delete = %v, want ErrVersionConflict", err)
+	}
+	if _, err := repo.GetByID(ctx, "e-3"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("rejected batch created e-3: %v", err)
+	}
+	if exp := mustGetExperience(t, repo, "e-1"); exp.Title == "Editada" {
+		t.Fatal("rejected batch updated e-1")
+	}
+
+	err = batcher.ApplyBatch(ctx, []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: testExperience("e-3", "2024-01-03T00:00:00Z")},
+		{Kind: repository.WriteUpdate, Experience: updated, ExpectedVersion: 1},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "e-2"}, ExpectedVersion: 1},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch: %v", err)
+	}
+	updated.Version = 2
+	suite.assertExperience(t, "e-1 after ApplyBatch", mustGetExperience(t, repo, "e-1"), updated)
+	mustGetExperience(t, repo, "e-3")
+	if _, err := repo.GetByID(ctx, "e-2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID(e-2) after batch delete = %v, want ErrNotFound", err)
+	}
+}
+
+// --- Skills ---
+
+func testSkillNotFound(t *testing.T, repo repository.SkillRepository) {
+	if _, err := repo.GetByID(context.Background(), "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func testSkillCreateRejectsTakenID(t *testing.T, repo repository.SkillRepository) {
+	ctx := context.Background()
+	if err := repo.Create(ctx, models.Skill{ID: "s-1", Name: "Go"}); err != nil {
+		t.Fatalf("Create(s-1): %v", err)
+	}
+	if err := repo.Create(ctx, models.Skill{ID: "s-1", Name: "Rust"}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create with a taken ID = %v, want ErrConflict", err)
+	}
+	if got, err := repo.GetByID(ctx, "s-1"); err != nil || got.Name != "Go" {
+		t.Fatalf("GetByID after rejected create = %+v, %v; want Go", got, err)
+	}
+	skills, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(skills) != 1 {
+		t.Fatalf("List after rejected create = %+v, want one skill", skills)
+	}
+}
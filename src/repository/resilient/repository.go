@@ -0,0 +1,165 @@
+// Package resilientrepo provides decorators that call repositories backed
+// by cloud services through a retry policy and a circuit breaker.
+package resilientrepo
+
+import (
+	"context"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/resilience"
+	"backend-yonathan/src/repository"
+)
+
+// Options configures a resilient repository.
+type Options struct {
+	// Breaker is shared by every repository of the same dependency.
+	Breaker *resilience.Breaker
+	// Policy applies to reads and to idempotent writes. Conditional writes
+	// (Create, Update, Delete, UpdatePositions, ApplyBatch, CreateUser) are
+	// attempted once: a retry after an ambiguous failure could report a
+	// conflict for a write that succeeded.
+	Policy resilience.Policy
+}
+
+// retried calls fn under the policy and breaker.
+func (o Options) retried(ctx context.Context, fn func(ctx context.Context) error) error {
+	return resilience.Do(ctx, o.Breaker, o.Policy, fn)
+}
+
+// once calls fn a single time under the breaker.
+func (o Options) once(ctx context.Context, fn func(ctx context.Context) error) error {
+	return resilience.Do(ctx, o.Breaker, o.Policy.NoRetry(), fn)
+}
+
+// --- Experiences ---
+
+// ExperienceRepository forwards every call to the wrapped repository under
+// the retry policy and breaker of its Options.
+type ExperienceRepository struct {
+	inner repository.ExperienceRepository
+	opts  Options
+}
+
+// batchExperienceRepository adds ApplyBatch when the wrapped repository
+// supports atomic batches.
+type batchExperienceRepository struct {
+	*ExperienceRepository
+	batcher repository.BatchExperienceRepository
+}
+
+// NewExperienceRepository wraps inner. The result implements
+// repository.BatchExperienceRepository exactly when inner does.
+func NewExperienceRepository(inner repository.ExperienceRepository, opts Options) repository.ExperienceRepository {
+	resilient := &ExperienceRepository{inner: inner, opts: opts}
+	if batcher, ok := inner.(repository.BatchExperienceRepository); ok {
+		return &batchExperienceRepository{ExperienceRepository: resilient, batcher: batcher}
+	}
+	return resilient
+}
+
+// List returns all experiences, retrying transient failures.
+func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
+	var experiences []models.Experience
+	err := r.opts.retried(ctx, func(ctx context.Context) (err error) {
+		experiences, err = r.inner.List(ctx)
+		return err
+	})
+	return experiences, err
+}
+
+// GetByID returns an experience, retrying transient failures.
+func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	var exp models.Experience
+	err := r.opts.retried(ctx, func(ctx context.Context) (err error) {
+		exp, err = r.inner.GetByID(ctx, id)
+		return err
+	})
+	return exp, err
+}
+
+// Create stores a new experience.
+func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
+	return r.opts.once(ctx, func(ctx context.Context) error {
+		return r.inner.Create(ctx, exp)
+	})
+}
+
+// Update replaces an experience if its version matches.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	return r.opts.once(ctx, func(ctx context.Context) error {
+		return r.inner.Update(ctx, exp, expectedVersion)
+	})
+}
+
+// Delete removes an experience if its version matches.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	return r.opts.once(ctx, func(ctx context.Context) error {
+		return r.inner.Delete(ctx, id, expectedVersion)
+	})
+}
+
+// UpdatePositions applies all position updates or none.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	return r.opts.once(ctx, func(ctx context.Context) error {
+		return r.inner.UpdatePositions(ctx, updates)
+	})
+}
+
+// ApplyBatch applies all writes or none.
+func (r *batchExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	return r.opts.once(ctx, func(ctx context.Context) error {
+		return r.batcher.ApplyBatch(ctx, writes)
+	})
+}
+
+// --- Users ---
+
+// UserRepository forwards every call to the wrapped repository under the
+// retry policy and breaker of its Options.
+type UserRepository struct {
+	inner repository.UserRepository
+	opts  Options
+}
+
+// NewUserRepository wraps inner.
+func NewUserRepository(inner repository.UserRepository, opts Options) *UserRepository {
+	return &UserRepository{inner: inner, opts: opts}
+}
+
+// CreateUser stores a new user.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	var created models.User
+	err := r.opts.once(ctx, func(ctx context.Context) (err error) {
+		created, err = r.inner.CreateUser(ctx, user)
+		return err
+	})
+	return created, err
+}
+
+// SaveUser inserts or replaces a user, retrying transient failures: saving
+// the same user twice leaves the same result.
+func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
+	return r.opts.retried(ctx, func(ctx context.Context) error {
+		return r.inner.SaveUser(ctx, user)
+	})
+}
+
+// GetUserByID returns a user, retrying transient failures.
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
+	var user models.User
+	err := r.opts.retried(ctx, func(ctx context.Context) (err error) {
+		user, err = r.inner.GetUserByID(ctx, id)
+		return err
+	})
+	return user, err
+}
+
+// GetUserByEmail returns a user, retrying transient failures.
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.opts.retried(ctx, func(ctx context.Context) (err error) {
+		user, err = r.inner.GetUserByEmail(ctx, email)
+		return err
+	})
+	return user, err
+}
@@ -0,0 +1,104 @@
+package resilientrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/pkg/resilience"
+	"backend-yonathan/src/repository"
+	memory "backend-yonathan/src/repository/memory"
+	"backend-yonathan/src/repository/repotest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyRepository fails the first failures calls of every method with a
+// transient error.
+type flakyRepository struct {
+	repository.ExperienceRepository
+	failures int
+	calls    int
+}
+
+func (r *flakyRepository) fail() error {
+	r.calls++
+	if r.calls <= r.failures {
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+	return nil
+}
+
+func (r *flakyRepository) List(ctx context.Context) ([]models.Experience, error) {
+	if err := r.fail(); err != nil {
+		return nil, err
+	}
+	return r.ExperienceRepository.List(ctx)
+}
+
+func (r *flakyRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	if err := r.fail(); err != nil {
+		return err
+	}
+	return r.ExperienceRepository.Update(ctx, exp, expectedVersion)
+}
+
+func testOptions(t *testing.T) Options {
+	return Options{
+		Breaker: resilience.BreakerFor(t.Name(), resilience.BreakerConfig{FailureThreshold: 3, OpenTimeout: time.Minute}),
+		Policy:  resilience.Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+}
+
+func TestReadsAreRetriedAndWritesAreNot(t *testing.T) {
+	inner := &flakyRepository{ExperienceRepository: memory.NewExperienceRepository(), failures: 2}
+	repo := NewExperienceRepository(inner, testOptions(t))
+
+	if _, err := repo.List(context.Background()); err != nil || inner.calls != 3 {
+		t.Fatalf("List = %v after %d calls, want success on the third", err, inner.calls)
+	}
+
+	inner.calls = 0
+	err := repo.Update(context.Background(), models.Experience{ID: "e-1"}, 1)
+	if status.Code(err) != codes.Unavailable || inner.calls != 1 {
+		t.Fatalf("Update = %v after %d calls, want the transient error after 1", err, inner.calls)
+	}
+}
+
+func TestOpenBreakerFailsFast(t *testing.T) {
+	inner := &flakyRepository{ExperienceRepository: memory.NewExperienceRepository(), failures: 100}
+	repo := NewExperienceRepository(inner, testOptions(t))
+
+	if _, err := repo.List(context.Background()); err == nil {
+		t.Fatal("expected List to fail")
+	}
+	calls := inner.calls
+	if _, err := repo.List(context.Background()); !errors.Is(err, resilience.ErrCircuitOpen) || inner.calls != calls {
+		t.Fatalf("List = %v, want ErrCircuitOpen without calling the repository", err)
+	}
+}
+
+func TestBatchSupportFollowsInner(t *testing.T) {
+	if _, ok := NewExperienceRepository(memory.NewExperienceRepository(), testOptions(t)).(repository.BatchExperienceRepository); !ok {
+		t.Fatal("expected ApplyBatch over a batch-capable repository")
+	}
+	inner := &flakyRepository{ExperienceRepository: memory.NewExperienceRepository()}
+	if _, ok := NewExperienceRepository(inner, testOptions(t)).(repository.BatchExperienceRepository); ok {
+		t.Fatal("expected no ApplyBatch over a repository without it")
+	}
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository(memory.NewUserRepository(), testOptions(t))
+	})
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return NewExperienceRepository(memory.NewExperienceRepository(), testOptions(t))
+	})
+}
@@ -0,0 +1,20 @@
+package sqlrepo
+
+import (
+	"testing"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/repotest"
+)
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return &UserRepository{s: newTestStore(t)}
+	})
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return &ExperienceRepository{s: newTestStore(t)}
+	})
+}
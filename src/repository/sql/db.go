@@ -0,0 +1,231 @@
+// Package sqlrepo implements the user and experience repositories over
+// database/sql, with PostgreSQL (pgx) and SQLite (modernc.org/sqlite)
+// dialects. The schema is created by embedded, versioned migrations applied
+// when a store is opened.
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Injectable database opener (swap in tests).
+var openDBFunc = sql.Open
+
+// migrationLockID serializes PostgreSQL migrations across instances
+// starting at the same time (pg_advisory_xact_lock key).
+const migrationLockID = 7_402_813
+
+// dialect holds the differences between the supported databases.
+type dialect struct {
+	name   string
+	driver string
+	// numbered reports whether placeholders are $1, $2... instead of ?.
+	numbered        bool
+	uniqueViolation func(error) bool
+}
+
+var postgresDialect = dialect{
+	name:     "postgres",
+	driver:   "pgx",
+	numbered: true,
+	uniqueViolation: func(err error) bool {
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == "23505"
+	},
+}
+
+var sqliteDialect = dialect{
+	name:   "sqlite",
+	driver: "sqlite",
+	uniqueViolation: func(err error) bool {
+		var sqliteErr *sqlite.Error
+		if !errors.As(err, &sqliteErr) {
+			return false
+		}
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	},
+}
+
+// rebind rewrites ? placeholders for dialects with numbered placeholders.
+// Queries in this package never contain literal question marks.
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// store is an open, migrated database shared by the repositories of one DSN.
+type store struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func (s *store) exec(ctx context.Context, q querier, query string, args ...any) (sql.Result, error) {
+	return q.ExecContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *store) queryRow(ctx context.Context, q querier, query string, args ...any) *sql.Row {
+	return q.QueryRowContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *store) query(ctx context.Context, q querier, query string, args ...any) (*sql.Rows, error) {
+	return q.QueryContext(ctx, s.dialect.rebind(query), args...)
+}
+
+// inTx runs fn in a transaction, committing only if it returns nil.
+func (s *store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// openStore opens and migrates a database.
+func openStore(ctx context.Context, d dialect, source string) (*store, error) {
+	db, err := openDBFunc(d.driver, source)
+	if err != nil {
+		return nil, err
+	}
+	if d.name == sqliteDialect.name {
+		// SQLite allows a single writer; one connection avoids SQLITE_BUSY
+		// and keeps :memory: databases on a single connection.
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	s := &store{db: db, dialect: d}
+	if err := s.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return s, nil
+}
+
+// migration is one embedded schema file, named <version>_<description>.sql.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, found := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		content, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(content)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// statements splits a migration file into statements terminated by ";" at
+// the end of a line.
+func statements(script string) []string {
+	result := []string{}
+	for _, part := range strings.Split(script, ";\n") {
+		if stmt := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), ";")); stmt != "" {
+			result = append(result, stmt)
+		}
+	}
+	return result
+}
+
+// migrate applies pending migrations in order, each in its own transaction
+// together with its schema_migrations row.
+func (s *store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    applied_at TEXT NOT NULL
+)`); err != nil {
+		return err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			if s.dialect.name == postgresDialect.name {
+				if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+					return err
+				}
+			}
+			var applied int
+			if err := s.queryRow(ctx, tx, "SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.version).Scan(&applied); err != nil {
+				return err
+			}
+			if applied > 0 {
+				return nil
+			}
+			for _, stmt := range statements(m.sql) {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return fmt.Errorf("%s: %w", m.name, err)
+				}
+			}
+			_, err := s.exec(ctx, tx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.version, m.name, time.Now().UTC().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+const experienceColumns = "id, title, summary, body, image_urls, tags, related_ids, visibility, position, pinned, version, created_at, updated_at, tenant_id"
+
+// ExperienceRepository is the SQL implementation of repository.ExperienceRepository.
+type ExperienceRepository struct {
+	s *store
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func encodeList(values []string) (string, error) {
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+func scanExperience(row rowScanner) (models.Experience, error) {
+	var exp models.Experience
+	var images, tags, related string
+	if err := row.Scan(&exp.ID, &exp.Title, &exp.Summary, &exp.Body, &images, &tags, &related,
+		&exp.Visibility, &exp.Position, &exp.Pinned, &exp.Version, &exp.CreatedAt, &exp.UpdatedAt, &exp.TenantID); err != nil {
+		return exp, err
+	}
+	for _, list := range []struct {
+		raw    string
+		target *[]string
+	}{{images, &exp.ImageURLs}, {tags, &exp.Tags}, {related, &exp.RelatedIDs}} {
+		if err := json.Unmarshal([]byte(list.raw), list.target); err != nil {
+			return exp, fmt.Errorf("experience %s: %w", exp.ID, err)
+		}
+	}
+	return exp, nil
+}
+
+// experienceArgs returns the column values of exp in experienceColumns order.
+func experienceArgs(exp models.Experience) ([]any, error) {
+	images, err := encodeList(exp.ImageURLs)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := encodeList(exp.Tags)
+	if err != nil {
+		return nil, err
+	}
+	related, err := encodeList(exp.RelatedIDs)
+	if err != nil {
+		return nil, err
+	}
+	return []any{exp.ID, exp.Title, exp.Summary, exp.Body, images, tags, related,
+		exp.Visibility, exp.Position, exp.Pinned, exp.Version, exp.CreatedAt, exp.UpdatedAt, exp.TenantID}, nil
+}
+
+// List returns all experiences ordered by creation date and ID.
+func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
+	rows, err := r.s.query(ctx, r.s.db, "SELECT "+experienceColumns+" FROM experiences ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	experiences := make([]models.Experience, 0)
+	for rows.Next() {
+		exp, err := scanExperience(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiences = append(experiences, exp)
+	}
+	return experiences, rows.Err()
+}
+
+// GetByID returns an experience by ID.
+func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	exp, err := scanExperience(r.s.queryRow(ctx, r.s.db, "SELECT "+experienceColumns+" FROM experiences WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Experience{}, fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	}
+	return exp, err
+}
+
+func (r *ExperienceRepository) insert(ctx context.Context, q querier, exp models.Experience) error {
+	args, err := experienceArgs(exp)
+	if err != nil {
+		return err
+	}
+	_, err = r.s.exec(ctx, q, "INSERT INTO experiences ("+experienceColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", args...)
+	if err != nil && r.s.dialect.uniqueViolation(err) {
+		return fmt.Errorf("%w: experience %s already exists", repository.ErrVersionConflict, exp.ID)
+	}
+	return err
+}
+
+// Create stores a new experience. It fails with ErrVersionConflict if the ID
+// is already taken.
+func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
+	return r.insert(ctx, r.s.db, exp)
+}
+
+// missingOrConflict explains why a conditional write on id matched no row.
+func (r *ExperienceRepository) missingOrConflict(ctx context.Context, q querier, id string) error {
+	var version int64
+	err := r.s.queryRow(ctx, q, "SELECT version FROM experiences WHERE id = ?", id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: experience %s", repository.ErrVersionConflict, id)
+}
+
+func (r *ExperienceRepository) update(ctx context.Context, q querier, exp models.Experience, expectedVersion int64) error {
+	exp.Version = expectedVersion + 1
+	args, err := experienceArgs(exp)
+	if err != nil {
+		return err
+	}
+	// Drop the id; it goes in the WHERE clause.
+	args = append(args[1:], exp.ID, expectedVersion)
+	result, err := r.s.exec(ctx, q, `UPDATE experiences SET title = ?, summary = ?, body = ?, image_urls = ?, tags = ?, related_ids = ?,
+visibility = ?, position = ?, pinned = ?, version = ?, created_at = ?, updated_at = ?, tenant_id = ? WHERE id = ? AND version = ?`, args...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		if err != nil {
+			return err
+		}
+		return r.missingOrConflict(ctx, q, exp.ID)
+	}
+	return nil
+}
+
+// Update replaces an experience only if its stored version matches expectedVersion.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	return r.update(ctx, r.s.db, exp, expectedVersion)
+}
+
+func (r *ExperienceRepository) delete(ctx context.Context, q querier, id string, expectedVersion int64) error {
+	result, err := r.s.exec(ctx, q, "DELETE FROM experiences WHERE id = ? AND version = ?", id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		if err != nil {
+			return err
+		}
+		return r.missingOrConflict(ctx, q, id)
+	}
+	return nil
+}
+
+// Delete removes an experience only if its stored version matches expectedVersion.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	return r.delete(ctx, r.s.db, id, expectedVersion)
+}
+
+// UpdatePositions applies all position updates in one transaction.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	return r.s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, u := range updates {
+			result, err := r.s.exec(ctx, tx, "UPDATE experiences SET position = ?, pinned = COALESCE(?, pinned), version = version + 1 WHERE id = ?",
+				u.Position, u.Pinned, u.ID)
+			if err != nil {
+				return err
+			}
+			if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+				if err != nil {
+					return err
+				}
+				return fmt.Errorf("%w: experience %s", repository.ErrNotFound, u.ID)
+			}
+		}
+		return nil
+	})
+}
+
+// ApplyBatch validates the writes against the stored versions and applies
+// them in one transaction; every update and delete is still conditioned on
+// its expected version, so a concurrent change rolls the whole batch back.
+func (r *ExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	return r.s.inTx(ctx, func(tx *sql.Tx) error {
+		versions := map[string]int64{}
+		for _, w := range writes {
+			id := w.Experience.ID
+			if _, known := versions[id]; known {
+				continue
+			}
+			var version int64
+			err := r.s.queryRow(ctx, tx, "SELECT version FROM experiences WHERE id = ?", id).Scan(&version)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			versions[id] = version
+		}
+		if err := repository.CheckBatch(versions, writes); err != nil {
+			return err
+		}
+
+		for _, w := range writes {
+			var err error
+			switch w.Kind {
+			case repository.WriteCreate:
+				err = r.insert(ctx, tx, w.Experience)
+			case repository.WriteUpdate:
+				err = r.update(ctx, tx, w.Experience, w.ExpectedVersion)
+			case repository.WriteDelete:
+				err = r.delete(ctx, tx, w.Experience.ID, w.ExpectedVersion)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
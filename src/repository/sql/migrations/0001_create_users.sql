@@ -0,0 +1,8 @@
+CREATE TABLE users (
+    user_id  TEXT PRIMARY KEY,
+    email    TEXT NOT NULL,
+    password TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX users_email_key ON users (email);
@@ -0,0 +1,18 @@
+-- List columns (image_urls, tags, related_ids) hold JSON arrays.
+CREATE TABLE experiences (
+    id          TEXT PRIMARY KEY,
+    title       TEXT NOT NULL,
+    summary     TEXT NOT NULL DEFAULT '',
+    body        TEXT NOT NULL DEFAULT '',
+    image_urls  TEXT NOT NULL DEFAULT '[]',
+    tags        TEXT NOT NULL DEFAULT '[]',
+    related_ids TEXT NOT NULL DEFAULT '[]',
+    visibility  TEXT NOT NULL DEFAULT 'public',
+    position    INTEGER NOT NULL DEFAULT 0,
+    pinned      BOOLEAN NOT NULL DEFAULT FALSE,
+    version     BIGINT NOT NULL DEFAULT 1,
+    created_at  TEXT NOT NULL DEFAULT '',
+    updated_at  TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX experiences_created_at_idx ON experiences (created_at, id);
@@ -0,0 +1,2 @@
+-- Owner of each experience; '' marks experiences created before tenants.
+ALTER TABLE experiences ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Sealed sensitive fields of each user; '' when field encryption is off.
+ALTER TABLE users ADD COLUMN encrypted TEXT NOT NULL DEFAULT '';
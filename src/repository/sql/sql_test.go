@@ -0,0 +1,240 @@
+package sqlrepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+func newTestStore(t *testing.T) *store {
+	t.Helper()
+	dsn, err := repository.ParseDSN("sqlite:" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := sqliteSource(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := openStore(context.Background(), sqliteDialect, source)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	return s
+}
+
+func TestRebind(t *testing.T) {
+	query := "UPDATE t SET a = ? WHERE id = ? AND version = ?"
+	if got := sqliteDialect.rebind(query); got != query {
+		t.Errorf("sqlite rebind changed query: %q", got)
+	}
+	if got, want := postgresDialect.rebind(query), "UPDATE t SET a = $1 WHERE id = $2 AND version = $3"; got != want {
+		t.Errorf("postgres rebind = %q, want %q", got, want)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	if err := s.migrate(ctx); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var applied int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("applied %d migrations, want %d", applied, len(migrations))
+	}
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := &UserRepository{s: newTestStore(t)}
+
+	user, err := repo.CreateUser(ctx, models.User{Email: "ana@example.com", Password: "hash", UserName: "ana"})
+	if err != nil || user.UserId == "" {
+		t.Fatalf("CreateUser = %+v, %v", user, err)
+	}
+	user.UserName = "ana maria"
+	if err := repo.SaveUser(ctx, user); err != nil {
+		t.Fatalf("SaveUser update: %v", err)
+	}
+	got, err := repo.GetUserByEmail(ctx, "ana@example.com")
+	if err != nil || got.UserId != user.UserId || got.UserName != "ana maria" {
+		t.Fatalf("GetUserByEmail = %+v, %v", got, err)
+	}
+
+	duplicate := models.User{UserId: "u2", Email: "ana@example.com", Password: "hash", UserName: "otra"}
+	if err := repo.SaveUser(ctx, duplicate); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("duplicate email error = %v, want ErrConflict", err)
+	}
+	if _, err := repo.CreateUser(ctx, duplicate); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("CreateUser duplicate email error = %v, want ErrConflict", err)
+	}
+	if _, err := repo.GetUserByID(ctx, "u2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetUserByID missing error = %v, want ErrNotFound", err)
+	}
+	if users, err := repo.ListUsers(ctx); err != nil || len(users) != 1 || users[0].UserName != "ana maria" {
+		t.Fatalf("ListUsers = %+v, %v", users, err)
+	}
+}
+
+func testExperience(id, createdAt string) models.Experience {
+	return models.Experience{
+		ID:         id,
+		Title:      "Titulo " + id,
+		Tags:       []string{"go", "sql"},
+		ImageURLs:  []string{"https://example.com/a.png"},
+		Visibility: "public",
+		Version:    1,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
+func TestExperienceRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := &ExperienceRepository{s: newTestStore(t)}
+
+	if err := repo.Create(ctx, testExperience("b", "2024-02-01T00:00:00Z")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, testExperience("a", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, testExperience("a", "2024-01-01T00:00:00Z")); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("duplicate Create error = %v, want ErrVersionConflict", err)
+	}
+
+	list, err := repo.List(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != "a" || list[0].Tags[1] != "sql" || list[0].RelatedIDs != nil {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+
+	updated := list[0]
+	updated.Title = "Nuevo"
+	if err := repo.Update(ctx, updated, 1); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := repo.Update(ctx, updated, 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("stale Update error = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.Update(ctx, testExperience("missing", ""), 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("missing Update error = %v, want ErrNotFound", err)
+	}
+	got, err := repo.GetByID(ctx, "a")
+	if err != nil || got.Title != "Nuevo" || got.Version != 2 {
+		t.Fatalf("GetByID = %+v, %v", got, err)
+	}
+
+	if err := repo.Delete(ctx, "a", 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("stale Delete error = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.Delete(ctx, "a", 2); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "a"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID deleted error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExperienceRepositoryUpdatePositionsIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	repo := &ExperienceRepository{s: newTestStore(t)}
+	if err := repo.Create(ctx, testExperience("a", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := true
+	err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "a", Position: 3, Pinned: &pinned}, {ID: "missing", Position: 1}})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("UpdatePositions error = %v, want ErrNotFound", err)
+	}
+	if got, _ := repo.GetByID(ctx, "a"); got.Position != 0 || got.Pinned {
+		t.Fatalf("failed UpdatePositions changed %+v", got)
+	}
+
+	if err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "a", Position: 3, Pinned: &pinned}}); err != nil {
+		t.Fatalf("UpdatePositions: %v", err)
+	}
+	if err := repo.UpdatePositions(ctx, []repository.PositionUpdate{{ID: "a", Position: 4}}); err != nil {
+		t.Fatalf("UpdatePositions: %v", err)
+	}
+	if got, _ := repo.GetByID(ctx, "a"); got.Position != 4 || !got.Pinned || got.Version != 3 {
+		t.Fatalf("GetByID after UpdatePositions = %+v", got)
+	}
+}
+
+func TestExperienceRepositoryApplyBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := &ExperienceRepository{s: newTestStore(t)}
+	if err := repo.Create(ctx, testExperience("a", "2024-01-01T00:00:00Z")); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: testExperience("b", "2024-02-01T00:00:00Z")},
+		{Kind: repository.WriteUpdate, Experience: testExperience("a", "2024-01-01T00:00:00Z"), ExpectedVersion: 5},
+	}
+	if err := repo.ApplyBatch(ctx, failing); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("ApplyBatch error = %v, want ErrVersionConflict", err)
+	}
+	if _, err := repo.GetByID(ctx, "b"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("failed batch persisted a write: %v", err)
+	}
+
+	writes := []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: testExperience("b", "2024-02-01T00:00:00Z")},
+		{Kind: repository.WriteUpdate, Experience: testExperience("b", "2024-02-01T00:00:00Z"), ExpectedVersion: 1},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "a"}, ExpectedVersion: 1},
+	}
+	if err := repo.ApplyBatch(ctx, writes); err != nil {
+		t.Fatalf("ApplyBatch: %v", err)
+	}
+	list, err := repo.List(ctx)
+	if err != nil || len(list) != 1 || list[0].ID != "b" || list[0].Version != 2 {
+		t.Fatalf("List after batch = %+v, %v", list, err)
+	}
+}
+
+func TestSQLiteBackendDefaultsToDataDir(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "data")
+	t.Setenv("PORTFOLIO_DATA_DIR", dir)
+	dsn, err := repository.ParseDSN("sqlite:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := backend(sqliteDialect, sqliteSource)
+	users, err := b.Users(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Users: %v", err)
+	}
+	experiences, err := b.Experiences(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Experiences: %v", err)
+	}
+	shared := users.(*UserRepository).s
+	t.Cleanup(func() { shared.db.Close() })
+	if experiences.(*ExperienceRepository).s != shared {
+		t.Error("stores of the same DSN should share one database")
+	}
+	if err := users.SaveUser(ctx, models.User{UserId: "u1", Email: "a@example.com"}); err != nil {
+		t.Fatalf("SaveUser: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "portfolio.db")); err != nil {
+		t.Fatalf("database file: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserRepository is the SQL implementation of repository.UserRepository.
+type UserRepository struct {
+	s *store
+}
+
+// CreateUser inserts a new user; the unique index on email rejects
+// duplicates atomically with an error wrapping repository.ErrConflict.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	_, err := r.s.exec(ctx, r.s.db, "INSERT INTO users (user_id, email, password, username, encrypted) VALUES (?, ?, ?, ?, ?)",
+		user.UserId, user.Email, user.Password, user.UserName, user.Encrypted)
+	if err != nil {
+		if r.s.dialect.uniqueViolation(err) {
+			return models.User{}, fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// SaveUser inserts or replaces a user by ID. If UserId is empty a new UUID is
+// generated. An email already used by another user returns an error wrapping
+// repository.ErrConflict.
+func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
+	if user.UserId == "" {
+		user.UserId = uuid.NewString()
+	}
+	_, err := r.s.exec(ctx, r.s.db, `INSERT INTO users (user_id, email, password, username, encrypted) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET email = excluded.email, password = excluded.password, username = excluded.username, encrypted = excluded.encrypted`,
+		user.UserId, user.Email, user.Password, user.UserName, user.Encrypted)
+	if err != nil && r.s.dialect.uniqueViolation(err) {
+		return fmt.Errorf("%w: email %s already registered", repository.ErrConflict, user.Email)
+	}
+	return err
+}
+
+func (r *UserRepository) getOne(ctx context.Context, where, arg, label string) (models.User, error) {
+	var user models.User
+	err := r.s.queryRow(ctx, r.s.db, "SELECT user_id, email, password, username, encrypted FROM users WHERE "+where+" = ?", arg).
+		Scan(&user.UserId, &user.Email, &user.Password, &user.UserName, &user.Encrypted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, fmt.Errorf("%w: %s %s", repository.ErrNotFound, label, arg)
+	}
+	return user, err
+}
+
+// ListUsers returns all users ordered by ID.
+func (r *UserRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	rows, err := r.s.query(ctx, r.s.db, "SELECT user_id, email, password, username, encrypted FROM users ORDER BY user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserId, &user.Email, &user.Password, &user.UserName, &user.Encrypted); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// GetUserByID returns the user with the given ID.
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (models.User, error) {
+	return r.getOne(ctx, "user_id", id, "user")
+}
+
+// GetUserByEmail returns the user with the given email.
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.getOne(ctx, "email", email, "user with email")
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose repository calls act on the portfolio
+// of tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant stored in ctx by WithTenant.
+func TenantFrom(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
@@ -0,0 +1,171 @@
+// Package tenantrepo scopes experience, skill and tag alias repositories to
+// the tenant carried by the context of each call (see repository.WithTenant),
+// so several portfolios can share one store.
+package tenantrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+// ExperienceRepository only sees and changes the experiences of the calling
+// tenant. Experiences of other tenants behave as if they did not exist.
+// Experiences without a TenantID, stored before tenants existed, belong to
+// the default tenant, which is also used for calls without a tenant.
+type ExperienceRepository struct {
+	inner         repository.ExperienceRepository
+	defaultTenant string
+}
+
+// batchExperienceRepository adds ApplyBatch when the wrapped repository
+// supports atomic batches.
+type batchExperienceRepository struct {
+	*ExperienceRepository
+	batcher repository.BatchExperienceRepository
+}
+
+// NewExperienceRepository wraps inner with tenant scoping. The result
+// implements repository.BatchExperienceRepository exactly when inner does.
+func NewExperienceRepository(inner repository.ExperienceRepository, defaultTenant string) repository.ExperienceRepository {
+	scoped := &ExperienceRepository{inner: inner, defaultTenant: defaultTenant}
+	if batcher, ok := inner.(repository.BatchExperienceRepository); ok {
+		return &batchExperienceRepository{ExperienceRepository: scoped, batcher: batcher}
+	}
+	return scoped
+}
+
+func (r *ExperienceRepository) tenant(ctx context.Context) string {
+	if tenant, ok := repository.TenantFrom(ctx); ok {
+		return tenant
+	}
+	return r.defaultTenant
+}
+
+func (r *ExperienceRepository) owner(exp models.Experience) string {
+	if exp.TenantID == "" {
+		return r.defaultTenant
+	}
+	return exp.TenantID
+}
+
+func notFound(id string) error {
+	return fmt.Errorf("%w: experience %s", repository.ErrNotFound, id)
+}
+
+// List returns the experiences of the calling tenant.
+func (r *ExperienceRepository) List(ctx context.Context) ([]models.Experience, error) {
+	all, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenant := r.tenant(ctx)
+	owned := make([]models.Experience, 0, len(all))
+	for _, exp := range all {
+		if r.owner(exp) == tenant {
+			owned = append(owned, exp)
+		}
+	}
+	return owned, nil
+}
+
+// GetByID returns an experience of the calling tenant.
+func (r *ExperienceRepository) GetByID(ctx context.Context, id string) (models.Experience, error) {
+	exp, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return models.Experience{}, err
+	}
+	if r.owner(exp) != r.tenant(ctx) {
+		return models.Experience{}, notFound(id)
+	}
+	return exp, nil
+}
+
+// Create stores exp for the calling tenant. An ID already used by any
+// tenant fails with ErrVersionConflict instead of overwriting it.
+func (r *ExperienceRepository) Create(ctx context.Context, exp models.Experience) error {
+	if _, err := r.inner.GetByID(ctx, exp.ID); err == nil {
+		return fmt.Errorf("%w: experience %s already exists", repository.ErrVersionConflict, exp.ID)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	exp.TenantID = r.tenant(ctx)
+	return r.inner.Create(ctx, exp)
+}
+
+// Update replaces an experience of the calling tenant, keeping its owner.
+func (r *ExperienceRepository) Update(ctx context.Context, exp models.Experience, expectedVersion int64) error {
+	existing, err := r.GetByID(ctx, exp.ID)
+	if err != nil {
+		return err
+	}
+	exp.TenantID = existing.TenantID
+	return r.inner.Update(ctx, exp, expectedVersion)
+}
+
+// Delete removes an experience of the calling tenant.
+func (r *ExperienceRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id, expectedVersion)
+}
+
+// stored maps the ID of every stored experience, of any tenant, to it.
+func (r *ExperienceRepository) stored(ctx context.Context) (map[string]models.Experience, error) {
+	all, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Experience, len(all))
+	for _, exp := range all {
+		byID[exp.ID] = exp
+	}
+	return byID, nil
+}
+
+// UpdatePositions applies the updates if every ID belongs to the calling
+// tenant; otherwise nothing is written.
+func (r *ExperienceRepository) UpdatePositions(ctx context.Context, updates []repository.PositionUpdate) error {
+	stored, err := r.stored(ctx)
+	if err != nil {
+		return err
+	}
+	tenant := r.tenant(ctx)
+	for _, update := range updates {
+		if exp, ok := stored[update.ID]; !ok || r.owner(exp) != tenant {
+			return notFound(update.ID)
+		}
+	}
+	return r.inner.UpdatePositions(ctx, updates)
+}
+
+// ApplyBatch stamps created experiences with the calling tenant and rejects
+// the whole batch when it touches an experience of another tenant.
+func (r *batchExperienceRepository) ApplyBatch(ctx context.Context, writes []repository.ExperienceWrite) error {
+	stored, err := r.stored(ctx)
+	if err != nil {
+		return err
+	}
+	tenant := r.tenant(ctx)
+	scoped := make([]repository.ExperienceWrite, len(writes))
+	for i, w := range writes {
+		exp, exists := stored[w.Experience.ID]
+		switch {
+		case exists && r.owner(exp) != tenant:
+			if w.Kind == repository.WriteCreate {
+				return fmt.Errorf("%w: experience %s already exists", repository.ErrVersionConflict, w.Experience.ID)
+			}
+			return notFound(w.Experience.ID)
+		case exists:
+			w.Experience.TenantID = exp.TenantID
+		default:
+			w.Experience.TenantID = tenant
+		}
+		scoped[i] = w
+	}
+	return r.batcher.ApplyBatch(ctx, scoped)
+}
@@ -0,0 +1,107 @@
+package tenantrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+	"backend-yonathan/src/repository/repotest"
+)
+
+func TestScopesExperiencesByTenant(t *testing.T) {
+	inner := memory.NewExperienceRepository()
+	_ = inner.Create(context.Background(), models.Experience{ID: "legacy", Title: "Antigua", Version: 1})
+	repo := NewExperienceRepository(inner, "ana")
+	ana := repository.WithTenant(context.Background(), "ana")
+	bob := repository.WithTenant(context.Background(), "bob")
+
+	if err := repo.Create(bob, models.Experience{ID: "b1", Title: "De Bob", Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := inner.GetByID(context.Background(), "b1"); stored.TenantID != "bob" {
+		t.Fatalf("created experience tenant = %q, want bob", stored.TenantID)
+	}
+
+	if list, _ := repo.List(ana); len(list) != 1 || list[0].ID != "legacy" {
+		t.Fatalf("ana list = %+v, want only the legacy experience", list)
+	}
+	if list, _ := repo.List(context.Background()); len(list) != 1 || list[0].ID != "legacy" {
+		t.Fatalf("list without tenant = %+v, want the default tenant", list)
+	}
+	if _, err := repo.GetByID(ana, "b1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Update(ana, models.Experience{ID: "b1", Title: "Robada"}, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ana, "b1", 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Delete of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Create(ana, models.Experience{ID: "b1", Title: "Copia"}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("Create with a taken ID = %v, want ErrVersionConflict", err)
+	}
+	if err := repo.UpdatePositions(ana, []repository.PositionUpdate{{ID: "legacy", Position: 2}, {ID: "b1", Position: 1}}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("UpdatePositions across tenants = %v, want ErrNotFound", err)
+	}
+	if stored, _ := inner.GetByID(context.Background(), "legacy"); stored.Position != 0 {
+		t.Fatalf("rejected UpdatePositions wrote position %d", stored.Position)
+	}
+
+	if err := repo.Update(bob, models.Experience{ID: "b1", Title: "Editada"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := repo.GetByID(bob, "b1"); stored.Title != "Editada" || stored.TenantID != "bob" {
+		t.Fatalf("updated experience = %+v", stored)
+	}
+}
+
+func TestApplyBatchStampsTenantAndRejectsForeignItems(t *testing.T) {
+	inner := memory.NewExperienceRepository()
+	_ = inner.Create(context.Background(), models.Experience{ID: "b1", TenantID: "bob", Title: "De Bob", Version: 1})
+	repo := NewExperienceRepository(inner, "").(repository.BatchExperienceRepository)
+	ana := repository.WithTenant(context.Background(), "ana")
+
+	err := repo.ApplyBatch(ana, []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: models.Experience{ID: "a1", Title: "Nueva", Version: 1}},
+		{Kind: repository.WriteDelete, Experience: models.Experience{ID: "b1"}, ExpectedVersion: 1},
+	})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("batch touching another tenant = %v, want ErrNotFound", err)
+	}
+	if _, err := inner.GetByID(context.Background(), "a1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatal("rejected batch wrote an experience")
+	}
+
+	if err := repo.ApplyBatch(ana, []repository.ExperienceWrite{
+		{Kind: repository.WriteCreate, Experience: models.Experience{ID: "a1", Title: "Nueva", Version: 1}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := inner.GetByID(context.Background(), "a1"); stored.TenantID != "ana" {
+		t.Fatalf("batch created experience tenant = %q, want ana", stored.TenantID)
+	}
+}
+
+func TestExperienceRepositoryConformance(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		return NewExperienceRepository(memory.NewExperienceRepository(), "ana")
+	}, repotest.StampsTenant("ana"))
+}
+
+// The suite runs without a tenant in ctx, so it works on the default
+// tenant; experiences of another tenant in the same store must not change
+// any of its results.
+func TestExperienceRepositoryConformanceWithOtherTenants(t *testing.T) {
+	repotest.RunExperienceRepositoryTests(t, func(t *testing.T) repository.ExperienceRepository {
+		inner := memory.NewExperienceRepository()
+		for _, id := range []string{"bob-1", "bob-2"} {
+			if err := inner.Create(context.Background(), models.Experience{ID: id, TenantID: "bob", Title: "De Bob", Version: 1}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewExperienceRepository(inner, "ana")
+	}, repotest.StampsTenant("ana"))
+}
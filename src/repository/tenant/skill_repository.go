@@ -0,0 +1,95 @@
+package tenantrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+)
+
+// SkillRepository only sees and changes the catalog skills of the calling
+// tenant, with the same rules as ExperienceRepository: skills without a
+// TenantID belong to the default tenant.
+type SkillRepository struct {
+	inner         repository.SkillRepository
+	defaultTenant string
+}
+
+// NewSkillRepository wraps inner with tenant scoping.
+func NewSkillRepository(inner repository.SkillRepository, defaultTenant string) *SkillRepository {
+	return &SkillRepository{inner: inner, defaultTenant: defaultTenant}
+}
+
+func (r *SkillRepository) tenant(ctx context.Context) string {
+	if tenant, ok := repository.TenantFrom(ctx); ok {
+		return tenant
+	}
+	return r.defaultTenant
+}
+
+func (r *SkillRepository) owner(skill models.Skill) string {
+	if skill.TenantID == "" {
+		return r.defaultTenant
+	}
+	return skill.TenantID
+}
+
+// List returns the skills of the calling tenant.
+func (r *SkillRepository) List(ctx context.Context) ([]models.Skill, error) {
+	all, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenant := r.tenant(ctx)
+	owned := make([]models.Skill, 0, len(all))
+	for _, skill := range all {
+		if r.owner(skill) == tenant {
+			owned = append(owned, skill)
+		}
+	}
+	return owned, nil
+}
+
+// GetByID returns a skill of the calling tenant.
+func (r *SkillRepository) GetByID(ctx context.Context, id string) (models.Skill, error) {
+	skill, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return models.Skill{}, err
+	}
+	if r.owner(skill) != r.tenant(ctx) {
+		return models.Skill{}, fmt.Errorf("%w: skill %s", repository.ErrNotFound, id)
+	}
+	return skill, nil
+}
+
+// Create stores skill for the calling tenant. An ID already used by any
+// tenant fails with ErrConflict instead of overwriting it.
+func (r *SkillRepository) Create(ctx context.Context, skill models.Skill) error {
+	if _, err := r.inner.GetByID(ctx, skill.ID); err == nil {
+		return fmt.Errorf("%w: skill %s already exists", repository.ErrConflict, skill.ID)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	skill.TenantID = r.tenant(ctx)
+	return r.inner.Create(ctx, skill)
+}
+
+// Update replaces a skill of the calling tenant, keeping its owner.
+func (r *SkillRepository) Update(ctx context.Context, skill models.Skill) error {
+	existing, err := r.GetByID(ctx, skill.ID)
+	if err != nil {
+		return err
+	}
+	skill.TenantID = existing.TenantID
+	return r.inner.Update(ctx, skill)
+}
+
+// Delete removes a skill of the calling tenant.
+func (r *SkillRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
@@ -0,0 +1,47 @@
+package tenantrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	models "backend-yonathan/src/models"
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+)
+
+func TestScopesSkillsByTenant(t *testing.T) {
+	inner := memory.NewSkillRepository()
+	_ = inner.Create(context.Background(), models.Skill{ID: "legacy", Name: "Go"})
+	repo := NewSkillRepository(inner, "ana")
+	ana := repository.WithTenant(context.Background(), "ana")
+	bob := repository.WithTenant(context.Background(), "bob")
+
+	if err := repo.Create(bob, models.Skill{ID: "b1", Name: "Rust"}); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := inner.GetByID(context.Background(), "b1"); stored.TenantID != "bob" {
+		t.Fatalf("created skill tenant = %q, want bob", stored.TenantID)
+	}
+	if list, _ := repo.List(ana); len(list) != 1 || list[0].ID != "legacy" {
+		t.Fatalf("ana list = %+v, want only the legacy skill", list)
+	}
+	if _, err := repo.GetByID(ana, "b1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Update(ana, models.Skill{ID: "b1", Name: "Robada"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ana, "b1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Delete of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.Create(ana, models.Skill{ID: "b1", Name: "Copia"}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create with a taken ID = %v, want ErrConflict", err)
+	}
+	if err := repo.Update(bob, models.Skill{ID: "b1", Name: "Rust 2"}); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := repo.GetByID(bob, "b1"); stored.Name != "Rust 2" || stored.TenantID != "bob" {
+		t.Fatalf("updated skill = %+v", stored)
+	}
+}
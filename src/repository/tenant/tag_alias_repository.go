@@ -0,0 +1,63 @@
+package tenantrepo
+
+import (
+	"context"
+	"strings"
+
+	"backend-yonathan/src/repository"
+)
+
+// aliasSeparator separates the tenant from the alias in stored keys. Tags
+// are plain text, so a control character cannot clash with them.
+const aliasSeparator = "\x1f"
+
+// TagAliasRepository gives each tenant its own tag aliases in one store.
+// Aliases of the default tenant are stored under their plain name, as
+// before tenants existed; those of other tenants under "<tenant>\x1f<alias>".
+type TagAliasRepository struct {
+	inner         repository.TagAliasRepository
+	defaultTenant string
+}
+
+// NewTagAliasRepository wraps inner with tenant scoping.
+func NewTagAliasRepository(inner repository.TagAliasRepository, defaultTenant string) *TagAliasRepository {
+	return &TagAliasRepository{inner: inner, defaultTenant: defaultTenant}
+}
+
+// key returns the stored key of alias for the calling tenant.
+func (r *TagAliasRepository) key(ctx context.Context, alias string) string {
+	tenant, ok := repository.TenantFrom(ctx)
+	if !ok || tenant == r.defaultTenant {
+		return alias
+	}
+	return tenant + aliasSeparator + alias
+}
+
+// ListAliases returns the aliases of the calling tenant.
+func (r *TagAliasRepository) ListAliases(ctx context.Context) (map[string]string, error) {
+	all, err := r.inner.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := r.key(ctx, "")
+	owned := map[string]string{}
+	for key, canonical := range all {
+		switch {
+		case prefix == "" && !strings.Contains(key, aliasSeparator):
+			owned[key] = canonical
+		case prefix != "" && strings.HasPrefix(key, prefix):
+			owned[strings.TrimPrefix(key, prefix)] = canonical
+		}
+	}
+	return owned, nil
+}
+
+// SaveAlias creates or replaces an alias of the calling tenant.
+func (r *TagAliasRepository) SaveAlias(ctx context.Context, alias, canonical string) error {
+	return r.inner.SaveAlias(ctx, r.key(ctx, alias), canonical)
+}
+
+// DeleteAlias removes an alias of the calling tenant.
+func (r *TagAliasRepository) DeleteAlias(ctx context.Context, alias string) error {
+	return r.inner.DeleteAlias(ctx, r.key(ctx, alias))
+}
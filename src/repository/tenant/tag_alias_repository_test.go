@@ -0,0 +1,40 @@
+package tenantrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend-yonathan/src/repository"
+	"backend-yonathan/src/repository/memory"
+)
+
+func TestScopesTagAliasesByTenant(t *testing.T) {
+	inner := memory.NewTagAliasRepository()
+	_ = inner.SaveAlias(context.Background(), "golang", "go")
+	repo := NewTagAliasRepository(inner, "ana")
+	ana := repository.WithTenant(context.Background(), "ana")
+	bob := repository.WithTenant(context.Background(), "bob")
+
+	if err := repo.SaveAlias(bob, "js", "javascript"); err != nil {
+		t.Fatal(err)
+	}
+	if aliases, _ := repo.ListAliases(ana); len(aliases) != 1 || aliases["golang"] != "go" {
+		t.Fatalf("ana aliases = %v, want only the legacy alias", aliases)
+	}
+	if aliases, _ := repo.ListAliases(context.Background()); len(aliases) != 1 || aliases["golang"] != "go" {
+		t.Fatalf("aliases without tenant = %v, want the default tenant", aliases)
+	}
+	if aliases, _ := repo.ListAliases(bob); len(aliases) != 1 || aliases["js"] != "javascript" {
+		t.Fatalf("bob aliases = %v", aliases)
+	}
+	if err := repo.DeleteAlias(bob, "golang"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("DeleteAlias of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteAlias(ana, "js"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("DeleteAlias of another tenant = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteAlias(bob, "js"); err != nil {
+		t.Fatal(err)
+	}
+}
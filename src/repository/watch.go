@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	models "backend-yonathan/src/models"
+)
+
+// ChangeKind identifies what happened to an experience.
+type ChangeKind string
+
+// Change kinds reported by ExperienceWatcher.
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeUpdated ChangeKind = "updated"
+	ChangeDeleted ChangeKind = "deleted"
+)
+
+// ExperienceChange is one change observed in the store. For deletes,
+// Experience holds the last known content. Previous is the content before
+// an update or delete, or nil when the watcher did not know it.
+type ExperienceChange struct {
+	Kind       ChangeKind
+	Experience models.Experience
+	Previous   *models.Experience
+}
+
+// ExperienceWatcher is implemented by experience repositories that can
+// report writes made by any process, such as Firestore snapshot listeners.
+type ExperienceWatcher interface {
+	// WatchExperiences calls fn for every change after the current state,
+	// which is not reported, until ctx is cancelled or the watch fails. Calls
+	// to fn are sequential.
+	WatchExperiences(ctx context.Context, fn func(ExperienceChange)) error
+}